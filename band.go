@@ -134,7 +134,7 @@ func NewBandDense(r, c, kl, ku int, data []float32) *BandDense {
 	}
 	bc := kl + ku + 1
 	if data != nil && len(data) != min(r, c+kl)*bc {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{len(data), 1}, Want: [2]int{min(r, c+kl) * bc, 1}})
 	}
 	if data == nil {
 		data = make([]float32, min(r, c+kl)*bc)
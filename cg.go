@@ -0,0 +1,78 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import "github.com/chewxy/math32"
+
+// CG solves the system of equations
+//  A * x = b
+// for symmetric positive definite A using the method of conjugate gradients,
+// placing x into dst. A is only accessed through matrix-vector products, so
+// CG may be used with any Matrix implementation, and is most effective when
+// those products are cheap. The iteration starts from x = 0 and stops when
+// the relative residual ‖b - A*x‖₂ / ‖b‖₂ is at most tol, or after maxIter
+// iterations. If maxIter is not positive, the dimension of A is used.
+//
+// CG returns the number of iterations performed and the relative residual
+// of the returned solution. It returns ErrNotConverged if the tolerance was
+// not reached within maxIter iterations, and ErrNotPSD if a direction of
+// non-positive curvature is found, indicating that A is not positive
+// definite. In both cases dst holds the last iterate.
+//
+// CG panics with ErrSquare if A is not square and with ErrShape if the
+// length of b does not match the dimension of A. If dst is empty it is
+// resized to the length of b, otherwise it must have that length.
+func CG(dst *VecDense, a Matrix, b Vector, tol float32, maxIter int) (iters int, resid float32, err error) {
+	n, c := a.Dims()
+	if n != c {
+		panic(ErrSquare)
+	}
+	if b.Len() != n {
+		panic(ShapeError{Got: [2]int{b.Len(), 1}, Want: [2]int{n, 1}})
+	}
+	if maxIter <= 0 {
+		maxIter = n
+	}
+
+	r := getWorkspaceVec(n, false)
+	defer putWorkspaceVec(r)
+	r.CopyVec(b)
+	dst.reuseAs(n)
+	for i := 0; i < n; i++ {
+		dst.setVec(i, 0)
+	}
+
+	bnorm := math32.Sqrt(Dot(r, r))
+	if bnorm == 0 {
+		return 0, 0, nil
+	}
+
+	p := getWorkspaceVec(n, false)
+	defer putWorkspaceVec(p)
+	ap := getWorkspaceVec(n, false)
+	defer putWorkspaceVec(ap)
+	p.CopyVec(r)
+	rr := Dot(r, r)
+	for iters = 0; iters < maxIter; {
+		ap.MulVec(a, p)
+		pap := Dot(p, ap)
+		if pap <= 0 {
+			return iters, math32.Sqrt(rr) / bnorm, ErrNotPSD
+		}
+		alpha := rr / pap
+		dst.AddScaledVec(dst, alpha, p)
+		r.AddScaledVec(r, -alpha, ap)
+		iters++
+
+		rrNew := Dot(r, r)
+		resid = math32.Sqrt(rrNew) / bnorm
+		if resid <= tol {
+			return iters, resid, nil
+		}
+		p.AddScaledVec(r, rrNew/rr, p)
+		rr = rrNew
+	}
+	return iters, resid, ErrNotConverged
+}
@@ -0,0 +1,108 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestCG(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i, test := range []struct {
+		a *SymDense
+		b []float32
+	}{
+		{
+			a: NewSymDense(1, []float32{2}),
+			b: []float32{3},
+		},
+		{
+			a: NewSymDense(3, []float32{
+				4, 1, 0,
+				1, 3, 1,
+				0, 1, 2,
+			}),
+			b: []float32{1, 2, 3},
+		},
+		{
+			a: NewSymDense(4, []float32{
+				10, 1, 2, 0,
+				1, 8, 0, 1,
+				2, 0, 6, 1,
+				0, 1, 1, 5,
+			}),
+			b: []float32{-1, 0, 4, 2},
+		},
+	} {
+		n := test.a.Symmetric()
+		b := NewVecDense(n, test.b)
+
+		var inv Dense
+		err := inv.Inverse(test.a)
+		if err != nil {
+			t.Fatalf("unexpected error from direct solve for test %d: %v", i, err)
+		}
+		var want VecDense
+		want.MulVec(&inv, b)
+
+		var got VecDense
+		iters, resid, err := CG(&got, test.a, b, 1e-6, 0)
+		if err != nil {
+			t.Errorf("unexpected error for test %d: %v", i, err)
+			continue
+		}
+		if iters > n+1 {
+			t.Errorf("unexpected iteration count for test %d: got: %d want: <= %d", i, iters, n+1)
+		}
+		if resid > 1e-6 {
+			t.Errorf("unexpected residual for test %d: got: %v", i, resid)
+		}
+		if !EqualApprox(&got, &want, 1e-5) {
+			t.Errorf("unexpected result for test %d: got: %v want: %v", i, got.RawVector().Data, want.RawVector().Data)
+		}
+	}
+
+	// A random well-conditioned system given as a Dense.
+	const n = 30
+	x := randNormDense(n, n, rnd)
+	var a Dense
+	a.Mul(x.T(), x)
+	for i := 0; i < n; i++ {
+		a.Set(i, i, a.At(i, i)+float32(n))
+	}
+	b := NewVecDense(n, randSlice(n, rnd))
+	var got, ax VecDense
+	_, _, err := CG(&got, &a, b, 1e-6, 5*n)
+	if err != nil {
+		t.Errorf("unexpected error for random system: %v", err)
+	}
+	ax.MulVec(&a, &got)
+	if !EqualApprox(&ax, b, 1e-4) {
+		t.Errorf("unexpected residual for random system")
+	}
+
+	// Zero right-hand side.
+	got.Reset()
+	iters, resid, err := CG(&got, &a, NewVecDense(n, nil), 1e-6, 0)
+	if iters != 0 || resid != 0 || err != nil {
+		t.Errorf("unexpected result for zero b: iters=%d resid=%v err=%v", iters, resid, err)
+	}
+
+	// Too few iterations.
+	got.Reset()
+	_, _, err = CG(&got, &a, b, 1e-6, 1)
+	if err != ErrNotConverged {
+		t.Errorf("unexpected error for truncated iteration: got: %v want: %v", err, ErrNotConverged)
+	}
+
+	// Indefinite matrix.
+	got.Reset()
+	_, _, err = CG(&got, NewSymDense(2, []float32{1, 0, 0, -1}), NewVecDense(2, []float32{0, 1}), 1e-6, 0)
+	if err != ErrNotPSD {
+		t.Errorf("unexpected error for indefinite matrix: got: %v want: %v", err, ErrNotPSD)
+	}
+}
@@ -5,6 +5,10 @@
 package mat32
 
 import (
+	"math"
+
+	"github.com/chewxy/math32"
+
 	"gonum.org/v1/gonum/blas"
 	"gonum.org/v1/gonum/blas/blas32"
 )
@@ -40,13 +44,14 @@ type Dense struct {
 // will be reflected in data. If neither of these is true, NewDense will panic.
 //
 // The data must be arranged in row-major order, i.e. the (i*c + j)-th
-// element in the data slice is the {i, j}-th element in the matrix.
+// element in the data slice is the {i, j}-th element in the matrix. Use
+// NewDenseColMajor for data in column-major order.
 func NewDense(r, c int, data []float32) *Dense {
 	if r < 0 || c < 0 {
 		panic("mat: negative dimension")
 	}
 	if data != nil && r*c != len(data) {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{len(data), 1}, Want: [2]int{r * c, 1}})
 	}
 	if data == nil {
 		data = make([]float32, r*c)
@@ -63,6 +68,58 @@ func NewDense(r, c int, data []float32) *Dense {
 	}
 }
 
+// NewDenseColMajor creates a new Dense matrix with r rows and c columns
+// from data arranged in column-major order, as used by Fortran and by
+// NumPy arrays with order='F', i.e. the (j*r + i)-th element in the data
+// slice is the {i, j}-th element in the matrix. Since Dense stores its
+// elements in row-major order, the data is copied into a newly allocated
+// backing slice and changes to the returned Dense are not reflected in
+// data. If data == nil a zero matrix is returned. NewDenseColMajor will
+// panic if data is non-nil and len(data) != r*c.
+func NewDenseColMajor(r, c int, data []float32) *Dense {
+	m := NewDense(r, c, nil)
+	if data == nil {
+		return m
+	}
+	if r*c != len(data) {
+		panic(ShapeError{Got: [2]int{len(data), 1}, Want: [2]int{r * c, 1}})
+	}
+	for j := 0; j < c; j++ {
+		blas32.Copy(r,
+			blas32.Vector{Inc: 1, Data: data[j*r:]},
+			blas32.Vector{Inc: m.mat.Stride, Data: m.mat.Data[j:]})
+	}
+	return m
+}
+
+// NewDenseChecked is like NewDense but returns ErrShape instead of panicking
+// if r or c is negative, if r*c overflows int or if data is non-nil and
+// len(data) != r*c.
+func NewDenseChecked(r, c int, data []float32) (*Dense, error) {
+	if r < 0 || c < 0 {
+		return nil, ErrShape
+	}
+	if c != 0 && r > math.MaxInt/c {
+		return nil, ErrShape
+	}
+	if data != nil && r*c != len(data) {
+		return nil, ErrShape
+	}
+	return NewDense(r, c, data), nil
+}
+
+// NewDenseFrom2D creates a new Dense from data, a slice of rows, copying
+// the elements into newly allocated storage. It returns ErrZeroLength if
+// data or its first row is empty and ErrRowLength if the rows do not all
+// have the same length.
+func NewDenseFrom2D(data [][]float32) (*Dense, error) {
+	var m Dense
+	if err := m.CopyFrom2D(data); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
 // reuseAs resizes an empty matrix to a r×c matrix,
 // or checks that a non-empty matrix is r×c.
 //
@@ -87,7 +144,7 @@ func (m *Dense) reuseAs(r, c int) {
 		return
 	}
 	if r != m.mat.Rows || c != m.mat.Cols {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{m.mat.Rows, m.mat.Cols}, Want: [2]int{r, c}})
 	}
 }
 
@@ -116,7 +173,7 @@ func (m *Dense) reuseAsZeroed(r, c int) {
 		return
 	}
 	if r != m.mat.Rows || c != m.mat.Cols {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{m.mat.Rows, m.mat.Cols}, Want: [2]int{r, c}})
 	}
 	for i := 0; i < r; i++ {
 		zero(m.mat.Data[i*m.mat.Stride : i*m.mat.Stride+c])
@@ -167,6 +224,77 @@ func (m *Dense) IsZero() bool {
 	return m.mat.Stride == 0
 }
 
+// IsFinite returns whether all elements of the receiver are finite, that
+// is neither NaN nor ±Inf. FirstNonFinite can be used to locate an element
+// that is not.
+func (m *Dense) IsFinite() bool {
+	for i := 0; i < m.mat.Rows; i++ {
+		for _, v := range m.mat.Data[i*m.mat.Stride : i*m.mat.Stride+m.mat.Cols] {
+			if !isFinite(v) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Sanitize replaces the non-finite elements of the receiver in place,
+// setting NaN elements to nan, +Inf elements to posInf and -Inf elements
+// to negInf. Finite elements are left unchanged.
+func (m *Dense) Sanitize(nan, posInf, negInf float32) {
+	for i := 0; i < m.mat.Rows; i++ {
+		row := m.mat.Data[i*m.mat.Stride : i*m.mat.Stride+m.mat.Cols]
+		for j, v := range row {
+			switch {
+			case math32.IsNaN(v):
+				row[j] = nan
+			case math32.IsInf(v, 1):
+				row[j] = posInf
+			case math32.IsInf(v, -1):
+				row[j] = negInf
+			}
+		}
+	}
+}
+
+// AsSymmetric returns a SymDense view of the receiver that shares its
+// backing data, so that changes to the elements of either are reflected in
+// the other. The view refers to the upper triangle of the receiver; the
+// lower triangle is ignored. AsSymmetric does not check that the receiver
+// is symmetric, which is the caller's responsibility. If it is not, the
+// view represents the symmetric matrix formed by reflecting the upper
+// triangle. AsSymmetric returns ErrSquare if the receiver is not square.
+func (m *Dense) AsSymmetric() (*SymDense, error) {
+	if m.mat.Rows != m.mat.Cols {
+		return nil, ErrSquare
+	}
+	return &SymDense{
+		mat: blas32.Symmetric{
+			N:      m.mat.Rows,
+			Stride: m.mat.Stride,
+			Data:   m.mat.Data,
+			Uplo:   blas.Upper,
+		},
+		cap: m.mat.Rows,
+	}, nil
+}
+
+// TriView returns a TriDense view of the upper or lower triangle of the
+// receiver, according to kind, that shares its backing data, so that
+// changes to the elements of the triangle are reflected in both. Elements
+// outside the triangle are ignored by the view. TriView returns ErrSquare
+// if the receiver is not square.
+func (m *Dense) TriView(kind TriKind) (*TriDense, error) {
+	if m.mat.Rows != m.mat.Cols {
+		return nil, ErrSquare
+	}
+	uplo := blas.Lower
+	if kind == Upper {
+		uplo = blas.Upper
+	}
+	return m.asTriDense(m.mat.Rows, blas.NonUnit, uplo), nil
+}
+
 // asTriDense returns a TriDense with the given size and side. The backing data
 // of the TriDense is the same as the receiver.
 func (m *Dense) asTriDense(n int, diag blas.Diag, uplo blas.Uplo) *TriDense {
@@ -202,6 +330,60 @@ func (m *Dense) SetRawMatrix(b blas32.General) {
 // in returned blas32.General.
 func (m *Dense) RawMatrix() blas32.General { return m.mat }
 
+// ToSlice returns a newly allocated slice holding the elements of the
+// receiver in contiguous row-major order. Unlike the data returned by
+// RawMatrix, the result does not include any elements outside the view
+// described by the receiver's stride.
+func (m *Dense) ToSlice() []float32 {
+	r, c := m.mat.Rows, m.mat.Cols
+	s := make([]float32, r*c)
+	for i := 0; i < r; i++ {
+		copy(s[i*c:i*c+c], m.rawRowView(i))
+	}
+	return s
+}
+
+// ToSlice2D returns the elements of the receiver as a newly allocated
+// slice of rows. The rows share a single contiguous backing array.
+func (m *Dense) ToSlice2D() [][]float32 {
+	r, c := m.mat.Rows, m.mat.Cols
+	s := m.ToSlice()
+	rows := make([][]float32, r)
+	for i := range rows {
+		rows[i] = s[i*c : i*c+c : i*c+c]
+	}
+	return rows
+}
+
+// CopyFrom2D copies the elements of data, a slice of rows, into the
+// receiver. It is the inverse of ToSlice2D. If the receiver is empty it is
+// resized to len(data)×len(data[0]).
+//
+// CopyFrom2D returns ErrZeroLength if data or its first row is empty,
+// ErrRowLength if the rows do not all have the same length, and ErrShape
+// if the receiver is non-empty and its dimensions do not match data. The
+// receiver is not modified when an error is returned.
+func (m *Dense) CopyFrom2D(data [][]float32) error {
+	if len(data) == 0 || len(data[0]) == 0 {
+		return ErrZeroLength
+	}
+	r, c := len(data), len(data[0])
+	for _, row := range data[1:] {
+		if len(row) != c {
+			return ErrRowLength
+		}
+	}
+	if !m.IsZero() && (m.mat.Rows != r || m.mat.Cols != c) {
+		return ErrShape
+	}
+
+	m.reuseAs(r, c)
+	for i, row := range data {
+		copy(m.mat.Data[i*m.mat.Stride:i*m.mat.Stride+c], row)
+	}
+	return nil
+}
+
 // Dims returns the number of rows and columns in the matrix.
 func (m *Dense) Dims() (r, c int) { return m.mat.Rows, m.mat.Cols }
 
@@ -261,6 +443,27 @@ func (m *Dense) RowView(i int) Vector {
 	return &v
 }
 
+// Rows calls yield for each row of the receiver in order, passing the row
+// index and a view of the row backed by the matrix data. Iteration stops
+// early if yield returns false. Rows has the signature of a range-over-func
+// iterator, so with Go 1.23 or later it may be used as
+//  for i, row := range m.Rows {
+//  	...
+//  }
+//
+// A single view is reused for all rows to avoid allocation, so the row is
+// only valid for the duration of the call to yield and must not be
+// retained.
+func (m *Dense) Rows(yield func(i int, row *VecDense) bool) {
+	var row VecDense
+	for i := 0; i < m.mat.Rows; i++ {
+		row.RowViewOf(m, i)
+		if !yield(i, &row) {
+			return
+		}
+	}
+}
+
 // RawRowView returns a slice backed by the same array as backing the
 // receiver.
 func (m *Dense) RawRowView(i int) []float32 {
@@ -359,9 +562,116 @@ func (m *Dense) Grow(r, c int) Matrix {
 	return &t
 }
 
+// AppendRow appends src as a new last row of the receiver. If the receiver
+// is empty, the first call sets the number of columns to len(src); later
+// calls panic with ErrRowLength if len(src) does not match the number of
+// columns. When the row capacity of the receiver is exhausted, the backing
+// storage is reallocated with double the row capacity, so a sequence of
+// appends takes amortized constant time per element.
+//
+// As with Grow, an append that fits within the capacity of the receiver
+// writes into the existing backing data, which may be shared with the
+// matrix the receiver was sliced from.
+func (m *Dense) AppendRow(src []float32) {
+	if m.IsZero() {
+		if len(src) == 0 {
+			panic(ErrZeroLength)
+		}
+		c := len(src)
+		m.mat = blas32.General{
+			Rows:   1,
+			Cols:   c,
+			Stride: c,
+			Data:   use(m.mat.Data, c),
+		}
+		m.capRows = 1
+		m.capCols = c
+		copy(m.mat.Data, src)
+		return
+	}
+	r, c := m.mat.Rows, m.mat.Cols
+	if len(src) != c {
+		panic(ErrRowLength)
+	}
+	if r == m.capRows {
+		cr := 2 * r
+		data := make([]float32, cr*c)
+		for i := 0; i < r; i++ {
+			copy(data[i*c:i*c+c], m.rawRowView(i))
+		}
+		m.mat = blas32.General{
+			Rows:   r,
+			Cols:   c,
+			Stride: c,
+			Data:   data,
+		}
+		m.capRows = cr
+		m.capCols = c
+	}
+	m.mat.Data = m.mat.Data[:r*m.mat.Stride+c]
+	m.mat.Rows = r + 1
+	copy(m.rawRowView(r), src)
+}
+
+// Reshape changes the dimensions of the receiver to r×c without copying,
+// reinterpreting its elements in row-major order. Reshape returns ErrShape
+// if r*c does not equal the number of elements in the receiver or either
+// dimension is not positive, and ErrIllegalStride if the receiver's elements
+// are not stored contiguously, as is the case for most views created by Slice.
+// After a successful reshape the capacity of the receiver is its new size.
+func (m *Dense) Reshape(r, c int) error {
+	// Compare by division first so that r*c cannot overflow.
+	n := m.mat.Rows * m.mat.Cols
+	if r <= 0 || c <= 0 || r > n/c || r*c != n {
+		return ErrShape
+	}
+	if !m.isContiguous() {
+		return ErrIllegalStride
+	}
+	m.mat = blas32.General{
+		Rows:   r,
+		Cols:   c,
+		Stride: c,
+		Data:   m.mat.Data[:r*c],
+	}
+	m.capRows = r
+	m.capCols = c
+	return nil
+}
+
+// Flatten returns a vector of length r*c that views the elements of the
+// receiver in row-major order. The vector shares storage with the receiver,
+// so changes through either are visible in the other. Flatten returns
+// ErrIllegalStride if the receiver's elements are not stored contiguously,
+// and ErrZeroLength if the receiver is empty.
+func (m *Dense) Flatten() (*VecDense, error) {
+	if m.IsZero() {
+		return nil, ErrZeroLength
+	}
+	if !m.isContiguous() {
+		return nil, ErrIllegalStride
+	}
+	n := m.mat.Rows * m.mat.Cols
+	return &VecDense{
+		mat: blas32.Vector{
+			Inc:  1,
+			Data: m.mat.Data[:n],
+		},
+		n: n,
+	}, nil
+}
+
+// isContiguous returns whether the elements of the receiver are stored
+// without gaps in row-major order.
+func (m *Dense) isContiguous() bool {
+	return m.mat.Stride == m.mat.Cols || m.mat.Rows <= 1
+}
+
 // Clone makes a copy of a into the receiver, overwriting the previous value of
 // the receiver. The clone operation does not make any restriction on shape and
-// will not cause shadowing.
+// will not cause shadowing. The receiver is given newly allocated storage sized
+// to the dimensions of a, so the logical contents of a transpose or slice view
+// are copied, not its backing layout.
 //
 // See the Cloner interface for more information.
 func (m *Dense) Clone(a Matrix) {
@@ -503,7 +813,10 @@ func (m *Dense) Copy(a Matrix) (r, c int) {
 func (m *Dense) Stack(a, b Matrix) {
 	ar, ac := a.Dims()
 	br, bc := b.Dims()
-	if ac != bc || m == a || m == b {
+	if ac != bc {
+		panic(ShapeError{Got: [2]int{br, bc}, Want: [2]int{br, ac}})
+	}
+	if m == a || m == b {
 		panic(ErrShape)
 	}
 
@@ -521,7 +834,10 @@ func (m *Dense) Stack(a, b Matrix) {
 func (m *Dense) Augment(a, b Matrix) {
 	ar, ac := a.Dims()
 	br, bc := b.Dims()
-	if ar != br || m == a || m == b {
+	if ar != br {
+		panic(ShapeError{Got: [2]int{br, bc}, Want: [2]int{ar, bc}})
+	}
+	if m == a || m == b {
 		panic(ErrShape)
 	}
 
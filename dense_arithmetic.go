@@ -5,6 +5,12 @@
 package mat32
 
 import (
+	"math"
+	"sync/atomic"
+
+	"github.com/chewxy/math32"
+
+	"github.com/arjunsk/mat32/internal/asm/f32"
 	"gonum.org/v1/gonum/blas"
 	"gonum.org/v1/gonum/blas/blas32"
 )
@@ -15,7 +21,7 @@ func (m *Dense) Add(a, b Matrix) {
 	ar, ac := a.Dims()
 	br, bc := b.Dims()
 	if ar != br || ac != bc {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{br, bc}, Want: [2]int{ar, ac}})
 	}
 
 	aU, _ := untranspose(a)
@@ -58,13 +64,62 @@ func (m *Dense) Add(a, b Matrix) {
 	}
 }
 
+// AddBroadcast adds a and b element-wise, placing the result in the
+// receiver, broadcasting b across a when their shapes differ. For an r×c
+// matrix a, b may be
+//  r×c: m[i,j] = a[i,j] + b[i,j]
+//  1×c: m[i,j] = a[i,j] + b[0,j]  (row broadcast)
+//  r×1: m[i,j] = a[i,j] + b[i,0]  (column broadcast)
+// AddBroadcast panics with ErrShape if b has any other shape.
+func (m *Dense) AddBroadcast(a, b Matrix) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if br == ar && bc == ac {
+		m.Add(a, b)
+		return
+	}
+	var rowBroadcast bool
+	switch {
+	case br == 1 && bc == ac:
+		rowBroadcast = true
+	case br == ar && bc == 1:
+	default:
+		panic(ShapeError{Got: [2]int{br, bc}, Want: [2]int{1, ac}})
+	}
+
+	// Take a copy of the broadcast operand so that
+	// the receiver may alias it.
+	bv := getFloats(max(br, bc), false)
+	defer putFloats(bv)
+	if rowBroadcast {
+		Row(bv, 0, b)
+	} else {
+		Col(bv, 0, b)
+	}
+
+	m.reuseAs(ar, ac)
+	if m != a {
+		m.Copy(a)
+	}
+	for i := 0; i < ar; i++ {
+		row := m.mat.Data[i*m.mat.Stride : i*m.mat.Stride+ac]
+		if rowBroadcast {
+			f32.AxpyUnitary(1, bv, row)
+			continue
+		}
+		for j := range row {
+			row[j] += bv[i]
+		}
+	}
+}
+
 // Sub subtracts the matrix b from a, placing the result in the receiver. Sub
 // will panic if the two matrices do not have the same shape.
 func (m *Dense) Sub(a, b Matrix) {
 	ar, ac := a.Dims()
 	br, bc := b.Dims()
 	if ar != br || ac != bc {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{br, bc}, Want: [2]int{ar, ac}})
 	}
 
 	aU, _ := untranspose(a)
@@ -107,6 +162,54 @@ func (m *Dense) Sub(a, b Matrix) {
 	}
 }
 
+// AddSubMatrix adds a element-wise into the block of the receiver whose top-left
+// element is at row i, column j. AddSubMatrix will panic with ErrIndexOutOfRange
+// if the block extends beyond the dimensions of the receiver.
+func (m *Dense) AddSubMatrix(i, j int, a Matrix) {
+	ar, ac := a.Dims()
+	if i < 0 || j < 0 || m.mat.Rows < i+ar || m.mat.Cols < j+ac {
+		panic(ErrIndexOutOfRange)
+	}
+	if ar == 0 || ac == 0 {
+		return
+	}
+
+	aU, aTrans := untranspose(a)
+	if m == aU {
+		w := getWorkspace(ar, ac, false)
+		w.Copy(a)
+		defer putWorkspace(w)
+		aU, aTrans = w, false
+	}
+	block := blas32.General{
+		Rows:   ar,
+		Cols:   ac,
+		Stride: m.mat.Stride,
+		Data:   m.mat.Data[i*m.mat.Stride+j : (i+ar-1)*m.mat.Stride+j+ac],
+	}
+
+	if rm, ok := aU.(RawMatrixer); ok {
+		amat := rm.RawMatrix()
+		checkOverlap(block, amat)
+		for r := 0; r < ar; r++ {
+			dst := block.Data[r*block.Stride : r*block.Stride+ac]
+			if !aTrans {
+				f32.AxpyUnitary(1, amat.Data[r*amat.Stride:r*amat.Stride+ac], dst)
+				continue
+			}
+			f32.AxpyInc(1, amat.Data[r:], dst, uintptr(ac), uintptr(amat.Stride), 1, 0, 0)
+		}
+		return
+	}
+
+	(&Dense{mat: block}).checkOverlapMatrix(aU)
+	for r := 0; r < ar; r++ {
+		for c := 0; c < ac; c++ {
+			block.Data[r*block.Stride+c] += a.At(r, c)
+		}
+	}
+}
+
 // MulElem performs element-wise multiplication of a and b, placing the result
 // in the receiver. MulElem will panic if the two matrices do not have the same
 // shape.
@@ -114,7 +217,7 @@ func (m *Dense) MulElem(a, b Matrix) {
 	ar, ac := a.Dims()
 	br, bc := b.Dims()
 	if ar != br || ac != bc {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{br, bc}, Want: [2]int{ar, ac}})
 	}
 
 	aU, _ := untranspose(a)
@@ -164,7 +267,7 @@ func (m *Dense) DivElem(a, b Matrix) {
 	ar, ac := a.Dims()
 	br, bc := b.Dims()
 	if ar != br || ac != bc {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{br, bc}, Want: [2]int{ar, ac}})
 	}
 
 	aU, _ := untranspose(a)
@@ -214,7 +317,7 @@ func (m *Dense) Mul(a, b Matrix) {
 	br, bc := b.Dims()
 
 	if ac != br {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{br, bc}, Want: [2]int{ac, bc}})
 	}
 
 	aU, aTrans := untranspose(a)
@@ -255,6 +358,30 @@ func (m *Dense) Mul(a, b Matrix) {
 				panic("blas: index of c out of range")
 			}
 
+			// A product with a single column or row operand is a
+			// matrix-vector product, for which Gemv is cheaper.
+			switch {
+			case bc == 1:
+				// C = A * b for the column vector b.
+				x := blas32.Vector{Inc: bmat.Stride, Data: bmat.Data}
+				if bTrans {
+					x.Inc = 1
+				}
+				blas32.Gemv(aT, 1, amat, x, 0, blas32.Vector{Inc: m.mat.Stride, Data: m.mat.Data})
+				return
+			case ar == 1:
+				// C^T = B^T * a^T for the row vector a.
+				x := blas32.Vector{Inc: 1, Data: amat.Data}
+				if aTrans {
+					x.Inc = amat.Stride
+				}
+				t := blas.Trans
+				if bTrans {
+					t = blas.NoTrans
+				}
+				blas32.Gemv(t, 1, bmat, x, 0, blas32.Vector{Inc: 1, Data: m.mat.Data})
+				return
+			}
 			blas32.Gemm(aT, bT, 1, amat, bmat, 0, m.mat)
 			return
 		}
@@ -361,18 +488,188 @@ func (m *Dense) Mul(a, b Matrix) {
 
 	m.checkOverlapMatrix(aU)
 	m.checkOverlapMatrix(bU)
-	row := getFloats(ac, false)
-	defer putFloats(row)
-	for r := 0; r < ar; r++ {
-		for i := range row {
-			row[i] = a.At(r, i)
+	aw := getWorkspace(ar, ac, false)
+	defer putWorkspace(aw)
+	aw.Copy(a)
+	bw := getWorkspace(br, bc, false)
+	defer putWorkspace(bw)
+	bw.Copy(b)
+	mulBlocked(m.mat, aw.mat, bw.mat, int(atomic.LoadInt32(&mulBlockSize)))
+}
+
+// Gemm computes
+//  c = alpha * op(a) * op(b) + beta * c
+// where op(a) is aᵀ if transA is true and a otherwise, and op(b) is bᵀ if
+// transB is true and b otherwise. This is the BLAS level 3 general
+// matrix-matrix operation, dispatched to blas32.Gemm when a and b provide
+// raw storage. Unlike Mul, Gemm can accumulate into an existing c. As in
+// BLAS, when beta is zero c need not be initialized, so NaN elements of c do
+// not propagate to the result.
+//
+// If c is empty it is resized to the number of rows of op(a) by the number of
+// columns of op(b) and treated as zero. Gemm panics with ErrShape if the
+// number of columns of op(a) is not the number of rows of op(b), or if c is
+// non-empty and does not have the shape of the product.
+func Gemm(transA, transB bool, alpha float32, a, b Matrix, beta float32, c *Dense) {
+	ar, ac := a.Dims()
+	if transA {
+		ar, ac = ac, ar
+	}
+	br, bc := b.Dims()
+	if transB {
+		br, bc = bc, br
+	}
+	if ac != br {
+		panic(ShapeError{Got: [2]int{br, bc}, Want: [2]int{ac, bc}})
+	}
+	if c.IsZero() {
+		c.reuseAs(ar, bc)
+		beta = 0
+	} else if r, cc := c.Dims(); r != ar || cc != bc {
+		panic(ShapeError{Got: [2]int{r, cc}, Want: [2]int{ar, bc}})
+	}
+
+	aU, aTrans := untranspose(a)
+	bU, bTrans := untranspose(b)
+	if aUrm, ok := aU.(RawMatrixer); ok && c != aU && c != bU {
+		if bUrm, ok := bU.(RawMatrixer); ok {
+			amat := aUrm.RawMatrix()
+			bmat := bUrm.RawMatrix()
+			c.checkOverlap(amat)
+			c.checkOverlap(bmat)
+			tA := blas.NoTrans
+			if transA != aTrans {
+				tA = blas.Trans
+			}
+			tB := blas.NoTrans
+			if transB != bTrans {
+				tB = blas.Trans
+			}
+			blas32.Gemm(tA, tB, alpha, amat, bmat, beta, c.mat)
+			return
+		}
+	}
+
+	// Form op(a) * op(b) before updating c, since c may be a or b.
+	if transA {
+		a = a.T()
+	}
+	if transB {
+		b = b.T()
+	}
+	ab := getWorkspace(ar, bc, false)
+	defer putWorkspace(ab)
+	ab.Mul(a, b)
+	for i := 0; i < ar; i++ {
+		for j, v := range ab.mat.Data[i*ab.mat.Stride : i*ab.mat.Stride+bc] {
+			if beta == 0 {
+				c.set(i, j, alpha*v)
+			} else {
+				c.set(i, j, alpha*v+beta*c.at(i, j))
+			}
 		}
-		for c := 0; c < bc; c++ {
-			var v float32
-			for i, e := range row {
-				v += e * b.At(i, c)
+	}
+}
+
+// mulBlockSize is the tile size used by mulBlocked in the fallback path of
+// Dense.Mul. The default of 64 keeps three float32 tiles comfortably within
+// a typical L1 or L2 cache. It is set by SetMulBlockSize.
+var mulBlockSize int32 = 64
+
+// maxMulBlockSize is the largest tile size accepted by SetMulBlockSize.
+const maxMulBlockSize = 4096
+
+// SetMulBlockSize sets the tile size used by Dense.Mul when it multiplies
+// operands that do not provide raw storage, and returns the previous size.
+// Larger tiles suit larger caches. The result of Mul does not depend on the
+// tile size. The default is 64.
+//
+// SetMulBlockSize panics with ErrIndexOutOfRange if n is less than 1 or
+// greater than 4096. It is safe to call concurrently with matrix operations.
+func SetMulBlockSize(n int) (prev int) {
+	if n < 1 || n > maxMulBlockSize {
+		panic(ErrIndexOutOfRange)
+	}
+	return int(atomic.SwapInt32(&mulBlockSize, int32(n)))
+}
+
+// mulBlocked computes c = a * b using square tiles of the given size so that
+// the working set of the innermost loops stays in cache. Each element of c
+// is accumulated over k in increasing order, so the result is identical to
+// that of the unblocked triple loop.
+func mulBlocked(c, a, b blas32.General, block int) {
+	m, n, p := a.Rows, a.Cols, b.Cols
+	for i := 0; i < m; i++ {
+		zero(c.Data[i*c.Stride : i*c.Stride+p])
+	}
+	for ii := 0; ii < m; ii += block {
+		iEnd := min(ii+block, m)
+		for kk := 0; kk < n; kk += block {
+			kEnd := min(kk+block, n)
+			for jj := 0; jj < p; jj += block {
+				jEnd := min(jj+block, p)
+				for i := ii; i < iEnd; i++ {
+					crow := c.Data[i*c.Stride+jj : i*c.Stride+jEnd]
+					for k := kk; k < kEnd; k++ {
+						aik := a.Data[i*a.Stride+k]
+						brow := b.Data[k*b.Stride+jj : k*b.Stride+jEnd]
+						for j, v := range brow {
+							crow[j] += aik * v
+						}
+					}
+				}
 			}
-			m.mat.Data[r*m.mat.Stride+c] = v
+		}
+	}
+}
+
+// Gram places the Gram matrix of a into the receiver. If trans is false the
+// receiver is set to
+//  m = A * A^T
+// the inner products of the rows of a, and if trans is true it is set to
+//  m = A^T * A
+// the inner products of the columns of a. Only one triangle of the
+// symmetric product is computed and the other is filled by reflection.
+//
+// If the receiver is empty it is resized to n×n where n is the number of
+// rows of a, or of columns when trans is true, otherwise it must be n×n.
+func (m *Dense) Gram(a Matrix, trans bool) {
+	ar, ac := a.Dims()
+	n := ar
+	t := blas.NoTrans
+	if trans {
+		n = ac
+		t = blas.Trans
+	}
+	m.reuseAs(n, n)
+
+	// Get a raw, untransposed view of a, copying if necessary.
+	var amat blas32.General
+	aU, aTrans := untranspose(a)
+	if rm, ok := aU.(RawMatrixer); ok && !aTrans {
+		amat = rm.RawMatrix()
+	} else {
+		w := getWorkspace(ar, ac, false)
+		defer putWorkspace(w)
+		w.Copy(a)
+		amat = w.mat
+	}
+	if m == aU || m.checkOverlap(amat) {
+		var restore func()
+		m, restore = m.isolatedWorkspace(m)
+		defer restore()
+	}
+
+	c := blas32.Symmetric{
+		N:      n,
+		Stride: m.mat.Stride,
+		Data:   m.mat.Data,
+		Uplo:   blas.Upper,
+	}
+	blas32.Syrk(t, 1, amat, 0, c)
+	for i := 1; i < n; i++ {
+		for j := 0; j < i; j++ {
+			m.mat.Data[i*m.mat.Stride+j] = m.mat.Data[j*m.mat.Stride+i]
 		}
 	}
 }
@@ -387,16 +684,189 @@ func strictCopy(m *Dense, a Matrix) {
 	}
 }
 
+// Exp calculates the exponential of the matrix a, e^a, placing the result
+// in the receiver. Exp will panic with ErrShape if a is not square.
+//
+// The exponential is computed by scaling and squaring with a diagonal Padé
+// approximant of degree 3, 5 or 7, chosen from the 1-norm of a using the
+// single precision thresholds of Higham (2005). When the 1-norm of a exceeds
+// 3.93, a is scaled by 2^-s where s = ceil(log2(‖a‖₁/3.93)), and the
+// approximant is squared s times. For matrices of modest norm the relative
+// error of the result is expected to be within a small multiple of float32
+// machine epsilon, about 1e-6, but it grows with s and with the condition
+// of the exponential, so results for matrices with large norm should be
+// treated with care.
+//
+// Exp returns a Condition error if the denominator of the Padé approximant
+// is singular or ill-conditioned, in which case the result is unreliable.
+func (m *Dense) Exp(a Matrix) error {
+	// The implementation used here is from Functions of Matrices: Theory and Computation
+	// Chapter 10, Algorithm 10.20. https://doi.org/10.1137/1.9780898717778.ch10
+
+	r, c := a.Dims()
+	if r != c {
+		panic(ErrShape)
+	}
+
+	m.reuseAs(r, r)
+	if r == 1 {
+		m.mat.Data[0] = math32.Exp(a.At(0, 0))
+		return nil
+	}
+
+	pade := []struct {
+		theta float32
+		b     []float32
+	}{
+		{theta: 0.4258730016922831, b: []float32{
+			120, 60, 12, 1,
+		}},
+		{theta: 1.880152677804762, b: []float32{
+			30240, 15120, 3360, 420, 30, 1,
+		}},
+		{theta: 3.925724783138660, b: []float32{
+			17297280, 8648640, 1995840, 277200, 25200, 1512, 56, 1,
+		}},
+	}
+
+	a1 := getWorkspace(r, r, false)
+	defer putWorkspace(a1)
+	a1.Copy(a)
+
+	// Choose the lowest degree approximant that is accurate for
+	// the norm of a, scaling a when even the highest is not.
+	n1 := Norm(a1, 1)
+	deg := len(pade) - 1
+	for i, t := range pade {
+		if n1 <= t.theta {
+			deg = i
+			break
+		}
+	}
+	var s int
+	if theta := pade[deg].theta; n1 > theta {
+		s = int(math32.Ceil(math32.Log2(n1 / theta)))
+		a1.Scale(1/math32.Pow(2, float32(s)), a1)
+	}
+	t := pade[deg]
+
+	n := r * r
+	v := getWorkspace(r, r, true)
+	defer putWorkspace(v)
+	u := getWorkspace(r, r, true)
+	defer putWorkspace(u)
+	p := getWorkspace(r, r, true)
+	defer putWorkspace(p)
+	a2 := getWorkspace(r, r, false)
+	defer putWorkspace(a2)
+
+	for k := 0; k < r; k++ {
+		p.set(k, k, 1)
+		v.set(k, k, t.b[0])
+		u.set(k, k, t.b[1])
+	}
+	a2.Mul(a1, a1)
+	for j := 0; j <= deg; j++ {
+		p.Mul(p, a2)
+		f32.AxpyUnitary(t.b[2*j+2], p.mat.Data[:n], v.mat.Data[:n])
+		f32.AxpyUnitary(t.b[2*j+3], p.mat.Data[:n], u.mat.Data[:n])
+	}
+	a2.Mul(a1, u)
+
+	// Solve (V - U) * R = (V + U) for the approximant R.
+	vmu, vpu := u, p
+	vpu.Add(v, a2)
+	vmu.Sub(v, a2)
+	var lu LU
+	lu.Factorize(vmu)
+	if math32.IsInf(lu.cond, 1) {
+		return Condition(lu.cond)
+	}
+	lu.solveDenseInPlace(vpu, false)
+
+	// Undo the scaling by repeated squaring.
+	for ; s > 0; s-- {
+		a2.Mul(vpu, vpu)
+		vpu.Copy(a2)
+	}
+	m.Copy(vpu)
+	if lu.cond > ConditionTolerance {
+		return Condition(lu.cond)
+	}
+	return nil
+}
+
+// Sqrt calculates the principal square root of the symmetric positive
+// semi-definite matrix a, placing the result in the receiver. The square root
+// is the unique symmetric positive semi-definite matrix X such that
+//  X * X = a
+// and is computed from the eigendecomposition a = P * D * P^T as
+//  X = P * sqrt(D) * P^T
+//
+// Sqrt returns ErrNotPSD if a has an eigenvalue that is negative beyond
+// rounding error, and ErrFailedEigen if the eigendecomposition does not
+// converge. Eigenvalues within rounding error of zero are treated as zero.
+// The receiver is not modified when an error is returned.
+func (m *Dense) Sqrt(a Symmetric) error {
+	n := a.Symmetric()
+	var eig EigenSym
+	if !eig.Factorize(a, 0) {
+		return ErrFailedEigen
+	}
+	values := eig.Values(nil)
+
+	// The eigenvalues are in ascending order, so the last has the
+	// largest magnitude unless a is negative definite.
+	tol := float32(n) * epsilon32 * math32.Max(math32.Abs(values[0]), math32.Abs(values[n-1]))
+	if values[0] < -tol {
+		return ErrNotPSD
+	}
+
+	p := getWorkspace(n, n, false)
+	defer putWorkspace(p)
+	eig.VectorsTo(p)
+	ps := getWorkspace(n, n, false)
+	defer putWorkspace(ps)
+	ps.Copy(p)
+	for j, v := range values {
+		if v < 0 {
+			v = 0
+		}
+		f32.ScalInc(math32.Sqrt(v), ps.mat.Data[j:], uintptr(n), uintptr(ps.mat.Stride))
+	}
+	m.Mul(ps, p.T())
+	return nil
+}
+
 // Pow calculates the integral power of the matrix a to n, placing the result
-// in the receiver. Pow will panic if n is negative or if a is not square.
+// in the receiver. The power is computed by repeated squaring, taking
+// O(log n) matrix multiplications. A negative n raises the inverse of a to
+// -n; no check is made that a is well-conditioned, but Pow will panic with
+// ErrSingular if n is negative and a is exactly singular. Pow will panic if
+// a is not square.
 func (m *Dense) Pow(a Matrix, n int) {
-	if n < 0 {
-		panic("matrix: illegal power")
-	}
 	r, c := a.Dims()
 	if r != c {
 		panic(ErrShape)
 	}
+	if n < 0 {
+		inv := getWorkspace(r, r, false)
+		defer putWorkspace(inv)
+		if err := inv.Inverse(a); err != nil {
+			if cond, ok := err.(Condition); ok && math32.IsInf(float32(cond), 1) {
+				panic(ErrSingular)
+			}
+		}
+		if n == math.MinInt {
+			// The negation of n overflows, so
+			// use a^n = (a^-1)^-(n+1) * a^-1.
+			m.Pow(inv, -(n + 1))
+			m.Mul(m, inv)
+			return
+		}
+		m.Pow(inv, -n)
+		return
+	}
 
 	m.reuseAs(r, c)
 
@@ -438,6 +908,309 @@ func (m *Dense) Pow(a Matrix, n int) {
 	putWorkspace(x)
 }
 
+// Cond returns an estimate of the condition number of the receiver,
+//  |A| * |A^-1|
+// in the 1-norm if norm is 1 or in the infinity-norm if norm is +Inf.
+// The estimate is computed from an LU factorization of the receiver and
+// is +Inf if the receiver is exactly singular. Cond will panic with
+// ErrSquare if the receiver is not square and with ErrNormOrder for any
+// other norm.
+func (m *Dense) Cond(norm float32) float32 {
+	if norm != 1 && !math32.IsInf(norm, 1) {
+		panic(ErrNormOrder)
+	}
+	var lu LU
+	lu.factorize(m, norm)
+	return lu.cond
+}
+
+// Det returns the determinant of the receiver. Det overflows or underflows
+// for many large matrices, in which case LogDet should be used instead.
+// Det will panic with ErrSquare if the receiver is not square.
+func (m *Dense) Det() float32 {
+	var lu LU
+	lu.Factorize(m)
+	return lu.Det()
+}
+
+// LogDet returns the log of the absolute value of the determinant of the
+// receiver, along with its sign, so that
+//  det(m) = sign * exp(logAbsDet)
+// The result is computed from an LU factorization of the receiver, with the
+// sign tracked from the signs of the pivots and the row interchanges. LogDet
+// returns (-Inf, 0) if the receiver is singular, and will panic with
+// ErrSquare if the receiver is not square.
+func (m *Dense) LogDet() (logAbsDet float32, sign float32) {
+	var lu LU
+	lu.Factorize(m)
+	return lu.LogDet()
+}
+
+// Trace returns the trace of the matrix. The matrix must be square or Trace
+// will panic.
+func (m *Dense) Trace() float32 {
+	if m.mat.Rows != m.mat.Cols {
+		panic(ErrSquare)
+	}
+	var t float32
+	for i := 0; i < m.mat.Rows; i++ {
+		t += m.mat.Data[i*m.mat.Stride+i]
+	}
+	return t
+}
+
+// Min returns the smallest element of the receiver. As for Sum, the result
+// is NaN if any element is NaN. Min will panic with ErrZeroLength if the
+// receiver is empty.
+func (m *Dense) Min() float32 {
+	if m.IsZero() {
+		panic(ErrZeroLength)
+	}
+	min := math32.Inf(1)
+	for i := 0; i < m.mat.Rows; i++ {
+		for _, v := range m.mat.Data[i*m.mat.Stride : i*m.mat.Stride+m.mat.Cols] {
+			if math32.IsNaN(v) {
+				return v
+			}
+			if v < min {
+				min = v
+			}
+		}
+	}
+	return min
+}
+
+// Max returns the largest element of the receiver. As for Sum, the result
+// is NaN if any element is NaN. Max will panic with ErrZeroLength if the
+// receiver is empty.
+func (m *Dense) Max() float32 {
+	if m.IsZero() {
+		panic(ErrZeroLength)
+	}
+	max := math32.Inf(-1)
+	for i := 0; i < m.mat.Rows; i++ {
+		for _, v := range m.mat.Data[i*m.mat.Stride : i*m.mat.Stride+m.mat.Cols] {
+			if math32.IsNaN(v) {
+				return v
+			}
+			if v > max {
+				max = v
+			}
+		}
+	}
+	return max
+}
+
+// Sum returns the sum of the elements of the receiver. Sum will panic with
+// ErrZeroLength if the receiver is empty.
+func (m *Dense) Sum() float32 {
+	if m.IsZero() {
+		panic(ErrZeroLength)
+	}
+	if m.mat.Stride == m.mat.Cols {
+		return f32.Sum(m.mat.Data[:m.mat.Rows*m.mat.Cols])
+	}
+	var sum float32
+	for i := 0; i < m.mat.Rows; i++ {
+		sum += f32.Sum(m.mat.Data[i*m.mat.Stride : i*m.mat.Stride+m.mat.Cols])
+	}
+	return sum
+}
+
+// Solve finds the matrix X that solves the linear system
+//  A * X = B
+// for the square matrix a, storing X into the receiver. The system is solved
+// using the LU decomposition of a with partial pivoting.
+//
+// If a is singular to working precision, a Condition error with a value of
+// +Inf is returned and the receiver is left unchanged. If a is
+// ill-conditioned, the best-effort solution is stored into the receiver and
+// a Condition error holding the estimated condition number is returned.
+// Solve panics with ErrSquare if a is not square and with ErrShape if the
+// number of rows of b does not match the dimension of a.
+func (m *Dense) Solve(a, b Matrix) error {
+	r, c := a.Dims()
+	if r != c {
+		panic(ErrSquare)
+	}
+	br, bc := b.Dims()
+	if br != r {
+		panic(ShapeError{Got: [2]int{br, bc}, Want: [2]int{r, bc}})
+	}
+	var lu LU
+	lu.Factorize(a)
+	return lu.Solve(m, false, b)
+}
+
+// Inverse computes the inverse of the matrix a, storing the result into the
+// receiver. If a is ill-conditioned, a Condition error will be returned.
+// Note that matrix inversion is numerically unstable, and should generally
+// be avoided where possible, for example by solving with an LU factorization.
+func (m *Dense) Inverse(a Matrix) error {
+	r, c := a.Dims()
+	if r != c {
+		panic(ErrSquare)
+	}
+	var lu LU
+	lu.Factorize(a)
+	if math32.IsInf(lu.cond, 1) {
+		return Condition(lu.cond)
+	}
+
+	m.reuseAs(r, r)
+	col := getFloats(r, false)
+	defer putFloats(col)
+	for j := 0; j < r; j++ {
+		zero(col)
+		col[j] = 1
+		lu.solveInPlace(col, false)
+		blas32.Copy(r, blas32.Vector{Inc: 1, Data: col}, blas32.Vector{Inc: m.mat.Stride, Data: m.mat.Data[j:]})
+	}
+	if lu.cond > ConditionTolerance {
+		return Condition(lu.cond)
+	}
+	return nil
+}
+
+// PseudoInverse computes the Moore-Penrose pseudo-inverse of the m×n matrix
+// a, storing the n×m result into the receiver. The pseudo-inverse is formed
+// from the thin singular value decomposition of a,
+//  A^+ = V * Σ^+ * U^T
+// where Σ^+ holds the reciprocals of the singular values greater than tol
+// and zero in place of the remaining singular values. If tol is not
+// positive, a default of max(m, n) * eps * σ_max is used, where eps is the
+// float32 machine epsilon and σ_max is the largest singular value.
+//
+// PseudoInverse returns ErrFailedEigen if the singular value decomposition
+// does not converge.
+func (m *Dense) PseudoInverse(a Matrix, tol float32) error {
+	ar, ac := a.Dims()
+	var svd SVD
+	if !svd.Factorize(a, SVDThin) {
+		return ErrFailedEigen
+	}
+	s := svd.Values(nil)
+	if tol <= 0 {
+		tol = float32(max(ar, ac)) * epsilon32 * s[0]
+	}
+
+	v := svd.VTo(nil)
+	for j, sv := range s {
+		col := v.mat.Data[j:]
+		if sv > tol {
+			f32.ScalInc(1/sv, col, uintptr(ac), uintptr(v.mat.Stride))
+		} else {
+			for i := 0; i < ac; i++ {
+				col[i*v.mat.Stride] = 0
+			}
+		}
+	}
+	u := svd.UTo(nil)
+	m.reuseAs(ac, ar)
+	m.Mul(v, u.T())
+	return nil
+}
+
+// GramSchmidt places into the receiver an orthonormal basis for the column
+// space of the r×c matrix a, computed by modified Gram-Schmidt
+// orthogonalization of the columns of a in order. The receiver is r×c with
+// orthonormal columns Q such that a = Q * R for an upper triangular R.
+// Modified Gram-Schmidt projects each column out of the remaining columns
+// as soon as it is normalized, which retains much better orthogonality in
+// float32 than the classical method. The loss of orthogonality is still
+// proportional to the condition number of a, so nearly dependent columns
+// give a Q that is only approximately orthonormal.
+//
+// GramSchmidt returns ErrSingular if the columns of a are linearly
+// dependent, which is detected when the norm of a column after projection
+// is less than 1e-5 times its original norm. In that case the contents of
+// the receiver are unspecified.
+func (m *Dense) GramSchmidt(a Matrix) error {
+	r, c := a.Dims()
+	if c > r {
+		return ErrSingular
+	}
+	m.reuseAs(r, c)
+
+	// The columns of a are held as the rows of w
+	// so that they are contiguous.
+	w := getWorkspace(c, r, false)
+	defer putWorkspace(w)
+	w.Copy(a.T())
+	orig := getFloats(c, false)
+	defer putFloats(orig)
+	for k := range orig {
+		row := w.mat.Data[k*r : (k+1)*r]
+		orig[k] = blas32.Nrm2(r, blas32.Vector{Inc: 1, Data: row})
+	}
+	for k := 0; k < c; k++ {
+		qk := w.mat.Data[k*r : (k+1)*r]
+		norm := blas32.Nrm2(r, blas32.Vector{Inc: 1, Data: qk})
+		if norm == 0 || norm < 1e-5*orig[k] {
+			return ErrSingular
+		}
+		f32.ScalUnitary(1/norm, qk)
+		for j := k + 1; j < c; j++ {
+			qj := w.mat.Data[j*r : (j+1)*r]
+			f32.AxpyUnitary(-f32.DotUnitary(qk, qj), qk, qj)
+		}
+	}
+	m.Copy(w.T())
+	return nil
+}
+
+// Rank returns the numerical rank of the receiver. The rank is computed by
+// Gaussian elimination with complete pivoting, counting the pivots whose
+// magnitude exceeds tol. If tol is not positive, a default of
+//  max(r, c) * eps * max_ij |m[i,j]|
+// is used, where eps is the float32 machine epsilon.
+func (m *Dense) Rank(tol float32) int {
+	r, c := m.Dims()
+	if r == 0 || c == 0 {
+		return 0
+	}
+	w := getWorkspace(r, c, false)
+	defer putWorkspace(w)
+	w.Copy(m)
+
+	if tol <= 0 {
+		var maxAbs float32
+		for _, v := range w.mat.Data {
+			maxAbs = math32.Max(maxAbs, math32.Abs(v))
+		}
+		tol = float32(max(r, c)) * epsilon32 * maxAbs
+	}
+
+	n := min(r, c)
+	for k := 0; k < n; k++ {
+		// Find the largest remaining element.
+		pi, pj := k, k
+		var pivot float32
+		for i := k; i < r; i++ {
+			for j, v := range w.mat.Data[i*c+k : i*c+c] {
+				if v := math32.Abs(v); v > pivot {
+					pivot, pi, pj = v, i, j+k
+				}
+			}
+		}
+		if pivot <= tol {
+			return k
+		}
+		if pi != k {
+			blas32.Swap(c, blas32.Vector{Inc: 1, Data: w.mat.Data[pi*c:]}, blas32.Vector{Inc: 1, Data: w.mat.Data[k*c:]})
+		}
+		if pj != k {
+			blas32.Swap(r, blas32.Vector{Inc: c, Data: w.mat.Data[pj:]}, blas32.Vector{Inc: c, Data: w.mat.Data[k:]})
+		}
+		rowK := w.mat.Data[k*c+k : k*c+c]
+		for i := k + 1; i < r; i++ {
+			rowI := w.mat.Data[i*c+k : i*c+c]
+			f32.AxpyUnitary(-rowI[0]/rowK[0], rowK, rowI)
+		}
+	}
+	return n
+}
+
 // Scale multiplies the elements of a by f, placing the result in the receiver.
 //
 // See the Scaler interface for more information.
@@ -478,6 +1251,200 @@ func (m *Dense) Scale(f float32, a Matrix) {
 	}
 }
 
+// Abs computes the absolute value of each element of a, placing the result
+// in the receiver. The sign bit is cleared, so -0 becomes +0 and NaN
+// elements remain NaN.
+func (m *Dense) Abs(a Matrix) {
+	ar, ac := a.Dims()
+
+	m.reuseAs(ar, ac)
+
+	aU, aTrans := untranspose(a)
+	if rm, ok := aU.(RawMatrixer); ok {
+		amat := rm.RawMatrix()
+		if m == aU || m.checkOverlap(amat) {
+			var restore func()
+			m, restore = m.isolatedWorkspace(a)
+			defer restore()
+		}
+		if !aTrans {
+			for ja, jm := 0, 0; ja < ar*amat.Stride; ja, jm = ja+amat.Stride, jm+m.mat.Stride {
+				for i, v := range amat.Data[ja : ja+ac] {
+					m.mat.Data[i+jm] = math32.Abs(v)
+				}
+			}
+		} else {
+			for ja, jm := 0, 0; ja < ac*amat.Stride; ja, jm = ja+amat.Stride, jm+1 {
+				for i, v := range amat.Data[ja : ja+ar] {
+					m.mat.Data[i*m.mat.Stride+jm] = math32.Abs(v)
+				}
+			}
+		}
+		return
+	}
+
+	m.checkOverlapMatrix(a)
+	for r := 0; r < ar; r++ {
+		for c := 0; c < ac; c++ {
+			m.set(r, c, math32.Abs(a.At(r, c)))
+		}
+	}
+}
+
+// Sign computes the sign of each element of a, placing the result in the
+// receiver. Positive elements map to 1, negative elements to -1 and both
+// +0 and -0 to 0. NaN elements remain NaN.
+func (m *Dense) Sign(a Matrix) {
+	m.Apply(func(_, _ int, v float32) float32 { return sign(v) }, a)
+}
+
+// sign returns the signum of v, with sign(±0) = 0 and sign(NaN) = NaN.
+func sign(v float32) float32 {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	case v == 0:
+		return 0
+	}
+	return v
+}
+
+// ScaleRows multiplies row i of a by d[i], placing the result in the
+// receiver. This is equivalent to, but cheaper than, Diag(d) * a.
+// ScaleRows panics with ErrShape if the length of d is not the number of
+// rows of a.
+func (m *Dense) ScaleRows(a Matrix, d Vector) {
+	r, c := a.Dims()
+	if d.Len() != r {
+		panic(ShapeError{Got: [2]int{d.Len(), 1}, Want: [2]int{r, 1}})
+	}
+	f := getFloats(r, false)
+	defer putFloats(f)
+	for i := range f {
+		f[i] = d.AtVec(i)
+	}
+
+	m.reuseAs(r, c)
+	m.Copy(a)
+	for i, v := range f {
+		f32.ScalUnitary(v, m.mat.Data[i*m.mat.Stride:i*m.mat.Stride+c])
+	}
+}
+
+// ScaleCols multiplies column j of a by d[j], placing the result in the
+// receiver. This is equivalent to, but cheaper than, a * Diag(d).
+// ScaleCols panics with ErrShape if the length of d is not the number of
+// columns of a.
+func (m *Dense) ScaleCols(a Matrix, d Vector) {
+	r, c := a.Dims()
+	if d.Len() != c {
+		panic(ShapeError{Got: [2]int{d.Len(), 1}, Want: [2]int{c, 1}})
+	}
+	f := getFloats(c, false)
+	defer putFloats(f)
+	for j := range f {
+		f[j] = d.AtVec(j)
+	}
+
+	m.reuseAs(r, c)
+	m.Copy(a)
+	for i := 0; i < r; i++ {
+		row := m.mat.Data[i*m.mat.Stride : i*m.mat.Stride+c]
+		for j, v := range f {
+			row[j] *= v
+		}
+	}
+}
+
+// NormalizeRowsL2 scales each row of the receiver in place to have unit
+// Euclidean norm. Rows with zero norm are left unchanged.
+func (m *Dense) NormalizeRowsL2() {
+	r, c := m.Dims()
+	for i := 0; i < r; i++ {
+		row := m.mat.Data[i*m.mat.Stride : i*m.mat.Stride+c]
+		norm := blas32.Nrm2(c, blas32.Vector{Inc: 1, Data: row})
+		if norm == 0 {
+			continue
+		}
+		f32.ScalUnitary(1/norm, row)
+	}
+}
+
+// NormalizeColsL2 scales each column of the receiver in place to have unit
+// Euclidean norm. Columns with zero norm are left unchanged.
+func (m *Dense) NormalizeColsL2() {
+	r, c := m.Dims()
+	for j := 0; j < c; j++ {
+		col := m.mat.Data[j:]
+		norm := blas32.Nrm2(r, blas32.Vector{Inc: m.mat.Stride, Data: col})
+		if norm == 0 {
+			continue
+		}
+		f32.ScalInc(1/norm, col, uintptr(r), uintptr(m.mat.Stride))
+	}
+}
+
+// Normalize applies the per-row preprocessing appropriate to metric to the
+// receiver in place, so that a database matrix and the queries compared
+// against it can be transformed consistently. For Cosine, each row is scaled
+// to unit Euclidean norm, after which the cosine distance between rows is
+// one minus their inner product. All other metrics compare the raw values
+// and Normalize leaves the receiver unchanged. Normalize panics with
+// ErrMetric if metric is not a known Metric.
+func (m *Dense) Normalize(metric Metric) {
+	switch metric {
+	case Cosine:
+		m.NormalizeRowsL2()
+	case L2, SquaredL2, L1, InnerProduct, Chebyshev:
+	default:
+		panic(ErrMetric)
+	}
+}
+
+// StandardizeCols places the column-wise z-scores of a into the receiver,
+//  m[i,j] = (a[i,j] - means[j]) / stds[j]
+// and returns the mean and population standard deviation of each column of
+// a, so that the same transform can be applied to other data. Columns with
+// zero variance are mapped to zero rather than NaN.
+func (m *Dense) StandardizeCols(a Matrix) (means, stds []float32) {
+	r, c := a.Dims()
+	m.reuseAs(r, c)
+	if m != a {
+		m.Copy(a)
+	}
+
+	means = make([]float32, c)
+	stds = make([]float32, c)
+	n := float32(r)
+	for j := 0; j < c; j++ {
+		col := m.mat.Data[j:]
+		var sum float32
+		for i := 0; i < r; i++ {
+			sum += col[i*m.mat.Stride]
+		}
+		mean := sum / n
+		var ss float32
+		for i := 0; i < r; i++ {
+			d := col[i*m.mat.Stride] - mean
+			ss += d * d
+		}
+		std := math32.Sqrt(ss / n)
+		means[j] = mean
+		stds[j] = std
+
+		inv := float32(0)
+		if std != 0 {
+			inv = 1 / std
+		}
+		for i := 0; i < r; i++ {
+			col[i*m.mat.Stride] = (col[i*m.mat.Stride] - mean) * inv
+		}
+	}
+	return means, stds
+}
+
 // Apply applies the function fn to each of the elements of a, placing the
 // resulting matrix in the receiver. The function fn takes a row/column
 // index and element value and returns some function of that tuple.
@@ -525,11 +1492,11 @@ func (m *Dense) RankOne(a Matrix, alpha float32, x, y Vector) {
 	ar, ac := a.Dims()
 	xr, xc := x.Dims()
 	if xr != ar || xc != 1 {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{xr, xc}, Want: [2]int{ar, 1}})
 	}
 	yr, yc := y.Dims()
 	if yr != ac || yc != 1 {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{yr, yc}, Want: [2]int{ac, 1}})
 	}
 
 	if a != m {
@@ -573,6 +1540,80 @@ func (m *Dense) RankOne(a Matrix, alpha float32, x, y Vector) {
 	}
 }
 
+// Symmetrize places the symmetric part of a,
+//  (a + aᵀ) / 2
+// into the receiver. The result is exactly symmetric and has the same
+// diagonal, and so the same trace, as a. Symmetrize is useful for removing
+// small asymmetries introduced by rounding error into matrices, such as
+// covariance matrices, that should be symmetric. Symmetrize will panic with
+// ErrSquare if a is not square.
+func (m *Dense) Symmetrize(a Matrix) {
+	m.halfSum(a, 1)
+}
+
+// SkewPart places the skew-symmetric part of a,
+//  (a - aᵀ) / 2
+// into the receiver. The result is exactly skew-symmetric with a zero
+// diagonal, and the sum of the results of Symmetrize and SkewPart is a.
+// SkewPart will panic with ErrSquare if a is not square.
+func (m *Dense) SkewPart(a Matrix) {
+	m.halfSum(a, -1)
+}
+
+// halfSum places (a + s*aᵀ) / 2 into the receiver, where s is 1 or -1.
+func (m *Dense) halfSum(a Matrix, s float32) {
+	r, c := a.Dims()
+	if r != c {
+		panic(ErrSquare)
+	}
+
+	m.reuseAs(r, c)
+
+	aU, aTrans := untranspose(a)
+	if rm, ok := aU.(RawMatrixer); ok {
+		amat := rm.RawMatrix()
+		// Each pair of elements is read before either is
+		// written, so the receiver may be a.
+		if m != aU && m.checkOverlap(amat) {
+			var restore func()
+			m, restore = m.isolatedWorkspace(a)
+			defer restore()
+		}
+		// The result for aᵀ is s times that for a.
+		f := float32(1)
+		if aTrans {
+			f = s
+		}
+		for i := 0; i < r; i++ {
+			if s == 1 {
+				m.mat.Data[i*m.mat.Stride+i] = amat.Data[i*amat.Stride+i]
+			} else {
+				m.mat.Data[i*m.mat.Stride+i] = 0
+			}
+			for j := i + 1; j < r; j++ {
+				v := f * (amat.Data[i*amat.Stride+j] + s*amat.Data[j*amat.Stride+i]) / 2
+				m.mat.Data[i*m.mat.Stride+j] = v
+				m.mat.Data[j*m.mat.Stride+i] = s * v
+			}
+		}
+		return
+	}
+
+	m.checkOverlapMatrix(a)
+	for i := 0; i < r; i++ {
+		if s == 1 {
+			m.set(i, i, a.At(i, i))
+		} else {
+			m.set(i, i, 0)
+		}
+		for j := i + 1; j < r; j++ {
+			v := (a.At(i, j) + s*a.At(j, i)) / 2
+			m.set(i, j, v)
+			m.set(j, i, s*v)
+		}
+	}
+}
+
 // Outer calculates the outer product of the column vectors x and y,
 // and stores the result in the receiver.
 //  m = alpha * x * y'
@@ -580,11 +1621,11 @@ func (m *Dense) RankOne(a Matrix, alpha float32, x, y Vector) {
 func (m *Dense) Outer(alpha float32, x, y Vector) {
 	xr, xc := x.Dims()
 	if xc != 1 {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{xr, xc}, Want: [2]int{xr, 1}})
 	}
 	yr, yc := y.Dims()
 	if yc != 1 {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{yr, yc}, Want: [2]int{yr, 1}})
 	}
 
 	r := xr
@@ -609,7 +1650,7 @@ func (m *Dense) Outer(alpha float32, x, y Vector) {
 		m.capRows = r
 		m.capCols = c
 	} else if r != m.mat.Rows || c != m.mat.Cols {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{m.mat.Rows, m.mat.Cols}, Want: [2]int{r, c}})
 	}
 
 	var xmat, ymat blas32.Vector
@@ -5,6 +5,7 @@
 package mat32
 
 import (
+	"math"
 	"reflect"
 	"testing"
 
@@ -160,6 +161,189 @@ func TestNewDense(t *testing.T) {
 	}
 }
 
+func TestNewDenseChecked(t *testing.T) {
+	for i, test := range []struct {
+		r, c int
+		data []float32
+		err  error
+	}{
+		{r: 2, c: 3, data: []float32{1, 2, 3, 4, 5, 6}},
+		{r: 2, c: 3, data: nil},
+		{r: 0, c: 0, data: nil},
+		{r: 2, c: 3, data: []float32{1, 2, 3, 4, 5}, err: ErrShape},
+		{r: 2, c: 3, data: []float32{1, 2, 3, 4, 5, 6, 7}, err: ErrShape},
+		{r: -1, c: 3, data: nil, err: ErrShape},
+		{r: 2, c: -3, data: nil, err: ErrShape},
+		{r: -2, c: -3, data: []float32{1, 2, 3, 4, 5, 6}, err: ErrShape},
+		// r*c wraps to zero.
+		{r: math.MaxInt/4 + 1, c: 8, data: []float32{}, err: ErrShape},
+		{r: 8, c: math.MaxInt/4 + 1, data: nil, err: ErrShape},
+		{r: math.MaxInt, c: 2, data: []float32{1, 2}, err: ErrShape},
+	} {
+		m, err := NewDenseChecked(test.r, test.c, test.data)
+		if err != test.err {
+			t.Errorf("unexpected error for test %d: got: %v want: %v", i, err, test.err)
+			continue
+		}
+		if err != nil {
+			if m != nil {
+				t.Errorf("unexpected non-nil matrix with error for test %d", i)
+			}
+			continue
+		}
+		if r, c := m.Dims(); r != test.r || c != test.c {
+			t.Errorf("unexpected dimensions for test %d: got: %d×%d want: %d×%d", i, r, c, test.r, test.c)
+		}
+		if test.data != nil && !floatsEqual(m.RawMatrix().Data, test.data) {
+			t.Errorf("unexpected data for test %d: got: %v want: %v", i, m.RawMatrix().Data, test.data)
+		}
+	}
+}
+
+func TestNewDenseColMajor(t *testing.T) {
+	for i, test := range []struct {
+		r, c int
+		data []float32
+		want [][]float32
+	}{
+		{
+			r: 2, c: 2,
+			data: []float32{1, 2, 3, 4},
+			want: [][]float32{{1, 3}, {2, 4}},
+		},
+		{
+			r: 2, c: 3,
+			data: []float32{1, 2, 3, 4, 5, 6},
+			want: [][]float32{{1, 3, 5}, {2, 4, 6}},
+		},
+		{
+			r: 3, c: 1,
+			data: []float32{1, 2, 3},
+			want: [][]float32{{1}, {2}, {3}},
+		},
+		{
+			r: 2, c: 2,
+			data: nil,
+			want: [][]float32{{0, 0}, {0, 0}},
+		},
+	} {
+		m := NewDenseColMajor(test.r, test.c, test.data)
+		want := NewDense(flatten(test.want))
+		if !Equal(m, want) {
+			t.Errorf("unexpected result for test %d: got: %v want: %v", i, Formatted(m), Formatted(want))
+		}
+		// A column-major buffer is the row-major buffer of the transpose.
+		if test.data != nil {
+			if !Equal(m, NewDense(test.c, test.r, test.data).T()) {
+				t.Errorf("result is not the transpose of the row-major interpretation for test %d", i)
+			}
+			test.data[0] = 100
+			if m.At(0, 0) == 100 {
+				t.Errorf("result shares storage with data for test %d", i)
+			}
+		}
+	}
+
+	matched, message := panicsWith(func() { NewDenseColMajor(2, 3, []float32{1, 2, 3}) }, ErrShape)
+	if !matched {
+		t.Errorf("expected ErrShape for short data: got: %q", message)
+	}
+}
+
+func TestNewDenseFrom2D(t *testing.T) {
+	for i, test := range []struct {
+		data [][]float32
+		want *Dense
+		err  error
+	}{
+		{
+			data: [][]float32{{1, 2, 3}, {4, 5, 6}},
+			want: NewDense(2, 3, []float32{1, 2, 3, 4, 5, 6}),
+		},
+		{
+			data: [][]float32{{7}},
+			want: NewDense(1, 1, []float32{7}),
+		},
+		{data: nil, err: ErrZeroLength},
+		{data: [][]float32{}, err: ErrZeroLength},
+		{data: [][]float32{{}, {}}, err: ErrZeroLength},
+		{data: [][]float32{{1, 2}, {3}}, err: ErrRowLength},
+	} {
+		m, err := NewDenseFrom2D(test.data)
+		if err != test.err {
+			t.Errorf("unexpected error for test %d: got: %v want: %v", i, err, test.err)
+			continue
+		}
+		if err != nil {
+			if m != nil {
+				t.Errorf("unexpected non-nil matrix with error for test %d", i)
+			}
+			continue
+		}
+		if !Equal(m, test.want) {
+			t.Errorf("unexpected result for test %d: got: %v want: %v", i, Formatted(m), Formatted(test.want))
+		}
+		if !reflect.DeepEqual(m.ToSlice2D(), test.data) {
+			t.Errorf("ToSlice2D does not round trip for test %d: got: %v want: %v", i, m.ToSlice2D(), test.data)
+		}
+	}
+}
+
+func TestDenseCopyFrom2D(t *testing.T) {
+	data := [][]float32{
+		{1, 2, 3},
+		{4, 5, 6},
+	}
+	var m Dense
+	if err := m.CopyFrom2D(data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := NewDense(2, 3, []float32{1, 2, 3, 4, 5, 6})
+	if !Equal(&m, want) {
+		t.Errorf("unexpected result: got: %v want: %v", Formatted(&m), Formatted(want))
+	}
+
+	// The result does not share storage with data.
+	data[0][0] = 100
+	if m.At(0, 0) != 1 {
+		t.Errorf("receiver shares storage with input")
+	}
+
+	// A non-empty receiver of the right shape, here a strided
+	// view, is written in place.
+	base := NewDense(3, 4, nil)
+	view := base.Slice(1, 3, 1, 4).(*Dense)
+	if err := view.CopyFrom2D([][]float32{{1, 2, 3}, {4, 5, 6}}); err != nil {
+		t.Fatalf("unexpected error for view: %v", err)
+	}
+	if !Equal(view, want) {
+		t.Errorf("unexpected view result: got: %v want: %v", Formatted(view), Formatted(want))
+	}
+	if Sum(base) != Sum(want) {
+		t.Errorf("CopyFrom2D wrote outside the view")
+	}
+
+	for i, test := range []struct {
+		m    *Dense
+		data [][]float32
+		err  error
+	}{
+		{m: &Dense{}, data: [][]float32{{1, 2, 3}, {4, 5}}, err: ErrRowLength},
+		{m: &Dense{}, data: [][]float32{{1}, {2, 3}}, err: ErrRowLength},
+		{m: &Dense{}, data: nil, err: ErrZeroLength},
+		{m: &Dense{}, data: [][]float32{{}}, err: ErrZeroLength},
+		{m: NewDense(3, 2, nil), data: [][]float32{{1, 2, 3}, {4, 5, 6}}, err: ErrShape},
+	} {
+		before := test.m.IsZero()
+		if err := test.m.CopyFrom2D(test.data); err != test.err {
+			t.Errorf("unexpected error for test %d: got: %v want: %v", i, err, test.err)
+		}
+		if test.m.IsZero() != before {
+			t.Errorf("receiver modified on error for test %d", i)
+		}
+	}
+}
+
 func TestAtSet(t *testing.T) {
 	for test, af := range [][][]float32{
 		{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}, // even
@@ -251,6 +435,268 @@ func TestSetRowColumn(t *testing.T) {
 	}
 }
 
+func TestSetRowColumnPanics(t *testing.T) {
+	a := NewDense(2, 3, nil)
+	for _, test := range []struct {
+		name  string
+		fn    func()
+		panic error
+	}{
+		{name: "negative row", fn: func() { a.SetRow(-1, make([]float32, 3)) }, panic: ErrRowAccess},
+		{name: "row out of range", fn: func() { a.SetRow(2, make([]float32, 3)) }, panic: ErrRowAccess},
+		{name: "short row", fn: func() { a.SetRow(0, make([]float32, 2)) }, panic: ErrRowLength},
+		{name: "negative column", fn: func() { a.SetCol(-1, make([]float32, 2)) }, panic: ErrColAccess},
+		{name: "column out of range", fn: func() { a.SetCol(3, make([]float32, 2)) }, panic: ErrColAccess},
+		{name: "long column", fn: func() { a.SetCol(0, make([]float32, 3)) }, panic: ErrColLength},
+	} {
+		panicked, message := panics(test.fn)
+		if !panicked || message != test.panic.Error() {
+			t.Errorf("expected %v panic for %s: got: %q", test.panic, test.name, message)
+		}
+	}
+
+	// Setting through a view writes only within the view.
+	b := NewDense(3, 3, []float32{1, 2, 3, 4, 5, 6, 7, 8, 9})
+	v := b.Slice(1, 3, 1, 3).(*Dense)
+	v.SetRow(0, []float32{-1, -2})
+	v.SetCol(0, []float32{-3, -4})
+	want := NewDense(3, 3, []float32{1, 2, 3, 4, -3, -2, 7, -4, 9})
+	if !Equal(b, want) {
+		t.Errorf("unexpected result of setting through a view:\ngot:\n%v\nwant:\n%v", Formatted(b), Formatted(want))
+	}
+}
+
+func TestDenseRows(t *testing.T) {
+	m := NewDense(3, 4, []float32{
+		1, 2, 3, 4,
+		5, 6, 7, 8,
+		9, 10, 11, 12,
+	})
+	for i, test := range []struct {
+		m    *Dense
+		sums []float32
+	}{
+		{m: m, sums: []float32{10, 26, 42}},
+		{m: m.Slice(1, 3, 1, 3).(*Dense), sums: []float32{13, 21}},
+	} {
+		var got []float32
+		var total VecDense
+		test.m.Rows(func(j int, row *VecDense) bool {
+			if j != len(got) {
+				t.Errorf("unexpected row index for test %d: got: %d want: %d", i, j, len(got))
+			}
+			got = append(got, Sum(row))
+			if total.IsZero() {
+				total.CloneVec(row)
+			} else {
+				total.AddVec(&total, row)
+			}
+			return true
+		})
+		if !floatsEqual(got, test.sums) {
+			t.Errorf("unexpected row sums for test %d: got: %v want: %v", i, got, test.sums)
+		}
+		var sum float32
+		for j := 0; j < total.Len(); j++ {
+			sum += total.AtVec(j)
+		}
+		if want := Sum(test.m); sum != want {
+			t.Errorf("unexpected total for test %d: got: %v want: %v", i, sum, want)
+		}
+	}
+
+	// Returning false stops iteration.
+	var n int
+	m.Rows(func(int, *VecDense) bool {
+		n++
+		return n < 2
+	})
+	if n != 2 {
+		t.Errorf("unexpected number of rows visited after early stop: got: %d want: 2", n)
+	}
+}
+
+func TestAppendRow(t *testing.T) {
+	var m Dense
+	var want []float32
+	for i := 0; i < 9; i++ {
+		row := []float32{float32(i), float32(10 * i), float32(-i)}
+		m.AppendRow(row)
+		want = append(want, row...)
+
+		r, c := m.Dims()
+		if r != i+1 || c != 3 {
+			t.Fatalf("unexpected dimensions after append %d: got: %d×%d want: %d×3", i, r, c, i+1)
+		}
+		if cr, _ := m.Caps(); cr < r {
+			t.Fatalf("row capacity less than rows after append %d: cap: %d rows: %d", i, cr, r)
+		}
+		if !Equal(&m, NewDense(i+1, 3, want)) {
+			t.Errorf("unexpected contents after append %d:\n%v", i, Formatted(&m))
+		}
+	}
+
+	panicked, message := panics(func() { m.AppendRow([]float32{1, 2}) })
+	if !panicked || message != ErrRowLength.Error() {
+		t.Errorf("expected ErrRowLength for wrong-width append: got: %q", message)
+	}
+	panicked, message = panics(func() {
+		var e Dense
+		e.AppendRow(nil)
+	})
+	if !panicked || message != ErrZeroLength.Error() {
+		t.Errorf("expected ErrZeroLength for empty first row: got: %q", message)
+	}
+
+	// Appending to a strided view reallocates contiguously once full.
+	base := NewDense(2, 4, []float32{1, 2, 3, 4, 5, 6, 7, 8})
+	v := base.Slice(0, 2, 1, 3).(*Dense)
+	v.AppendRow([]float32{-1, -2})
+	if !Equal(v, NewDense(3, 2, []float32{2, 3, 6, 7, -1, -2})) {
+		t.Errorf("unexpected contents after appending to view:\n%v", Formatted(v))
+	}
+	if !Equal(base, NewDense(2, 4, []float32{1, 2, 3, 4, 5, 6, 7, 8})) {
+		t.Errorf("appending to a full view modified its parent:\n%v", Formatted(base))
+	}
+}
+
+func TestToSlice(t *testing.T) {
+	base := NewDense(3, 4, []float32{
+		1, 2, 3, 4,
+		5, 6, 7, 8,
+		9, 10, 11, 12,
+	})
+	for i, test := range []struct {
+		m    *Dense
+		want [][]float32
+	}{
+		{
+			m:    base,
+			want: [][]float32{{1, 2, 3, 4}, {5, 6, 7, 8}, {9, 10, 11, 12}},
+		},
+		{
+			m:    base.Slice(1, 3, 1, 3).(*Dense),
+			want: [][]float32{{6, 7}, {10, 11}},
+		},
+		{
+			m:    base.Slice(0, 3, 2, 3).(*Dense),
+			want: [][]float32{{3}, {7}, {11}},
+		},
+	} {
+		got := test.m.ToSlice()
+		if _, _, want := flatten(test.want); !floatsEqual(got, want) {
+			t.Errorf("unexpected slice for test %d: got: %v want: %v", i, got, test.want)
+		}
+		got2D := test.m.ToSlice2D()
+		if len(got2D) != len(test.want) {
+			t.Errorf("unexpected number of rows for test %d: got: %d want: %d", i, len(got2D), len(test.want))
+			continue
+		}
+		for j, row := range got2D {
+			if !floatsEqual(row, test.want[j]) {
+				t.Errorf("unexpected row %d for test %d: got: %v want: %v", j, i, row, test.want[j])
+			}
+		}
+
+		// The results are copies.
+		got[0] = -1
+		got2D[0][0] = -1
+		if test.m.At(0, 0) == -1 {
+			t.Errorf("ToSlice result shares storage with the matrix for test %d", i)
+		}
+	}
+}
+
+func TestReshape(t *testing.T) {
+	data := []float32{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+	m := NewDense(2, 6, data)
+	if err := m.Reshape(3, 4); err != nil {
+		t.Fatalf("unexpected error reshaping 2×6 to 3×4: %v", err)
+	}
+	if !Equal(m, NewDense(3, 4, data)) {
+		t.Errorf("unexpected contents after reshape:\n%v", Formatted(m))
+	}
+	if cr, cc := m.Caps(); cr != 3 || cc != 4 {
+		t.Errorf("unexpected capacity after reshape: got: %d×%d want: 3×4", cr, cc)
+	}
+	// Storage is shared with the original data.
+	m.Set(2, 3, -12)
+	if data[11] != -12 {
+		t.Errorf("reshape did not share storage")
+	}
+
+	// A single row of a strided matrix is contiguous.
+	row := NewDense(3, 4, nil).Slice(1, 2, 0, 4).(*Dense)
+	if err := row.Reshape(2, 2); err != nil {
+		t.Errorf("unexpected error reshaping a single row view: %v", err)
+	}
+	// As are complete rows of a matrix.
+	rows := NewDense(3, 4, nil).Slice(1, 3, 0, 4).(*Dense)
+	if err := rows.Reshape(4, 2); err != nil {
+		t.Errorf("unexpected error reshaping a full-width view: %v", err)
+	}
+
+	for i, test := range []struct {
+		m    *Dense
+		r, c int
+		err  error
+	}{
+		{m: NewDense(2, 6, nil), r: 3, c: 5, err: ErrShape},
+		{m: NewDense(2, 6, nil), r: 0, c: 0, err: ErrShape},
+		{m: NewDense(2, 6, nil), r: -3, c: -4, err: ErrShape},
+		// r*c wraps to the number of elements.
+		{m: NewDense(2, 2, nil), r: 4, c: math.MaxInt/2 + 2, err: ErrShape},
+		{m: NewDense(2, 2, nil), r: math.MaxInt/2 + 2, c: 4, err: ErrShape},
+		{m: NewDense(4, 4, nil).Slice(0, 2, 0, 2).(*Dense), r: 1, c: 4, err: ErrIllegalStride},
+	} {
+		before := *test.m
+		if err := test.m.Reshape(test.r, test.c); err != test.err {
+			t.Errorf("unexpected error for test %d: got: %v want: %v", i, err, test.err)
+		}
+		if r, c := test.m.Dims(); r != before.mat.Rows || c != before.mat.Cols {
+			t.Errorf("failed reshape modified dimensions for test %d", i)
+		}
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	m := NewDense(2, 3, []float32{1, 2, 3, 4, 5, 6})
+	v, err := m.Flatten()
+	if err != nil {
+		t.Fatalf("unexpected error flattening a contiguous matrix: %v", err)
+	}
+	if !Equal(v, NewVecDense(6, []float32{1, 2, 3, 4, 5, 6})) {
+		t.Errorf("unexpected flattened vector: got: %v", v.RawVector().Data)
+	}
+	v.SetVec(4, -5)
+	if m.At(1, 1) != -5 {
+		t.Errorf("mutation through the vector not reflected in the matrix")
+	}
+	m.Set(0, 2, -3)
+	if v.AtVec(2) != -3 {
+		t.Errorf("mutation through the matrix not reflected in the vector")
+	}
+
+	// Full-width views are contiguous.
+	rows := NewDense(3, 2, []float32{1, 2, 3, 4, 5, 6}).Slice(1, 3, 0, 2).(*Dense)
+	v, err = rows.Flatten()
+	if err != nil {
+		t.Fatalf("unexpected error flattening a full-width view: %v", err)
+	}
+	if !Equal(v, NewVecDense(4, []float32{3, 4, 5, 6})) {
+		t.Errorf("unexpected flattened view: got: %v", v.RawVector().Data)
+	}
+
+	strided := NewDense(3, 3, nil).Slice(0, 2, 0, 2).(*Dense)
+	if _, err := strided.Flatten(); err != ErrIllegalStride {
+		t.Errorf("unexpected error flattening a strided view: got: %v want: %v", err, ErrIllegalStride)
+	}
+	var empty Dense
+	if _, err := empty.Flatten(); err != ErrZeroLength {
+		t.Errorf("unexpected error flattening an empty matrix: got: %v want: %v", err, ErrZeroLength)
+	}
+}
+
 func TestRowColView(t *testing.T) {
 	for _, test := range []struct {
 		mat [][]float32
@@ -583,6 +1029,399 @@ func TestSub(t *testing.T) {
 	testTwoInput(t, "Sub", &Dense{}, method, denseComparison, legalTypesAll, legalSizeSameRectangular, 1e-7)
 }
 
+func TestAddBroadcast(t *testing.T) {
+	a := NewDense(2, 3, []float32{
+		1, 2, 3,
+		4, 5, 6,
+	})
+	for i, test := range []struct {
+		b    Matrix
+		want *Dense
+	}{
+		{
+			b: NewDense(2, 3, []float32{10, 20, 30, 40, 50, 60}),
+			want: NewDense(2, 3, []float32{
+				11, 22, 33,
+				44, 55, 66,
+			}),
+		},
+		{
+			b: NewDense(1, 3, []float32{10, 20, 30}),
+			want: NewDense(2, 3, []float32{
+				11, 22, 33,
+				14, 25, 36,
+			}),
+		},
+		{
+			b: NewDense(2, 1, []float32{100, 200}),
+			want: NewDense(2, 3, []float32{
+				101, 102, 103,
+				204, 205, 206,
+			}),
+		},
+		{
+			// Row broadcast of a transposed column vector.
+			b: NewVecDense(3, []float32{-1, -2, -3}).T(),
+			want: NewDense(2, 3, []float32{
+				0, 0, 0,
+				3, 3, 3,
+			}),
+		},
+		{
+			b: NewVecDense(2, []float32{1, -1}),
+			want: NewDense(2, 3, []float32{
+				2, 3, 4,
+				3, 4, 5,
+			}),
+		},
+	} {
+		var got Dense
+		got.AddBroadcast(a, test.b)
+		if !Equal(&got, test.want) {
+			t.Errorf("unexpected result for test %d:\ngot:\n%v\nwant:\n%v", i, Formatted(&got), Formatted(test.want))
+		}
+
+		inPlace := DenseCopyOf(a)
+		inPlace.AddBroadcast(inPlace, test.b)
+		if !Equal(inPlace, test.want) {
+			t.Errorf("unexpected in-place result for test %d:\ngot:\n%v\nwant:\n%v", i, Formatted(inPlace), Formatted(test.want))
+		}
+	}
+
+	for _, b := range []Matrix{
+		NewDense(1, 2, nil),
+		NewDense(3, 1, nil),
+		NewDense(2, 2, nil),
+		NewDense(3, 3, nil),
+	} {
+		matched, message := panicsWith(func() {
+			var m Dense
+			m.AddBroadcast(a, b)
+		}, ErrShape)
+		if r, c := b.Dims(); !matched {
+			t.Errorf("expected ErrShape for %d×%d operand: got: %q", r, c, message)
+		}
+	}
+}
+
+func TestAddSubMatrix(t *testing.T) {
+	for i, test := range []struct {
+		m    [][]float32
+		i, j int
+		a    Matrix
+		want [][]float32
+	}{
+		{
+			m:    [][]float32{{1, 2, 3, 4}, {5, 6, 7, 8}, {9, 10, 11, 12}, {13, 14, 15, 16}},
+			i:    1,
+			j:    1,
+			a:    NewDense(2, 2, []float32{1, 2, 3, 4}),
+			want: [][]float32{{1, 2, 3, 4}, {5, 7, 9, 8}, {9, 13, 15, 12}, {13, 14, 15, 16}},
+		},
+		{
+			m:    [][]float32{{1, 2, 3, 4}, {5, 6, 7, 8}, {9, 10, 11, 12}, {13, 14, 15, 16}},
+			i:    1,
+			j:    1,
+			a:    NewDense(2, 2, []float32{1, 2, 3, 4}).T(),
+			want: [][]float32{{1, 2, 3, 4}, {5, 7, 10, 8}, {9, 12, 15, 12}, {13, 14, 15, 16}},
+		},
+		{
+			m:    [][]float32{{0, 0, 0}, {0, 0, 0}},
+			i:    0,
+			j:    1,
+			a:    asBasicMatrix(NewDense(2, 2, []float32{1, 2, 3, 4})),
+			want: [][]float32{{0, 1, 2}, {0, 3, 4}},
+		},
+	} {
+		m := NewDense(flatten(test.m))
+		m.AddSubMatrix(test.i, test.j, test.a)
+		want := NewDense(flatten(test.want))
+		if !Equal(m, want) {
+			t.Errorf("unexpected result for test %d: got: %v want: %v", i, Formatted(m), Formatted(want))
+		}
+	}
+
+	m := NewDense(3, 3, []float32{1, 2, 3, 4, 5, 6, 7, 8, 9})
+	m.AddSubMatrix(0, 0, m.T())
+	want := NewDense(3, 3, []float32{2, 6, 10, 6, 10, 14, 10, 14, 18})
+	if !Equal(m, want) {
+		t.Errorf("unexpected result for aliased transpose: got: %v want: %v", Formatted(m), Formatted(want))
+	}
+
+	for _, test := range []struct{ i, j int }{{3, 3}, {-1, 0}, {0, 3}, {3, 0}} {
+		m := NewDense(4, 4, nil)
+		panicked, message := panics(func() { m.AddSubMatrix(test.i, test.j, NewDense(2, 2, nil)) })
+		if !panicked || message != ErrIndexOutOfRange.Error() {
+			t.Errorf("expected ErrIndexOutOfRange for block at (%d,%d): got: %q", test.i, test.j, message)
+		}
+	}
+}
+
+func TestGram(t *testing.T) {
+	for i, test := range []struct {
+		a Matrix
+	}{
+		{a: NewDense(1, 1, []float32{3})},
+		{a: NewDense(2, 3, []float32{1, 2, 3, 4, 5, 6})},
+		{a: NewDense(3, 2, []float32{1, -2, 0, 5, 3, 1})},
+		{a: NewDense(3, 2, []float32{1, -2, 0, 5, 3, 1}).T()},
+		{a: asBasicMatrix(NewDense(2, 4, []float32{1, 0, -1, 2, 0.5, 3, 1, 1}))},
+	} {
+		for _, trans := range []bool{false, true} {
+			var want Dense
+			if trans {
+				want.Mul(test.a.T(), test.a)
+			} else {
+				want.Mul(test.a, test.a.T())
+			}
+			var got Dense
+			got.Gram(test.a, trans)
+			if !EqualApprox(&got, &want, 1e-6) {
+				t.Errorf("unexpected Gram matrix for test %d trans=%t:\ngot:\n%v\nwant:\n%v", i, trans, Formatted(&got), Formatted(&want))
+			}
+			if !Equal(&got, got.T()) {
+				t.Errorf("Gram matrix not symmetric for test %d trans=%t", i, trans)
+			}
+		}
+	}
+
+	// The receiver may alias a square input.
+	a := NewDense(2, 2, []float32{1, 2, 3, 4})
+	var want Dense
+	want.Mul(a, a.T())
+	a.Gram(a, false)
+	if !Equal(a, &want) {
+		t.Errorf("unexpected aliased Gram matrix:\ngot:\n%v\nwant:\n%v", Formatted(a), Formatted(&want))
+	}
+}
+
+func TestGramSchmidt(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i, a := range []*Dense{
+		NewDense(1, 1, []float32{-3}),
+		NewDense(3, 2, []float32{1, 1, 0, 1, 1, 0}),
+		NewDense(3, 3, []float32{2, -1, 0, -1, 2, -1, 0, -1, 2}),
+		randNormDense(10, 4, rnd),
+		randNormDense(20, 20, rnd),
+	} {
+		r, c := a.Dims()
+		var q Dense
+		err := q.GramSchmidt(a)
+		if err != nil {
+			t.Errorf("unexpected error for test %d: %v", i, err)
+			continue
+		}
+		if qr, qc := q.Dims(); qr != r || qc != c {
+			t.Errorf("unexpected dimensions for test %d: got: %d×%d want: %d×%d", i, qr, qc, r, c)
+		}
+		var qtq Dense
+		qtq.Mul(q.T(), &q)
+		if !EqualApprox(&qtq, eye(c), 1e-5) {
+			t.Errorf("columns not orthonormal for test %d: QᵀQ =\n%v", i, Formatted(&qtq))
+		}
+
+		// The columns of a must lie in the span of Q.
+		var qqt, proj Dense
+		qqt.Mul(&q, q.T())
+		proj.Mul(&qqt, a)
+		if !EqualApprox(&proj, a, 1e-4) {
+			t.Errorf("column space not preserved for test %d", i)
+		}
+	}
+
+	for i, a := range []*Dense{
+		NewDense(3, 2, []float32{1, 2, 1, 2, 1, 2}),
+		NewDense(3, 3, []float32{1, 2, 3, 4, 5, 9, 7, 8, 15}),
+		NewDense(2, 2, []float32{0, 1, 0, 1}),
+		NewDense(2, 3, nil),
+	} {
+		var q Dense
+		err := q.GramSchmidt(a)
+		if err != ErrSingular {
+			t.Errorf("unexpected error for dependent test %d: got: %v want: %v", i, err, ErrSingular)
+		}
+	}
+}
+
+func TestRank(t *testing.T) {
+	for i, test := range []struct {
+		a    *Dense
+		tol  float32
+		want int
+	}{
+		{
+			a: NewDense(3, 3, []float32{
+				2, 1, 0,
+				1, 3, 1,
+				0, 1, 4,
+			}),
+			want: 3,
+		},
+		{
+			// The third row is the sum of the first two.
+			a: NewDense(3, 3, []float32{
+				1, 2, 3,
+				4, 5, 6,
+				5, 7, 9,
+			}),
+			want: 2,
+		},
+		{
+			a:    NewDense(3, 3, nil),
+			want: 0,
+		},
+		{
+			a: NewDense(2, 4, []float32{
+				1, 2, 3, 4,
+				2, 4, 6, 8,
+			}),
+			want: 1,
+		},
+		{
+			a: NewDense(4, 2, []float32{
+				1, 0,
+				0, 1,
+				1, 1,
+				2, 3,
+			}),
+			want: 2,
+		},
+		{
+			// A small pivot is significant by default but not
+			// under a coarse tolerance.
+			a: NewDense(2, 2, []float32{
+				1, 0,
+				0, 1e-3,
+			}),
+			want: 2,
+		},
+		{
+			a: NewDense(2, 2, []float32{
+				1, 0,
+				0, 1e-3,
+			}),
+			tol:  1e-2,
+			want: 1,
+		},
+	} {
+		orig := DenseCopyOf(test.a)
+		if got := test.a.Rank(test.tol); got != test.want {
+			t.Errorf("unexpected rank for test %d: got: %d want: %d", i, got, test.want)
+		}
+		if !Equal(test.a, orig) {
+			t.Errorf("Rank modified the receiver for test %d", i)
+		}
+	}
+}
+
+func TestInverse(t *testing.T) {
+	for i, test := range []struct {
+		a    *Dense
+		want *Dense
+	}{
+		{
+			a:    NewDense(1, 1, []float32{4}),
+			want: NewDense(1, 1, []float32{0.25}),
+		},
+		{
+			a:    NewDense(2, 2, []float32{4, 7, 2, 6}),
+			want: NewDense(2, 2, []float32{0.6, -0.7, -0.2, 0.4}),
+		},
+		{
+			a: NewDense(3, 3, []float32{
+				0, 1, 2,
+				1, 0, 3,
+				4, -3, 8,
+			}),
+			want: NewDense(3, 3, []float32{
+				-4.5, 7, -1.5,
+				-2, 4, -1,
+				1.5, -2, 0.5,
+			}),
+		},
+	} {
+		var got Dense
+		if err := got.Inverse(test.a); err != nil {
+			t.Errorf("unexpected error for test %d: %v", i, err)
+			continue
+		}
+		if !EqualApprox(&got, test.want, 1e-5) {
+			t.Errorf("unexpected inverse for test %d:\ngot:\n%v\nwant:\n%v", i, Formatted(&got), Formatted(test.want))
+		}
+	}
+
+	var got Dense
+	err := got.Inverse(NewDense(2, 2, []float32{1, 2, 2, 4}))
+	if _, ok := err.(Condition); !ok {
+		t.Errorf("expected Condition error for singular matrix: got: %v", err)
+	}
+}
+
+func TestPseudoInverse(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i, a := range []*Dense{
+		NewDense(3, 2, []float32{
+			1, 2,
+			3, 4,
+			5, 6,
+		}),
+		NewDense(2, 4, []float32{
+			1, 0, 2, -1,
+			0, 3, 1, 1,
+		}),
+		// Rank deficient.
+		NewDense(3, 3, []float32{
+			1, 2, 3,
+			2, 4, 6,
+			1, 1, 1,
+		}),
+		randNormDense(6, 4, rnd),
+	} {
+		r, c := a.Dims()
+		var pinv Dense
+		if err := pinv.PseudoInverse(a, 0); err != nil {
+			t.Errorf("unexpected error for test %d: %v", i, err)
+			continue
+		}
+		if pr, pc := pinv.Dims(); pr != c || pc != r {
+			t.Errorf("unexpected shape for test %d: got: %d×%d want: %d×%d", i, pr, pc, c, r)
+			continue
+		}
+
+		// Check the Moore-Penrose conditions A A⁺ A = A and A⁺ A A⁺ = A⁺.
+		var apa, tmp Dense
+		tmp.Mul(a, &pinv)
+		apa.Mul(&tmp, a)
+		if !EqualApprox(&apa, a, 1e-4) {
+			t.Errorf("A*A⁺*A != A for test %d:\ngot:\n%v\nwant:\n%v", i, Formatted(&apa), Formatted(a))
+		}
+		var pap Dense
+		tmp.Reset()
+		tmp.Mul(&pinv, a)
+		pap.Mul(&tmp, &pinv)
+		if !EqualApprox(&pap, &pinv, 1e-4) {
+			t.Errorf("A⁺*A*A⁺ != A⁺ for test %d", i)
+		}
+	}
+
+	// The pseudo-inverse of a full-rank square matrix is its inverse.
+	a := NewDense(3, 3, []float32{
+		0, 1, 2,
+		1, 0, 3,
+		4, -3, 8,
+	})
+	var inv, pinv Dense
+	if err := inv.Inverse(a); err != nil {
+		t.Fatalf("unexpected error computing inverse: %v", err)
+	}
+	if err := pinv.PseudoInverse(a, 0); err != nil {
+		t.Fatalf("unexpected error computing pseudo-inverse: %v", err)
+	}
+	if !EqualApprox(&pinv, &inv, 1e-4) {
+		t.Errorf("pseudo-inverse does not match inverse:\ngot:\n%v\nwant:\n%v", Formatted(&pinv), Formatted(&inv))
+	}
+}
+
 func TestMulElem(t *testing.T) {
 	for i, test := range []struct {
 		a, b, r [][]float32
@@ -854,37 +1693,157 @@ func TestMul(t *testing.T) {
 		type Muler interface {
 			Mul(a, b Matrix)
 		}
-		rd := receiver.(Muler)
-		rd.Mul(a, b)
-	}
-	denseComparison := func(receiver, a, b *Dense) {
-		receiver.Mul(a, b)
-	}
-	legalSizeMul := func(ar, ac, br, bc int) bool {
-		return ac == br
+		rd := receiver.(Muler)
+		rd.Mul(a, b)
+	}
+	denseComparison := func(receiver, a, b *Dense) {
+		receiver.Mul(a, b)
+	}
+	legalSizeMul := func(ar, ac, br, bc int) bool {
+		return ac == br
+	}
+	testTwoInput(t, "Mul", &Dense{}, method, denseComparison, legalTypesAll, legalSizeMul, 1e-5)
+}
+
+func randDense(size int, rho float32, rnd func() float32) (*Dense, error) {
+	if size == 0 {
+		return nil, ErrZeroLength
+	}
+	d := &Dense{
+		mat: blas32.General{
+			Rows: size, Cols: size, Stride: size,
+			Data: make([]float32, size*size),
+		},
+		capRows: size, capCols: size,
+	}
+	for i := 0; i < size; i++ {
+		for j := 0; j < size; j++ {
+			if rand.Float32() < rho {
+				d.Set(i, j, rnd())
+			}
+		}
+	}
+	return d, nil
+}
+
+func TestExp(t *testing.T) {
+	e := math32.Exp
+	for i, test := range []struct {
+		a    [][]float32
+		want [][]float32
+	}{
+		{
+			a:    [][]float32{{1}},
+			want: [][]float32{{e(1)}},
+		},
+		{
+			a:    [][]float32{{0, 0}, {0, 0}},
+			want: [][]float32{{1, 0}, {0, 1}},
+		},
+		{
+			a:    [][]float32{{1, 0, 0}, {0, -2, 0}, {0, 0, 0.5}},
+			want: [][]float32{{e(1), 0, 0}, {0, e(-2), 0}, {0, 0, e(0.5)}},
+		},
+		{
+			// Large enough norm to require scaling and squaring.
+			a:    [][]float32{{5, 0, 0}, {0, -3, 0}, {0, 0, 0.1}},
+			want: [][]float32{{e(5), 0, 0}, {0, e(-3), 0}, {0, 0, e(0.1)}},
+		},
+		{
+			// Nilpotent matrices have the truncated series I + N + N^2/2 + ...
+			a:    [][]float32{{0, 1}, {0, 0}},
+			want: [][]float32{{1, 1}, {0, 1}},
+		},
+		{
+			a:    [][]float32{{0, 2, 3}, {0, 0, 4}, {0, 0, 0}},
+			want: [][]float32{{1, 2, 7}, {0, 1, 4}, {0, 0, 1}},
+		},
+		{
+			a:    [][]float32{{0, 20, 30}, {0, 0, 40}, {0, 0, 0}},
+			want: [][]float32{{1, 20, 430}, {0, 1, 40}, {0, 0, 1}},
+		},
+	} {
+		var got Dense
+		err := got.Exp(NewDense(flatten(test.a)))
+		if err != nil {
+			t.Errorf("unexpected error for test %d: %v", i, err)
+		}
+		want := NewDense(flatten(test.want))
+		if !EqualApprox(&got, want, 1e-5) {
+			t.Errorf("unexpected result for Exp test %d\ngot:\n%v\nwant:\n%v",
+				i, Formatted(&got), Formatted(want))
+		}
+	}
+
+	matched, message := panicsWith(func() {
+		var m Dense
+		m.Exp(NewDense(2, 3, nil))
+	}, ErrShape)
+	if !matched {
+		t.Errorf("expected panic for non-square matrix: got: %q want: %q", message, ErrShape)
+	}
+}
+
+func TestSqrt(t *testing.T) {
+	for i, test := range []struct {
+		a    []float32
+		n    int
+		want [][]float32
+	}{
+		{
+			n:    1,
+			a:    []float32{9},
+			want: [][]float32{{3}},
+		},
+		{
+			n:    3,
+			a:    []float32{4, 0, 0, 0, 9, 0, 0, 0, 0},
+			want: [][]float32{{2, 0, 0}, {0, 3, 0}, {0, 0, 0}},
+		},
+		{
+			n:    2,
+			a:    []float32{5, 4, 4, 5},
+			want: [][]float32{{2, 1}, {1, 2}},
+		},
+		{
+			n: 3,
+			a: []float32{
+				4, 1, 2,
+				1, 3, 0.5,
+				2, 0.5, 6,
+			},
+		},
+	} {
+		a := NewSymDense(test.n, test.a)
+		var got Dense
+		err := got.Sqrt(a)
+		if err != nil {
+			t.Errorf("unexpected error for test %d: %v", i, err)
+			continue
+		}
+		if test.want != nil {
+			want := NewDense(flatten(test.want))
+			if !EqualApprox(&got, want, 1e-5) {
+				t.Errorf("unexpected result for Sqrt test %d\ngot:\n%v\nwant:\n%v",
+					i, Formatted(&got), Formatted(want))
+			}
+		}
+		var sq Dense
+		sq.Mul(&got, &got)
+		if !EqualApprox(&sq, a, 1e-5) {
+			t.Errorf("unexpected square of Sqrt for test %d\ngot:\n%v\nwant:\n%v",
+				i, Formatted(&sq), Formatted(a))
+		}
 	}
-	testTwoInput(t, "Mul", &Dense{}, method, denseComparison, legalTypesAll, legalSizeMul, 1e-5)
-}
 
-func randDense(size int, rho float32, rnd func() float32) (*Dense, error) {
-	if size == 0 {
-		return nil, ErrZeroLength
-	}
-	d := &Dense{
-		mat: blas32.General{
-			Rows: size, Cols: size, Stride: size,
-			Data: make([]float32, size*size),
-		},
-		capRows: size, capCols: size,
+	var m Dense
+	err := m.Sqrt(NewSymDense(2, []float32{1, 2, 2, 1}))
+	if err != ErrNotPSD {
+		t.Errorf("unexpected error for indefinite matrix: got: %v want: %v", err, ErrNotPSD)
 	}
-	for i := 0; i < size; i++ {
-		for j := 0; j < size; j++ {
-			if rand.Float32() < rho {
-				d.Set(i, j, rnd())
-			}
-		}
+	if !m.IsZero() {
+		t.Errorf("unexpected modification of receiver on error")
 	}
-	return d, nil
 }
 
 func TestPow(t *testing.T) {
@@ -974,6 +1933,357 @@ func TestPow(t *testing.T) {
 	}
 }
 
+func TestPowSquaring(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	a := randNormDense(5, 5, rnd)
+	// Scale a so that high powers remain representable.
+	a.Scale(1/Norm(a, 1), a)
+	a.Scale(0.9, a)
+
+	for _, n := range []int{5, 16, 17, 31} {
+		var got, want Dense
+		got.Pow(a, n)
+		want.iterativePow(a, n)
+		if !EqualApprox(&got, &want, 1e-5) {
+			t.Errorf("unexpected result for Pow(a, %d):\ngot:\n%v\nwant:\n%v",
+				n, Formatted(&got), Formatted(&want))
+		}
+	}
+
+	// Negative powers raise the inverse.
+	b := NewDense(3, 3, []float32{
+		4, 1, 0,
+		1, 3, 1,
+		0, 1, 2,
+	})
+	for _, n := range []int{-1, -2, -5} {
+		var inv, pos, prod Dense
+		inv.Pow(b, n)
+		pos.Pow(b, -n)
+		prod.Mul(&inv, &pos)
+		if !EqualApprox(&prod, eye(3), 1e-4) {
+			t.Errorf("Pow(b, %d) * Pow(b, %d) is not the identity:\n%v", n, -n, Formatted(&prod))
+		}
+	}
+
+	// The receiver may alias a for negative powers.
+	inPlace := DenseCopyOf(b)
+	inPlace.Pow(inPlace, -2)
+	var want Dense
+	want.Pow(b, -2)
+	if !Equal(inPlace, &want) {
+		t.Errorf("unexpected in-place result for negative power")
+	}
+
+	// The most negative power does not overflow on negation.
+	signs := NewDense(2, 2, []float32{1, 0, 0, -1})
+	for _, test := range []struct {
+		n    int
+		want *Dense
+	}{
+		{n: math.MinInt, want: eye(2)},
+		{n: -math.MaxInt, want: signs},
+	} {
+		var got Dense
+		got.Pow(signs, test.n)
+		if !Equal(&got, test.want) {
+			t.Errorf("unexpected result for Pow(a, %d):\ngot:\n%v\nwant:\n%v",
+				test.n, Formatted(&got), Formatted(test.want))
+		}
+	}
+
+	panicked, message := panics(func() {
+		var m Dense
+		m.Pow(NewDense(2, 2, []float32{1, 2, 2, 4}), -1)
+	})
+	if !panicked || message != ErrSingular.Error() {
+		t.Errorf("expected ErrSingular for negative power of singular matrix: got: %q", message)
+	}
+}
+
+func TestDenseMinMaxSum(t *testing.T) {
+	// The padding between the rows of the view holds values
+	// that would change every result if they were included.
+	base := NewDense(4, 5, []float32{
+		100, 100, 100, 100, 100,
+		100, 3, -2, 7, -100,
+		-100, 0.5, 4, -6, 100,
+		100, 1, 1, 1, -100,
+	})
+	for i, test := range []struct {
+		m             *Dense
+		min, max, sum float32
+	}{
+		{
+			m:   base.Slice(1, 4, 1, 4).(*Dense),
+			min: -6, max: 7, sum: 9.5,
+		},
+		{
+			m:   base.Slice(1, 2, 1, 4).(*Dense),
+			min: -2, max: 7, sum: 8,
+		},
+		{
+			m:   base.Slice(2, 3, 2, 3).(*Dense),
+			min: 4, max: 4, sum: 4,
+		},
+		{
+			m:   NewDense(2, 3, []float32{1, 2, 3, 4, 5, 6}),
+			min: 1, max: 6, sum: 21,
+		},
+	} {
+		if got := test.m.Min(); got != test.min {
+			t.Errorf("unexpected Min for test %d: got: %v want: %v", i, got, test.min)
+		}
+		if got := test.m.Max(); got != test.max {
+			t.Errorf("unexpected Max for test %d: got: %v want: %v", i, got, test.max)
+		}
+		if got := test.m.Sum(); got != test.sum {
+			t.Errorf("unexpected Sum for test %d: got: %v want: %v", i, got, test.sum)
+		}
+		if got := Sum(test.m); got != test.sum {
+			t.Errorf("Dense.Sum disagrees with Sum for test %d: got: %v want: %v", i, got, test.sum)
+		}
+	}
+
+	// NaN elements propagate to each result, wherever they appear.
+	nan := math32.NaN()
+	view := DenseCopyOf(base).Slice(1, 4, 1, 4).(*Dense)
+	view.Set(2, 1, nan)
+	for i, m := range []*Dense{
+		NewDense(2, 2, []float32{nan, nan, nan, nan}),
+		NewDense(2, 2, []float32{nan, 1, 2, 3}),
+		NewDense(2, 2, []float32{1, 2, 3, nan}),
+		view,
+	} {
+		for _, fn := range []struct {
+			name string
+			fn   func(*Dense) float32
+		}{
+			{name: "Min", fn: (*Dense).Min},
+			{name: "Max", fn: (*Dense).Max},
+			{name: "Sum", fn: (*Dense).Sum},
+		} {
+			if got := fn.fn(m); !math32.IsNaN(got) {
+				t.Errorf("expected NaN %s for NaN test %d: got: %v", fn.name, i, got)
+			}
+		}
+	}
+
+	for _, fn := range []func(*Dense) float32{(*Dense).Min, (*Dense).Max, (*Dense).Sum} {
+		panicked, message := panics(func() { fn(&Dense{}) })
+		if !panicked || message != ErrZeroLength.Error() {
+			t.Errorf("expected ErrZeroLength for empty matrix: got: %q", message)
+		}
+	}
+}
+
+func TestDenseAbs(t *testing.T) {
+	nan := math32.NaN()
+	negZero := math32.Copysign(0, -1)
+	a := NewDense(2, 3, []float32{
+		-1, 2, negZero,
+		nan, -3.5, math32.Inf(-1),
+	})
+	want := []float32{
+		1, 2, 0,
+		nan, 3.5, math32.Inf(1),
+	}
+	check := func(name string, got *Dense, trans bool) {
+		r, c := 2, 3
+		for i := 0; i < r; i++ {
+			for j := 0; j < c; j++ {
+				var v float32
+				if trans {
+					v = got.At(j, i)
+				} else {
+					v = got.At(i, j)
+				}
+				w := want[i*c+j]
+				if math32.IsNaN(w) {
+					if !math32.IsNaN(v) {
+						t.Errorf("unexpected %s result at (%d,%d): got: %v want: NaN", name, i, j, v)
+					}
+					continue
+				}
+				if v != w || math32.Signbit(v) {
+					t.Errorf("unexpected %s result at (%d,%d): got: %v want: %v", name, i, j, v, w)
+				}
+			}
+		}
+	}
+
+	var m Dense
+	m.Abs(a)
+	check("Abs", &m, false)
+
+	var mt Dense
+	mt.Abs(a.T())
+	check("transposed Abs", &mt, true)
+
+	var mb Dense
+	mb.Abs(asBasicMatrix(a))
+	check("non-raw Abs", &mb, false)
+
+	inPlace := DenseCopyOf(a)
+	inPlace.Abs(inPlace)
+	check("in-place Abs", inPlace, false)
+}
+
+func TestDenseSign(t *testing.T) {
+	nan := math32.NaN()
+	a := NewDense(2, 4, []float32{
+		3, -0.5, 0, math32.Copysign(0, -1),
+		nan, math32.Inf(1), math32.Inf(-1), -1e-30,
+	})
+	want := []float32{
+		1, -1, 0, 0,
+		nan, 1, -1, -1,
+	}
+	for _, test := range []struct {
+		name string
+		a    Matrix
+	}{
+		{name: "raw", a: a},
+		{name: "non-raw", a: asBasicMatrix(a)},
+		{name: "in-place", a: nil},
+	} {
+		var m *Dense
+		if test.a == nil {
+			m = DenseCopyOf(a)
+			m.Sign(m)
+		} else {
+			m = &Dense{}
+			m.Sign(test.a)
+		}
+		for i, w := range want {
+			v := m.At(i/4, i%4)
+			if math32.IsNaN(w) {
+				if !math32.IsNaN(v) {
+					t.Errorf("unexpected %s result at %d: got: %v want: NaN", test.name, i, v)
+				}
+				continue
+			}
+			if v != w || math32.Signbit(v) != math32.Signbit(w) {
+				t.Errorf("unexpected %s result at %d: got: %v want: %v", test.name, i, v, w)
+			}
+		}
+	}
+}
+
+func TestDenseSymmetrize(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 5, 16} {
+		a := randNormDense(n, n, rnd)
+		want := NewDense(n, n, nil)
+		want.Add(a, a.T())
+		want.Scale(0.5, want)
+
+		for _, test := range []struct {
+			name string
+			a    Matrix
+		}{
+			{name: "raw", a: a},
+			{name: "transposed", a: a.T()},
+			{name: "non-raw", a: asBasicMatrix(a)},
+			{name: "in-place", a: nil},
+		} {
+			var m *Dense
+			if test.a == nil {
+				m = DenseCopyOf(a)
+				m.Symmetrize(m)
+			} else {
+				m = &Dense{}
+				m.Symmetrize(test.a)
+			}
+			for i := 0; i < n; i++ {
+				for j := 0; j < n; j++ {
+					if m.At(i, j) != m.At(j, i) {
+						t.Errorf("%s result for n=%d not exactly symmetric at (%d,%d)", test.name, n, i, j)
+					}
+				}
+			}
+			if Trace(m) != Trace(a) {
+				t.Errorf("unexpected trace of %s result for n=%d: got: %v want: %v", test.name, n, Trace(m), Trace(a))
+			}
+			if !EqualApprox(m, want, 1e-6) {
+				t.Errorf("unexpected %s result for n=%d:\ngot:\n%v\nwant:\n%v", test.name, n, Formatted(m), Formatted(want))
+			}
+		}
+	}
+
+	panicked, message := panics(func() {
+		var m Dense
+		m.Symmetrize(NewDense(2, 3, nil))
+	})
+	if !panicked || message != ErrSquare.Error() {
+		t.Errorf("expected ErrSquare for non-square input: got: %q", message)
+	}
+}
+
+func TestDenseSkewPart(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 5, 16} {
+		a := randNormDense(n, n, rnd)
+		want := NewDense(n, n, nil)
+		want.Sub(a, a.T())
+		want.Scale(0.5, want)
+
+		for _, test := range []struct {
+			name string
+			a    Matrix
+			orig Matrix
+		}{
+			{name: "raw", a: a, orig: a},
+			{name: "transposed", a: a.T(), orig: a.T()},
+			{name: "non-raw", a: asBasicMatrix(a), orig: a},
+			{name: "in-place", a: nil, orig: a},
+		} {
+			var skew, sym *Dense
+			if test.a == nil {
+				skew = DenseCopyOf(a)
+				skew.SkewPart(skew)
+				sym = DenseCopyOf(a)
+				sym.Symmetrize(sym)
+			} else {
+				skew = &Dense{}
+				skew.SkewPart(test.a)
+				sym = &Dense{}
+				sym.Symmetrize(test.a)
+			}
+			for i := 0; i < n; i++ {
+				for j := 0; j < n; j++ {
+					if skew.At(i, j) != -skew.At(j, i) {
+						t.Errorf("%s result for n=%d not exactly skew-symmetric at (%d,%d)", test.name, n, i, j)
+					}
+				}
+			}
+
+			w := want
+			if test.name == "transposed" {
+				w = NewDense(n, n, nil)
+				w.Scale(-1, want)
+			}
+			if !EqualApprox(skew, w, 1e-6) {
+				t.Errorf("unexpected %s result for n=%d:\ngot:\n%v\nwant:\n%v", test.name, n, Formatted(skew), Formatted(w))
+			}
+
+			var sum Dense
+			sum.Add(sym, skew)
+			if !EqualApprox(&sum, test.orig, 1e-6) {
+				t.Errorf("symmetric and skew parts do not reconstruct the %s input for n=%d", test.name, n)
+			}
+		}
+	}
+
+	panicked, message := panics(func() {
+		var m Dense
+		m.SkewPart(NewDense(3, 2, nil))
+	})
+	if !panicked || message != ErrSquare.Error() {
+		t.Errorf("expected ErrSquare for non-square input: got: %q", message)
+	}
+}
+
 func TestScale(t *testing.T) {
 	for _, f := range []float32{0.5, 1, 3} {
 		method := func(receiver, a Matrix) {
@@ -1206,6 +2516,253 @@ func TestCopyVecDenseAlias(t *testing.T) {
 
 func identity(r, c int, v float32) float32 { return v }
 
+func TestScaleRowsCols(t *testing.T) {
+	for i, test := range []struct {
+		a [][]float32
+		d []float32
+	}{
+		{
+			a: [][]float32{{1, 2, 3}, {4, 5, 6}},
+			d: []float32{2, -1},
+		},
+		{
+			a: [][]float32{{1, 2}, {3, 4}, {5, 6}},
+			d: []float32{0, 0.5, 3},
+		},
+		{
+			a: [][]float32{{7}},
+			d: []float32{-2},
+		},
+	} {
+		a := NewDense(flatten(test.a))
+		r, _ := a.Dims()
+		d := NewVecDense(len(test.d), test.d)
+
+		var got, want Dense
+		got.ScaleRows(a, d)
+		want.Mul(NewDiagonalRect(r, r, test.d), a)
+		if !Equal(&got, &want) {
+			t.Errorf("unexpected ScaleRows result for test %d\ngot:\n%v\nwant:\n%v",
+				i, Formatted(&got), Formatted(&want))
+		}
+
+		at := DenseCopyOf(a.T())
+		got.Reset()
+		want.Reset()
+		got.ScaleCols(at, d)
+		want.Mul(at, NewDiagonalRect(r, r, test.d))
+		if !Equal(&got, &want) {
+			t.Errorf("unexpected ScaleCols result for test %d\ngot:\n%v\nwant:\n%v",
+				i, Formatted(&got), Formatted(&want))
+		}
+
+		// In-place scaling.
+		got.Clone(a)
+		got.ScaleRows(&got, d)
+		want.Reset()
+		want.ScaleRows(a, d)
+		if !Equal(&got, &want) {
+			t.Errorf("unexpected in-place ScaleRows result for test %d", i)
+		}
+	}
+
+	for _, fn := range []func(){
+		func() { var m Dense; m.ScaleRows(NewDense(2, 3, nil), NewVecDense(3, nil)) },
+		func() { var m Dense; m.ScaleCols(NewDense(2, 3, nil), NewVecDense(2, nil)) },
+	} {
+		matched, message := panicsWith(fn, ErrShape)
+		if !matched {
+			t.Errorf("expected shape panic: got: %q", message)
+		}
+	}
+}
+
+func TestNormalizeL2(t *testing.T) {
+	for i, test := range []struct {
+		a    []float32
+		r, c int
+		rows []float32
+		cols []float32
+	}{
+		{
+			a: []float32{
+				3, 4,
+				0, 0,
+				-1, 0,
+			},
+			r: 3, c: 2,
+			rows: []float32{
+				0.6, 0.8,
+				0, 0,
+				-1, 0,
+			},
+			cols: []float32{
+				0.9486833, 1,
+				0, 0,
+				-0.31622777, 0,
+			},
+		},
+		{
+			a: []float32{
+				1, 1, 1, 1,
+				0, 2, 0, 0,
+			},
+			r: 2, c: 4,
+			rows: []float32{
+				0.5, 0.5, 0.5, 0.5,
+				0, 1, 0, 0,
+			},
+			cols: []float32{
+				1, 0.4472136, 1, 1,
+				0, 0.8944272, 0, 0,
+			},
+		},
+	} {
+		m := NewDense(test.r, test.c, append([]float32(nil), test.a...))
+		m.NormalizeRowsL2()
+		if !EqualApprox(m, NewDense(test.r, test.c, test.rows), 1e-6) {
+			t.Errorf("unexpected row normalization for test %d:\ngot:\n%v", i, Formatted(m))
+		}
+		for r := 0; r < test.r; r++ {
+			if n := Norm(m.RowView(r), 2); n != 0 && !EqualWithinAbsOrRel(n, 1, 1e-6, 1e-6) {
+				t.Errorf("row %d not unit norm for test %d: %v", r, i, n)
+			}
+		}
+
+		m = NewDense(test.r, test.c, append([]float32(nil), test.a...))
+		m.NormalizeColsL2()
+		if !EqualApprox(m, NewDense(test.r, test.c, test.cols), 1e-6) {
+			t.Errorf("unexpected column normalization for test %d:\ngot:\n%v", i, Formatted(m))
+		}
+
+		// Normalizing a view leaves the surrounding data untouched.
+		big := NewDense(test.r+1, test.c+1, nil)
+		big.Apply(func(_, _ int, _ float32) float32 { return 7 }, big)
+		view := big.Slice(0, test.r, 0, test.c).(*Dense)
+		view.Copy(NewDense(test.r, test.c, test.a))
+		view.NormalizeRowsL2()
+		if !EqualApprox(view, NewDense(test.r, test.c, test.rows), 1e-6) {
+			t.Errorf("unexpected view row normalization for test %d", i)
+		}
+		for r := 0; r <= test.r; r++ {
+			if big.At(r, test.c) != 7 {
+				t.Errorf("normalization modified data outside view for test %d", i)
+				break
+			}
+		}
+	}
+}
+
+func TestNormalizeMetric(t *testing.T) {
+	data := [][]float32{{3, 4}, {0, 0}, {-1, 1}, {2, 0}}
+	orig := NewDense(flatten(data))
+
+	for _, metric := range []Metric{L2, SquaredL2, L1, InnerProduct, Chebyshev} {
+		m := NewDense(flatten(data))
+		m.Normalize(metric)
+		if !Equal(m, orig) {
+			t.Errorf("unexpected modification for metric %d:\n%v", metric, Formatted(m))
+		}
+	}
+
+	m := NewDense(flatten(data))
+	m.Normalize(Cosine)
+	for i := 0; i < len(data); i++ {
+		norm := Norm(m.RowView(i), 2)
+		want := float32(1)
+		if i == 1 {
+			want = 0
+		}
+		if math32.Abs(norm-want) > 1e-6 {
+			t.Errorf("unexpected norm of row %d after cosine normalization: got: %v want: %v", i, norm, want)
+		}
+	}
+	// Cosine distance is preserved by normalization.
+	for i := 0; i < len(data); i++ {
+		for j := 0; j < len(data); j++ {
+			if i == 1 || j == 1 {
+				continue
+			}
+			got := CosineDistance(m.RowView(i), m.RowView(j))
+			want := CosineDistance(orig.RowView(i), orig.RowView(j))
+			if math32.Abs(got-want) > 1e-6 {
+				t.Errorf("unexpected cosine distance between rows %d and %d: got: %v want: %v", i, j, got, want)
+			}
+		}
+	}
+
+	panicked, message := panics(func() { NewDense(1, 1, nil).Normalize(Metric(0)) })
+	if !panicked || message != ErrMetric.Error() {
+		t.Errorf("expected ErrMetric panic: got: %q", message)
+	}
+}
+
+func TestStandardizeCols(t *testing.T) {
+	for i, test := range []struct {
+		a     *Dense
+		means []float32
+		stds  []float32
+	}{
+		{
+			a: NewDense(4, 3, []float32{
+				1, 10, 5,
+				2, 20, 5,
+				3, 30, 5,
+				4, 40, 5,
+			}),
+			means: []float32{2.5, 25, 5},
+			stds:  []float32{1.118034, 11.18034, 0},
+		},
+		{
+			a: NewDense(3, 2, []float32{
+				-1, 0.5,
+				0, 0.5,
+				1, -1,
+			}),
+			means: []float32{0, 0},
+			stds:  []float32{0.8164966, 0.70710678},
+		},
+	} {
+		r, c := test.a.Dims()
+		var m Dense
+		means, stds := m.StandardizeCols(test.a)
+		for j := 0; j < c; j++ {
+			if !EqualWithinAbsOrRel(means[j], test.means[j], 1e-6, 1e-6) {
+				t.Errorf("unexpected mean for test %d column %d: got: %v want: %v", i, j, means[j], test.means[j])
+			}
+			if !EqualWithinAbsOrRel(stds[j], test.stds[j], 1e-6, 1e-6) {
+				t.Errorf("unexpected std for test %d column %d: got: %v want: %v", i, j, stds[j], test.stds[j])
+			}
+
+			var sum, ss float32
+			for k := 0; k < r; k++ {
+				v := m.At(k, j)
+				if v != v {
+					t.Errorf("NaN in output for test %d column %d", i, j)
+				}
+				sum += v
+				ss += v * v
+			}
+			mean := sum / float32(r)
+			variance := ss/float32(r) - mean*mean
+			wantVar := float32(1)
+			if test.stds[j] == 0 {
+				wantVar = 0
+			}
+			if math32.Abs(mean) > 1e-6 || math32.Abs(variance-wantVar) > 1e-5 {
+				t.Errorf("unexpected moments for test %d column %d: mean: %v variance: %v", i, j, mean, variance)
+			}
+		}
+
+		// Standardizing in place gives the same result.
+		inPlace := DenseCopyOf(test.a)
+		inPlace.StandardizeCols(inPlace)
+		if !Equal(inPlace, &m) {
+			t.Errorf("unexpected in-place result for test %d", i)
+		}
+	}
+}
+
 func TestApply(t *testing.T) {
 	for i, test := range []struct {
 		a, want [][]float32
@@ -1335,6 +2892,50 @@ func TestClone(t *testing.T) {
 	}
 }
 
+func TestCloneView(t *testing.T) {
+	for i, test := range []struct {
+		a    Matrix
+		want [][]float32
+	}{
+		{
+			a:    NewDense(2, 3, []float32{1, 2, 3, 4, 5, 6}).T(),
+			want: [][]float32{{1, 4}, {2, 5}, {3, 6}},
+		},
+		{
+			a:    NewDense(3, 4, []float32{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}).Slice(1, 3, 1, 3),
+			want: [][]float32{{6, 7}, {10, 11}},
+		},
+		{
+			a:    NewDense(3, 4, []float32{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}).Slice(0, 2, 1, 4).T(),
+			want: [][]float32{{2, 6}, {3, 7}, {4, 8}},
+		},
+	} {
+		want := NewDense(flatten(test.want))
+
+		var got Dense
+		got.Clone(test.a)
+		if !Equal(&got, want) {
+			t.Errorf("unexpected clone for test %d: got: %v want: %v", i, Formatted(&got), Formatted(want))
+		}
+		r, c := got.Dims()
+		if got.mat.Stride != c || len(got.mat.Data) != r*c {
+			t.Errorf("unexpected storage layout for test %d: stride=%d len=%d", i, got.mat.Stride, len(got.mat.Data))
+		}
+
+		aU, _ := untranspose(test.a)
+		src := aU.(*Dense)
+		sr, sc := src.Dims()
+		for j := 0; j < sr; j++ {
+			for k := 0; k < sc; k++ {
+				src.Set(j, k, -1)
+			}
+		}
+		if !Equal(&got, want) {
+			t.Errorf("unexpected mirror of write to source for test %d: got: %v want: %v", i, Formatted(&got), Formatted(want))
+		}
+	}
+}
+
 // TODO(kortschak) Roll this into testOneInput when it exists.
 func TestCopyPanic(t *testing.T) {
 	for _, a := range []*Dense{
@@ -1643,6 +3244,19 @@ func rowBench(b *testing.B, size int) {
 	}
 }
 
+func BenchmarkPow100_16(b *testing.B)          { powBench(b, 100, 16) }
+func BenchmarkPowIterative100_16(b *testing.B) { powIterativeBench(b, 100, 16) }
+
+func powIterativeBench(b *testing.B, size, n int) {
+	a, _ := randDense(size, 1, randNormFloat32)
+
+	b.ResetTimer()
+	var m Dense
+	for i := 0; i < b.N; i++ {
+		m.iterativePow(a, n)
+	}
+}
+
 func BenchmarkPow10_3(b *testing.B)   { powBench(b, 10, 3) }
 func BenchmarkPow100_3(b *testing.B)  { powBench(b, 100, 3) }
 func BenchmarkPow1000_3(b *testing.B) { powBench(b, 1000, 3) }
@@ -0,0 +1,452 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	mathbits "math/bits"
+
+	"github.com/arjunsk/mat32/internal/asm/f32"
+	"github.com/chewxy/math32"
+
+	"gonum.org/v1/gonum/blas/blas32"
+)
+
+// Metric specifies the distance measure used to compare vectors. For all
+// metrics, smaller distances indicate more similar vectors.
+type Metric int
+
+const (
+	// L2 is the Euclidean distance, sqrt(sum_i (a_i - b_i)^2).
+	L2 Metric = iota + 1
+	// SquaredL2 is the squared Euclidean distance, sum_i (a_i - b_i)^2.
+	// It orders vectors identically to L2 without the square root.
+	SquaredL2
+	// L1 is the Manhattan distance, sum_i |a_i - b_i|.
+	L1
+	// Cosine is the cosine distance, 1 - a·b/(‖a‖‖b‖).
+	Cosine
+	// InnerProduct is the negated inner product, -a·b, so that vectors
+	// with larger inner products are closer.
+	InnerProduct
+	// Chebyshev is the Chebyshev distance, max_i |a_i - b_i|.
+	Chebyshev
+)
+
+// Distance returns the distance between a and b under the given metric.
+// Distance returns ErrShape if a and b have different lengths and ErrMetric
+// if metric is not a known Metric.
+func Distance(metric Metric, a, b Vector) (float32, error) {
+	if a.Len() != b.Len() {
+		return 0, ErrShape
+	}
+	switch metric {
+	case L2:
+		return L2Distance(a, b), nil
+	case SquaredL2:
+		return SquaredL2Distance(a, b), nil
+	case L1:
+		return L1Distance(a, b), nil
+	case Cosine:
+		return CosineDistance(a, b), nil
+	case InnerProduct:
+		return -Dot(a, b), nil
+	case Chebyshev:
+		return ChebyshevDistance(a, b), nil
+	default:
+		return 0, ErrMetric
+	}
+}
+
+// DistanceComputer computes distances from a fixed query vector under a
+// fixed metric. Quantities that depend only on the query, such as its norm
+// for the Cosine metric, are computed once when the DistanceComputer is
+// created rather than for every candidate.
+type DistanceComputer struct {
+	query  *VecDense
+	metric Metric
+	norm   float32
+}
+
+// NewDistanceComputer returns a DistanceComputer for query under the given
+// metric. The query is copied, so later changes to query do not affect the
+// returned DistanceComputer. NewDistanceComputer panics with ErrMetric if
+// metric is not a known Metric.
+func NewDistanceComputer(query Vector, metric Metric) *DistanceComputer {
+	if metric < L2 || metric > Chebyshev {
+		panic(ErrMetric)
+	}
+	q := &VecDense{}
+	if query.Len() != 0 {
+		q = VecDenseCopyOf(query)
+	}
+	d := &DistanceComputer{query: q, metric: metric}
+	if metric == Cosine && q.Len() != 0 {
+		d.norm = Norm(q, 2)
+	}
+	return d
+}
+
+// To returns the distance from the query to v. It is equal to
+//  Distance(metric, query, v)
+// To panics with ErrShape if the length of v does not match the length of
+// the query.
+func (d *DistanceComputer) To(v Vector) float32 {
+	n := d.query.Len()
+	if v.Len() != n {
+		panic(ShapeError{Got: [2]int{v.Len(), 1}, Want: [2]int{n, 1}})
+	}
+	if n == 0 {
+		if d.metric == Cosine {
+			return 1
+		}
+		return 0
+	}
+	switch d.metric {
+	case L2:
+		return L2Distance(d.query, v)
+	case SquaredL2:
+		return SquaredL2Distance(d.query, v)
+	case L1:
+		return L1Distance(d.query, v)
+	case Cosine:
+		if d.norm == 0 {
+			return 1
+		}
+		nv := Norm(v, 2)
+		if nv == 0 {
+			return 1
+		}
+		return 1 - Dot(d.query, v)/(d.norm*nv)
+	case InnerProduct:
+		return -Dot(d.query, v)
+	case Chebyshev:
+		return ChebyshevDistance(d.query, v)
+	default:
+		panic(ErrMetric)
+	}
+}
+
+// L2Distance returns the Euclidean distance between a and b,
+//  sqrt(sum_i (a_i - b_i)^2)
+//
+// L2Distance panics with ErrShape if a and b have different lengths.
+func L2Distance(a, b Vector) float32 {
+	return math32.Sqrt(SquaredL2Distance(a, b))
+}
+
+// L2DistanceWS returns the Euclidean distance between a and b, using scratch
+// as workspace for the difference vector so that no allocation is made. On
+// return the first n elements of scratch hold a - b, where n is the length
+// of a. The norm of the difference is computed with scaling, so unlike
+// L2Distance it does not overflow for large differences.
+//
+// If scratch is empty it is resized to length n, which allocates on the
+// first call only. Otherwise scratch must have length at least n, and
+// L2DistanceWS will panic with ErrShape if it does not or if a and b have
+// different lengths.
+func L2DistanceWS(a, b Vector, scratch *VecDense) float32 {
+	n := a.Len()
+	if b.Len() != n {
+		panic(ShapeError{Got: [2]int{b.Len(), 1}, Want: [2]int{n, 1}})
+	}
+	if n == 0 {
+		return 0
+	}
+	if scratch.IsZero() {
+		scratch.reuseAs(n)
+	}
+	if scratch.Len() < n {
+		panic(ShapeError{Got: [2]int{scratch.Len(), 1}, Want: [2]int{n, 1}})
+	}
+
+	d := scratch.mat
+	if arv, ok := a.(RawVectorer); ok {
+		if brv, ok := b.(RawVectorer); ok {
+			amat := arv.RawVector()
+			bmat := brv.RawVector()
+			for i := 0; i < n; i++ {
+				d.Data[i*d.Inc] = amat.Data[i*amat.Inc] - bmat.Data[i*bmat.Inc]
+			}
+			return blas32.Nrm2(n, d)
+		}
+	}
+	for i := 0; i < n; i++ {
+		d.Data[i*d.Inc] = a.AtVec(i) - b.AtVec(i)
+	}
+	return blas32.Nrm2(n, d)
+}
+
+// L2DistanceIgnoreNaN returns the Euclidean distance between a and b over
+// the dimensions where both a[i] and b[i] are finite, together with the
+// number of such dimensions. Dimensions where either value is NaN, marking
+// a missing value, or infinite are skipped, as in DotIgnoreNaN.
+//
+// To keep distances between pairs with different amounts of missing data
+// comparable, the mean squared difference over the dims shared dimensions
+// is scaled back up to the full length n of the vectors,
+//  sqrt(n/dims * sum_i (a_i - b_i)^2)
+// so that when no values are missing the result equals L2Distance. If no
+// dimension is shared, L2DistanceIgnoreNaN returns NaN and zero.
+//
+// L2DistanceIgnoreNaN panics with ErrShape if a and b have different
+// lengths.
+func L2DistanceIgnoreNaN(a, b Vector) (dist float32, dims int) {
+	n := a.Len()
+	if b.Len() != n {
+		panic(ShapeError{Got: [2]int{b.Len(), 1}, Want: [2]int{n, 1}})
+	}
+	var sum float32
+	for i := 0; i < n; i++ {
+		av := a.AtVec(i)
+		bv := b.AtVec(i)
+		if !isFinite(av) || !isFinite(bv) {
+			continue
+		}
+		d := av - bv
+		sum += d * d
+		dims++
+	}
+	if dims == 0 {
+		return math32.NaN(), 0
+	}
+	return math32.Sqrt(float32(n) / float32(dims) * sum), dims
+}
+
+// SquaredL2Distance returns the squared Euclidean distance between a and b,
+//  sum_i (a_i - b_i)^2
+// computed in a single pass without forming the difference vector.
+//
+// SquaredL2Distance panics with ErrShape if a and b have different lengths.
+func SquaredL2Distance(a, b Vector) float32 {
+	n := a.Len()
+	if b.Len() != n {
+		panic(ShapeError{Got: [2]int{b.Len(), 1}, Want: [2]int{n, 1}})
+	}
+	if n == 0 {
+		return 0
+	}
+	if arv, ok := a.(RawVectorer); ok {
+		if brv, ok := b.(RawVectorer); ok {
+			amat := arv.RawVector()
+			bmat := brv.RawVector()
+			if amat.Inc == 1 && bmat.Inc == 1 {
+				return f32.L2DistSqUnitary(amat.Data[:n], bmat.Data[:n])
+			}
+			return f32.L2DistSqInc(amat.Data, bmat.Data, uintptr(n), uintptr(amat.Inc), uintptr(bmat.Inc), 0, 0)
+		}
+	}
+	var sum float32
+	for i := 0; i < n; i++ {
+		d := a.AtVec(i) - b.AtVec(i)
+		sum += d * d
+	}
+	return sum
+}
+
+// CosineDistance returns the cosine distance between a and b,
+//  1 - a·b/(‖a‖‖b‖)
+// which lies in [0, 2]. If either vector has zero norm the cosine
+// similarity is undefined and CosineDistance returns 1.
+//
+// CosineDistance panics with ErrShape if a and b have different lengths.
+func CosineDistance(a, b Vector) float32 {
+	n := a.Len()
+	if b.Len() != n {
+		panic(ShapeError{Got: [2]int{b.Len(), 1}, Want: [2]int{n, 1}})
+	}
+	if n == 0 {
+		return 1
+	}
+	na := Norm(a, 2)
+	nb := Norm(b, 2)
+	if na == 0 || nb == 0 {
+		return 1
+	}
+	return 1 - Dot(a, b)/(na*nb)
+}
+
+// CrossL2 places the Euclidean distances between every row of a and every
+// row of b into dst,
+//  dst[i,j] = ‖a_i - b_j‖
+// The distances are computed from the expansion
+//  ‖a_i - b_j‖² = ‖a_i‖² + ‖b_j‖² - 2 a_i·b_j
+// using a single matrix product for the inner products. Negative squared
+// distances arising from cancellation are clamped to zero, so distances
+// between nearly identical rows are accurate only to about the square root
+// of machine precision relative to the row norms.
+//
+// CrossL2 panics with ErrShape if a and b have different numbers of columns.
+// If dst is empty it is resized to ra×rb, otherwise it must be ra×rb where
+// ra and rb are the numbers of rows of a and b.
+func CrossL2(dst *Dense, a, b *Dense) {
+	ra, ca := a.Dims()
+	rb, cb := b.Dims()
+	if ca != cb {
+		panic(ShapeError{Got: [2]int{rb, cb}, Want: [2]int{rb, ca}})
+	}
+
+	na := getFloats(ra, false)
+	defer putFloats(na)
+	for i := range na {
+		row := a.RawRowView(i)
+		na[i] = f32.DotUnitary(row, row)
+	}
+	nb := getFloats(rb, false)
+	defer putFloats(nb)
+	for j := range nb {
+		row := b.RawRowView(j)
+		nb[j] = f32.DotUnitary(row, row)
+	}
+
+	dst.reuseAs(ra, rb)
+	dst.Mul(a, b.T())
+	for i := 0; i < ra; i++ {
+		row := dst.RawRowView(i)
+		for j, ab := range row {
+			d := na[i] + nb[j] - 2*ab
+			if d < 0 {
+				d = 0
+			}
+			row[j] = math32.Sqrt(d)
+		}
+	}
+}
+
+// MahalanobisDistance returns the Mahalanobis distance between x and mean
+// under the inverse covariance matrix invCov,
+//  sqrt((x-mean)^T * invCov * (x-mean))
+// Negative values of the quadratic form that arise from rounding error when
+// invCov is positive semi-definite are clamped to zero.
+//
+// MahalanobisDistance panics with ErrShape if the lengths of x and mean
+// differ or do not match the dimension of invCov.
+func MahalanobisDistance(x, mean Vector, invCov Symmetric) float32 {
+	n := x.Len()
+	if mean.Len() != n {
+		panic(ShapeError{Got: [2]int{mean.Len(), 1}, Want: [2]int{n, 1}})
+	}
+	if s := invCov.Symmetric(); s != n {
+		panic(ShapeError{Got: [2]int{s, s}, Want: [2]int{n, n}})
+	}
+	if n == 0 {
+		return 0
+	}
+
+	d := getWorkspaceVec(n, false)
+	defer putWorkspaceVec(d)
+	d.SubVec(x, mean)
+	w := getWorkspaceVec(n, false)
+	defer putWorkspaceVec(w)
+	w.MulVec(invCov, d)
+
+	q := Dot(d, w)
+	if q < 0 {
+		q = 0
+	}
+	return math32.Sqrt(q)
+}
+
+// HammingDistance returns the number of bits that differ between the
+// bit-packed binary codes a and b.
+//
+// The population count is computed with math/bits.OnesCount64, which the
+// compiler lowers to the POPCNT instruction on amd64 when it is available
+// and to a portable bit-twiddling sequence otherwise.
+//
+// HammingDistance panics with ErrShape if a and b have different lengths.
+func HammingDistance(a, b []uint64) int {
+	if len(a) != len(b) {
+		panic(ShapeError{Got: [2]int{len(b), 1}, Want: [2]int{len(a), 1}})
+	}
+	var d int
+	for i, v := range a {
+		d += mathbits.OnesCount64(v ^ b[i])
+	}
+	return d
+}
+
+// L1Distance returns the Manhattan distance between a and b,
+//  sum_i |a_i - b_i|
+// computed in a single pass without forming the difference vector.
+//
+// L1Distance panics with ErrShape if a and b have different lengths.
+func L1Distance(a, b Vector) float32 {
+	n := a.Len()
+	if b.Len() != n {
+		panic(ShapeError{Got: [2]int{b.Len(), 1}, Want: [2]int{n, 1}})
+	}
+	if n == 0 {
+		return 0
+	}
+	if arv, ok := a.(RawVectorer); ok {
+		if brv, ok := b.(RawVectorer); ok {
+			amat := arv.RawVector()
+			bmat := brv.RawVector()
+			if amat.Inc == 1 && bmat.Inc == 1 {
+				return f32.L1DistUnitary(amat.Data[:n], bmat.Data[:n])
+			}
+			return f32.L1DistInc(amat.Data, bmat.Data, uintptr(n), uintptr(amat.Inc), uintptr(bmat.Inc), 0, 0)
+		}
+	}
+	var sum float32
+	for i := 0; i < n; i++ {
+		sum += math32.Abs(a.AtVec(i) - b.AtVec(i))
+	}
+	return sum
+}
+
+// L1DistanceSlice returns the Manhattan distance between a and b.
+//
+// L1DistanceSlice panics with ErrShape if a and b have different lengths.
+func L1DistanceSlice(a, b []float32) float32 {
+	if len(a) != len(b) {
+		panic(ShapeError{Got: [2]int{len(b), 1}, Want: [2]int{len(a), 1}})
+	}
+	return f32.L1DistUnitary(a, b)
+}
+
+// ChebyshevDistance returns the Chebyshev (L∞) distance between a and b,
+//  max_i |a_i - b_i|
+// computed in a single pass without forming the difference vector.
+// As for the other distances, the result is NaN if any difference is NaN.
+//
+// ChebyshevDistance panics with ErrShape if a and b have different lengths.
+func ChebyshevDistance(a, b Vector) float32 {
+	n := a.Len()
+	if b.Len() != n {
+		panic(ShapeError{Got: [2]int{b.Len(), 1}, Want: [2]int{n, 1}})
+	}
+	if n == 0 {
+		return 0
+	}
+	if arv, ok := a.(RawVectorer); ok {
+		if brv, ok := b.(RawVectorer); ok {
+			amat := arv.RawVector()
+			bmat := brv.RawVector()
+			if amat.Inc == 1 && bmat.Inc == 1 {
+				return f32.LinfDistUnitary(amat.Data[:n], bmat.Data[:n])
+			}
+			return f32.LinfDistInc(amat.Data, bmat.Data, uintptr(n), uintptr(amat.Inc), uintptr(bmat.Inc), 0, 0)
+		}
+	}
+	var max float32
+	for i := 0; i < n; i++ {
+		d := math32.Abs(a.AtVec(i) - b.AtVec(i))
+		if d > max || math32.IsNaN(d) {
+			max = d
+		}
+	}
+	return max
+}
+
+// ChebyshevDistanceSlice returns the Chebyshev (L∞) distance between a and b.
+//
+// ChebyshevDistanceSlice panics with ErrShape if a and b have different lengths.
+func ChebyshevDistanceSlice(a, b []float32) float32 {
+	if len(a) != len(b) {
+		panic(ShapeError{Got: [2]int{len(b), 1}, Want: [2]int{len(a), 1}})
+	}
+	return f32.LinfDistUnitary(a, b)
+}
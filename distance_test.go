@@ -0,0 +1,568 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"testing"
+
+	"github.com/chewxy/math32"
+	"golang.org/x/exp/rand"
+)
+
+func TestMahalanobisDistance(t *testing.T) {
+	for i, test := range []struct {
+		x, mean []float32
+		invCov  *SymDense
+		want    float32
+	}{
+		{
+			x:      []float32{1, 2, 3},
+			mean:   []float32{1, 2, 3},
+			invCov: NewSymDense(3, []float32{1, 0, 0, 0, 1, 0, 0, 0, 1}),
+			want:   0,
+		},
+		{
+			// Identity inverse covariance reduces to the Euclidean distance.
+			x:      []float32{3, 4},
+			mean:   []float32{0, 0},
+			invCov: NewSymDense(2, []float32{1, 0, 0, 1}),
+			want:   5,
+		},
+		{
+			// Diagonal inverse covariance reduces to a weighted Euclidean distance.
+			x:      []float32{1, -2, 5},
+			mean:   []float32{0, 1, 3},
+			invCov: NewSymDense(3, []float32{4, 0, 0, 0, 0.5, 0, 0, 0, 0.25}),
+			want:   math32.Sqrt(4*1*1 + 0.5*3*3 + 0.25*2*2),
+		},
+		{
+			x:      []float32{1, 1},
+			mean:   []float32{0, 0},
+			invCov: NewSymDense(2, []float32{2, 1, 1, 2}),
+			want:   math32.Sqrt(6),
+		},
+	} {
+		x := NewVecDense(len(test.x), test.x)
+		mean := NewVecDense(len(test.mean), test.mean)
+		got := MahalanobisDistance(x, mean, test.invCov)
+		if !EqualWithinAbsOrRel(got, test.want, 1e-6, 1e-6) {
+			t.Errorf("unexpected distance for test %d: got: %v want: %v", i, got, test.want)
+		}
+	}
+
+	matched, message := panicsWith(func() {
+		MahalanobisDistance(NewVecDense(2, nil), NewVecDense(3, nil), NewSymDense(2, nil))
+	}, ErrShape)
+	if !matched {
+		t.Errorf("expected ErrShape for mismatched lengths: got: %q", message)
+	}
+}
+
+func TestHammingDistance(t *testing.T) {
+	for i, test := range []struct {
+		a, b []uint64
+		want int
+	}{
+		{a: nil, b: nil, want: 0},
+		{a: []uint64{0}, b: []uint64{0}, want: 0},
+		{a: []uint64{0}, b: []uint64{^uint64(0)}, want: 64},
+		{a: []uint64{0xF0F0}, b: []uint64{0x0F0F}, want: 16},
+		{a: []uint64{1 << 63, 1}, b: []uint64{0, 0}, want: 2},
+		{
+			a:    []uint64{0xAAAAAAAAAAAAAAAA, 0xFFFF, 0x8000000000000001},
+			b:    []uint64{0x5555555555555555, 0xFFFF, 0x0000000000000001},
+			want: 65,
+		},
+	} {
+		got := HammingDistance(test.a, test.b)
+		if got != test.want {
+			t.Errorf("unexpected distance for test %d: got: %d want: %d", i, got, test.want)
+		}
+		if rev := HammingDistance(test.b, test.a); rev != got {
+			t.Errorf("asymmetric distance for test %d: got: %d and %d", i, got, rev)
+		}
+	}
+
+	matched, message := panicsWith(func() { HammingDistance(make([]uint64, 1), make([]uint64, 2)) }, ErrShape)
+	if !matched {
+		t.Errorf("expected ErrShape for mismatched lengths: got: %q", message)
+	}
+}
+
+func TestL1Distance(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	naive := func(a, b []float32) float32 {
+		var sum float32
+		for i, v := range a {
+			sum += math32.Abs(v - b[i])
+		}
+		return sum
+	}
+	for i, test := range []struct {
+		a, b []float32
+	}{
+		{a: []float32{1}, b: []float32{1}},
+		{a: []float32{1, 2, 3}, b: []float32{3, 2, 1}},
+		{a: []float32{-1, 0.5, 4, -8}, b: []float32{2, 0.5, -4, 1}},
+		{a: randSlice(17, rnd), b: randSlice(17, rnd)},
+		{a: randSlice(100, rnd), b: randSlice(100, rnd)},
+	} {
+		want := naive(test.a, test.b)
+		n := len(test.a)
+
+		if got := L1DistanceSlice(test.a, test.b); !EqualWithinAbsOrRel(got, want, 1e-5, 1e-5) {
+			t.Errorf("unexpected slice distance for test %d: got: %v want: %v", i, got, want)
+		}
+		a := NewVecDense(n, test.a)
+		b := NewVecDense(n, test.b)
+		if got := L1Distance(a, b); !EqualWithinAbsOrRel(got, want, 1e-5, 1e-5) {
+			t.Errorf("unexpected unitary distance for test %d: got: %v want: %v", i, got, want)
+		}
+
+		// Strided views of the same data.
+		ad := NewDense(n, 2, nil)
+		bd := NewDense(n, 3, nil)
+		for j := 0; j < n; j++ {
+			ad.Set(j, 0, test.a[j])
+			bd.Set(j, 2, test.b[j])
+		}
+		ac := ad.ColView(0)
+		bc := bd.ColView(2)
+		if got := L1Distance(ac, bc); !EqualWithinAbsOrRel(got, want, 1e-5, 1e-5) {
+			t.Errorf("unexpected strided distance for test %d: got: %v want: %v", i, got, want)
+		}
+
+		if got := L1Distance(&basicVector{test.a}, &basicVector{test.b}); !EqualWithinAbsOrRel(got, want, 1e-5, 1e-5) {
+			t.Errorf("unexpected non-raw distance for test %d: got: %v want: %v", i, got, want)
+		}
+	}
+
+	matched, message := panicsWith(func() { L1Distance(NewVecDense(2, nil), NewVecDense(3, nil)) }, ErrShape)
+	if !matched {
+		t.Errorf("expected ErrShape for mismatched lengths: got: %q", message)
+	}
+	matched, message = panicsWith(func() { L1DistanceSlice(make([]float32, 2), make([]float32, 3)) }, ErrShape)
+	if !matched {
+		t.Errorf("expected ErrShape for mismatched slice lengths: got: %q", message)
+	}
+}
+
+func TestChebyshevDistance(t *testing.T) {
+	for i, test := range []struct {
+		a, b []float32
+		want float32
+	}{
+		{a: []float32{1, 2, 3}, b: []float32{1, 2, 3}, want: 0},
+		{a: []float32{0}, b: []float32{-4}, want: 4},
+		{a: []float32{1, 2, 3, 4}, b: []float32{1.5, 2, -3, 3}, want: 6},
+		{a: []float32{0, 0, 0, 0, 0}, b: []float32{1, -2, 1, 0, 7}, want: 7},
+		{a: []float32{math32.NaN(), 0, 0}, b: []float32{0, 3, 9}, want: math32.NaN()},
+		{a: []float32{1, 2, 3}, b: []float32{8, math32.NaN(), 3}, want: math32.NaN()},
+	} {
+		same := func(got float32) bool {
+			return got == test.want || math32.IsNaN(got) && math32.IsNaN(test.want)
+		}
+		n := len(test.a)
+		if got := ChebyshevDistanceSlice(test.a, test.b); !same(got) {
+			t.Errorf("unexpected slice distance for test %d: got: %v want: %v", i, got, test.want)
+		}
+		if got := ChebyshevDistance(NewVecDense(n, test.a), NewVecDense(n, test.b)); !same(got) {
+			t.Errorf("unexpected distance for test %d: got: %v want: %v", i, got, test.want)
+		}
+
+		ad := NewDense(n, 2, nil)
+		ad.SetCol(1, test.a)
+		if got := ChebyshevDistance(ad.ColView(1), NewVecDense(n, test.b)); !same(got) {
+			t.Errorf("unexpected strided distance for test %d: got: %v want: %v", i, got, test.want)
+		}
+		if got := ChebyshevDistance(&basicVector{test.a}, &basicVector{test.b}); !same(got) {
+			t.Errorf("unexpected non-raw distance for test %d: got: %v want: %v", i, got, test.want)
+		}
+	}
+
+	matched, message := panicsWith(func() { ChebyshevDistanceSlice(make([]float32, 2), make([]float32, 3)) }, ErrShape)
+	if !matched {
+		t.Errorf("expected ErrShape for mismatched lengths: got: %q", message)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	individual := map[Metric]func(a, b Vector) float32{
+		L2:           L2Distance,
+		SquaredL2:    SquaredL2Distance,
+		L1:           L1Distance,
+		Cosine:       CosineDistance,
+		InnerProduct: func(a, b Vector) float32 { return -Dot(a, b) },
+		Chebyshev:    ChebyshevDistance,
+	}
+	for i, test := range []struct {
+		a, b []float32
+		want map[Metric]float32
+	}{
+		{
+			a: []float32{1, 0},
+			b: []float32{0, 1},
+			want: map[Metric]float32{
+				L2:           math32.Sqrt(2),
+				SquaredL2:    2,
+				L1:           2,
+				Cosine:       1,
+				InnerProduct: 0,
+				Chebyshev:    1,
+			},
+		},
+		{
+			a: []float32{1, 2, 3},
+			b: []float32{2, 4, 6},
+			want: map[Metric]float32{
+				L2:           math32.Sqrt(14),
+				SquaredL2:    14,
+				L1:           6,
+				Cosine:       0,
+				InnerProduct: -28,
+				Chebyshev:    3,
+			},
+		},
+		{
+			a: []float32{0, 0},
+			b: []float32{3, 4},
+			want: map[Metric]float32{
+				L2:           5,
+				SquaredL2:    25,
+				L1:           7,
+				Cosine:       1,
+				InnerProduct: 0,
+				Chebyshev:    4,
+			},
+		},
+		{a: randSlice(33, rnd), b: randSlice(33, rnd)},
+	} {
+		n := len(test.a)
+		a := NewVecDense(n, test.a)
+		b := NewVecDense(n, test.b)
+		for _, metric := range []Metric{L2, SquaredL2, L1, Cosine, InnerProduct, Chebyshev} {
+			got, err := Distance(metric, a, b)
+			if err != nil {
+				t.Errorf("unexpected error for test %d metric %d: %v", i, metric, err)
+				continue
+			}
+			if want := individual[metric](a, b); got != want {
+				t.Errorf("dispatch mismatch for test %d metric %d: got: %v want: %v", i, metric, got, want)
+			}
+			if want, ok := test.want[metric]; ok && !EqualWithinAbsOrRel(got, want, 1e-6, 1e-6) {
+				t.Errorf("unexpected distance for test %d metric %d: got: %v want: %v", i, metric, got, want)
+			}
+			gotBasic, _ := Distance(metric, &basicVector{test.a}, &basicVector{test.b})
+			if !EqualWithinAbsOrRel(gotBasic, got, 1e-5, 1e-5) {
+				t.Errorf("unexpected non-raw distance for test %d metric %d: got: %v want: %v", i, metric, gotBasic, got)
+			}
+		}
+	}
+
+	if _, err := Distance(L2, NewVecDense(2, nil), NewVecDense(3, nil)); err != ErrShape {
+		t.Errorf("unexpected error for mismatched lengths: got: %v want: %v", err, ErrShape)
+	}
+	if _, err := Distance(0, NewVecDense(2, nil), NewVecDense(2, nil)); err != ErrMetric {
+		t.Errorf("unexpected error for unknown metric: got: %v want: %v", err, ErrMetric)
+	}
+}
+
+func TestL2DistanceWS(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i, n := range []int{1, 3, 17, 100} {
+		x := randSlice(n, rnd)
+		y := randSlice(n, rnd)
+		a := NewVecDense(n, x)
+		b := NewVecDense(n, y)
+		want := L2Distance(a, b)
+
+		var scratch VecDense
+		got := L2DistanceWS(a, b, &scratch)
+		if !EqualWithinRel(got, want, 1e-5) {
+			t.Errorf("unexpected distance for test %d: got: %v want: %v", i, got, want)
+		}
+		if scratch.Len() != n {
+			t.Errorf("unexpected scratch length for test %d: got: %d want: %d", i, scratch.Len(), n)
+		}
+		for j := 0; j < n; j++ {
+			if scratch.AtVec(j) != x[j]-y[j] {
+				t.Errorf("unexpected difference for test %d at %d: got: %v want: %v", i, j, scratch.AtVec(j), x[j]-y[j])
+			}
+		}
+
+		// A longer and strided scratch vector is used in part.
+		long := NewDense(n+2, 2, nil).ColView(1).(*VecDense)
+		got = L2DistanceWS(&basicVector{x}, &basicVector{y}, long)
+		if !EqualWithinRel(got, want, 1e-5) {
+			t.Errorf("unexpected distance for non-raw test %d: got: %v want: %v", i, got, want)
+		}
+	}
+
+	// Large differences do not overflow.
+	big := L2DistanceWS(NewVecDense(2, []float32{3e30, 4e30}), NewVecDense(2, nil), &VecDense{})
+	if !EqualWithinRel(big, 5e30, 1e-6) {
+		t.Errorf("unexpected distance for large vectors: got: %v want: 5e30", big)
+	}
+
+	matched, message := panicsWith(func() {
+		L2DistanceWS(NewVecDense(3, nil), NewVecDense(3, nil), NewVecDense(2, nil))
+	}, ErrShape)
+	if !matched {
+		t.Errorf("expected ErrShape for short scratch: got: %q", message)
+	}
+
+	a := NewVecDense(64, randSlice(64, rnd))
+	b := NewVecDense(64, randSlice(64, rnd))
+	var scratch VecDense
+	L2DistanceWS(a, b, &scratch)
+	allocs := testing.AllocsPerRun(10, func() {
+		L2DistanceWS(a, b, &scratch)
+	})
+	if allocs != 0 {
+		t.Errorf("unexpected allocations after first call: got: %v want: 0", allocs)
+	}
+}
+
+func TestL2DistanceIgnoreNaN(t *testing.T) {
+	nan := math32.NaN()
+	for i, test := range []struct {
+		a, b []float32
+		dist float32
+		dims int
+	}{
+		{
+			// Fully observed pairs match L2Distance.
+			a: []float32{1, 2, 3}, b: []float32{4, 6, 3},
+			dist: 5, dims: 3,
+		},
+		{
+			// The squared difference of 9 over 1 of 4
+			// dimensions is scaled up by 4.
+			a: []float32{1, nan, 2, 5}, b: []float32{4, 0, nan, nan},
+			dist: 6, dims: 1,
+		},
+		{
+			// 3² + 4² over 2 of 8 dimensions is scaled up by 4.
+			a: []float32{0, nan, 0, nan, 1, 2, 3, 4}, b: []float32{3, 1, 4, 2, nan, nan, math32.Inf(1), nan},
+			dist: 10, dims: 2,
+		},
+		{
+			a: []float32{nan, 1}, b: []float32{2, nan},
+			dist: nan, dims: 0,
+		},
+	} {
+		n := len(test.a)
+		for _, args := range [][2]Vector{
+			{NewVecDense(n, test.a), NewVecDense(n, test.b)},
+			{&basicVector{test.a}, &basicVector{test.b}},
+		} {
+			dist, dims := L2DistanceIgnoreNaN(args[0], args[1])
+			if dims != test.dims {
+				t.Errorf("unexpected dims for test %d: got: %d want: %d", i, dims, test.dims)
+			}
+			if math32.IsNaN(test.dist) {
+				if !math32.IsNaN(dist) {
+					t.Errorf("unexpected distance for test %d: got: %v want: NaN", i, dist)
+				}
+				continue
+			}
+			if !EqualWithinAbsOrRel(dist, test.dist, 1e-6, 1e-6) {
+				t.Errorf("unexpected distance for test %d: got: %v want: %v", i, dist, test.dist)
+			}
+			if dims == n {
+				if want := L2Distance(args[0], args[1]); !EqualWithinAbsOrRel(dist, want, 1e-6, 1e-6) {
+					t.Errorf("fully observed distance differs from L2Distance for test %d: got: %v want: %v", i, dist, want)
+				}
+			}
+		}
+	}
+
+	matched, message := panicsWith(func() { L2DistanceIgnoreNaN(NewVecDense(2, nil), NewVecDense(3, nil)) }, ErrShape)
+	if !matched {
+		t.Errorf("expected ErrShape for mismatched lengths: got: %q", message)
+	}
+}
+
+func TestDistanceComputer(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i, test := range []struct {
+		query []float32
+		cands [][]float32
+	}{
+		{
+			query: []float32{1, 0},
+			cands: [][]float32{{0, 1}, {1, 0}, {0, 0}, {-3, 4}},
+		},
+		{
+			query: []float32{0, 0, 0},
+			cands: [][]float32{{1, 2, 3}, {0, 0, 0}},
+		},
+		{
+			query: randSlice(33, rnd),
+			cands: [][]float32{randSlice(33, rnd), randSlice(33, rnd), randSlice(33, rnd)},
+		},
+	} {
+		q := NewVecDense(len(test.query), test.query)
+		for _, metric := range []Metric{L2, SquaredL2, L1, Cosine, InnerProduct, Chebyshev} {
+			dc := NewDistanceComputer(q, metric)
+			for j, c := range test.cands {
+				for _, v := range []Vector{NewVecDense(len(c), c), &basicVector{c}} {
+					got := dc.To(v)
+					want, err := Distance(metric, q, v)
+					if err != nil {
+						t.Fatalf("unexpected error for test %d metric %d: %v", i, metric, err)
+					}
+					if !EqualWithinAbsOrRel(got, want, 1e-6, 1e-6) {
+						t.Errorf("unexpected distance for test %d metric %d candidate %d: got: %v want: %v",
+							i, metric, j, got, want)
+					}
+				}
+			}
+		}
+	}
+
+	// The query is copied.
+	query := []float32{1, 2}
+	dc := NewDistanceComputer(NewVecDense(2, query), L2)
+	query[0] = 100
+	if got := dc.To(NewVecDense(2, []float32{1, 2})); got != 0 {
+		t.Errorf("unexpected dependence on modified query: got: %v want: 0", got)
+	}
+
+	matched, message := panicsWith(func() { dc.To(NewVecDense(3, nil)) }, ErrShape)
+	if !matched {
+		t.Errorf("expected ErrShape for mismatched length: got: %q", message)
+	}
+	panicked, message := panics(func() { NewDistanceComputer(NewVecDense(2, nil), 0) })
+	if !panicked || message != ErrMetric.Error() {
+		t.Errorf("expected ErrMetric for unknown metric: got: %q", message)
+	}
+}
+
+func TestCrossL2(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i, test := range []struct {
+		a, b *Dense
+	}{
+		{
+			a: NewDense(2, 2, []float32{0, 0, 3, 4}),
+			b: NewDense(3, 2, []float32{0, 0, 3, 4, 6, 8}),
+		},
+		{a: randNormDense(1, 5, rnd), b: randNormDense(7, 5, rnd)},
+		{a: randNormDense(9, 3, rnd), b: randNormDense(4, 3, rnd)},
+		{a: randNormDense(16, 32, rnd), b: randNormDense(16, 32, rnd)},
+	} {
+		ra, _ := test.a.Dims()
+		rb, _ := test.b.Dims()
+		want := NewDense(ra, rb, nil)
+		for r := 0; r < ra; r++ {
+			for c := 0; c < rb; c++ {
+				want.Set(r, c, L2Distance(test.a.RowView(r), test.b.RowView(c)))
+			}
+		}
+		var got Dense
+		CrossL2(&got, test.a, test.b)
+		if !EqualApprox(&got, want, 1e-3) {
+			t.Errorf("unexpected distances for test %d:\ngot:\n%v\nwant:\n%v", i, Formatted(&got), Formatted(want))
+		}
+	}
+
+	// Distances of rows to themselves are clamped to be non-negative.
+	a := randNormDense(10, 8, rnd)
+	var self Dense
+	CrossL2(&self, a, a)
+	for i := 0; i < 10; i++ {
+		if d := self.At(i, i); d < 0 || d > 1e-2 || d != d {
+			t.Errorf("unexpected self distance for row %d: %v", i, d)
+		}
+	}
+
+	matched, message := panicsWith(func() {
+		var dst Dense
+		CrossL2(&dst, NewDense(2, 3, nil), NewDense(2, 2, nil))
+	}, ErrShape)
+	if !matched {
+		t.Errorf("expected ErrShape for mismatched columns: got: %q", message)
+	}
+}
+
+// randSlice returns a slice of n elements drawn from the standard
+// normal distribution.
+func randSlice(n int, rnd *rand.Rand) []float32 {
+	s := make([]float32, n)
+	for i := range s {
+		s[i] = float32(rnd.NormFloat64())
+	}
+	return s
+}
+
+func BenchmarkL1DistanceSlice(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	x := randSlice(1000, rnd)
+	y := randSlice(1000, rnd)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		L1DistanceSlice(x, y)
+	}
+}
+
+func BenchmarkL1DistanceMaterialized(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	x := NewVecDense(1000, randSlice(1000, rnd))
+	y := NewVecDense(1000, randSlice(1000, rnd))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var d VecDense
+		d.SubVec(x, y)
+		Norm(&d, 1)
+	}
+}
+
+func BenchmarkL2DistanceWS(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	x := NewVecDense(1000, randSlice(1000, rnd))
+	y := NewVecDense(1000, randSlice(1000, rnd))
+	var scratch VecDense
+	L2DistanceWS(x, y, &scratch)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		L2DistanceWS(x, y, &scratch)
+	}
+}
+
+func BenchmarkCrossL2(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	x := randNormDense(256, 64, rnd)
+	y := randNormDense(256, 64, rnd)
+	var dst Dense
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		CrossL2(&dst, x, y)
+	}
+}
+
+func BenchmarkCrossL2Naive(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	x := randNormDense(256, 64, rnd)
+	y := randNormDense(256, 64, rnd)
+	dst := NewDense(256, 256, nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for r := 0; r < 256; r++ {
+			xr := x.RawRowView(r)
+			for c := 0; c < 256; c++ {
+				yc := y.RawRowView(c)
+				var sum float32
+				for k, v := range xr {
+					d := v - yc[k]
+					sum += d * d
+				}
+				dst.Set(r, c, math32.Sqrt(sum))
+			}
+		}
+	}
+}
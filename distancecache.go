@@ -0,0 +1,167 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"container/list"
+	"hash/fnv"
+	"math"
+	"sync"
+)
+
+// DistanceCache is a fixed-capacity cache of pairwise distance matrices,
+// such as the result of CrossL2(dst, m, m), keyed by the contents of the
+// input matrix m. When the cache is full, adding an entry evicts the least
+// recently used one.
+//
+// Inputs and results are copied on the way in and out, so callers may
+// modify matrices passed to or returned from the cache. A DistanceCache is
+// safe for concurrent use by multiple goroutines.
+type DistanceCache struct {
+	mu      sync.Mutex
+	cap     int
+	lru     *list.List
+	entries map[uint64][]*list.Element
+}
+
+type distanceCacheEntry struct {
+	key    uint64
+	input  *Dense
+	result *Dense
+}
+
+// NewDistanceCache returns a DistanceCache holding at most capacity
+// results. NewDistanceCache panics if capacity is not positive.
+func NewDistanceCache(capacity int) *DistanceCache {
+	if capacity <= 0 {
+		panic("mat: non-positive distance cache capacity")
+	}
+	return &DistanceCache{
+		cap:     capacity,
+		lru:     list.New(),
+		entries: make(map[uint64][]*list.Element),
+	}
+}
+
+// Get returns a copy of the result stored for the input m, and whether
+// one was found. A hit marks the entry as most recently used.
+func (c *DistanceCache) Get(m *Dense) (*Dense, bool) {
+	key := fingerprint(m)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e := c.lookup(key, m)
+	if e == nil {
+		return nil, false
+	}
+	c.lru.MoveToFront(e)
+	return DenseCopyOf(e.Value.(*distanceCacheEntry).result), true
+}
+
+// Put stores a copy of result for the input m, replacing any result
+// already stored for m, and evicts the least recently used entry if the
+// cache would otherwise exceed its capacity.
+func (c *DistanceCache) Put(m *Dense, result *Dense) {
+	key := fingerprint(m)
+	result = DenseCopyOf(result)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e := c.lookup(key, m); e != nil {
+		e.Value.(*distanceCacheEntry).result = result
+		c.lru.MoveToFront(e)
+		return
+	}
+	if c.lru.Len() >= c.cap {
+		c.remove(c.lru.Back())
+	}
+	e := c.lru.PushFront(&distanceCacheEntry{
+		key:    key,
+		input:  DenseCopyOf(m),
+		result: result,
+	})
+	c.entries[key] = append(c.entries[key], e)
+}
+
+// Len returns the number of results held in the cache.
+func (c *DistanceCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// lookup returns the list element holding the input m with the given key,
+// or nil if there is none. The stored input is compared element-wise so
+// that fingerprint collisions cannot return a result for another input.
+func (c *DistanceCache) lookup(key uint64, m *Dense) *list.Element {
+	for _, e := range c.entries[key] {
+		if sameBits(e.Value.(*distanceCacheEntry).input, m) {
+			return e
+		}
+	}
+	return nil
+}
+
+// sameBits returns whether a and b have the same dimensions and element
+// bit patterns. Unlike Equal, it matches inputs the way fingerprint hashes
+// them, so an input holding NaN is equal to a copy of itself.
+func sameBits(a, b *Dense) bool {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ar != br || ac != bc {
+		return false
+	}
+	for i := 0; i < ar; i++ {
+		arow := a.mat.Data[i*a.mat.Stride : i*a.mat.Stride+ac]
+		brow := b.mat.Data[i*b.mat.Stride : i*b.mat.Stride+bc]
+		for j, v := range arow {
+			if math.Float32bits(v) != math.Float32bits(brow[j]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// remove deletes the list element e from the cache.
+func (c *DistanceCache) remove(e *list.Element) {
+	key := e.Value.(*distanceCacheEntry).key
+	bucket := c.entries[key]
+	for i, b := range bucket {
+		if b == e {
+			bucket = append(bucket[:i], bucket[i+1:]...)
+			break
+		}
+	}
+	if len(bucket) == 0 {
+		delete(c.entries, key)
+	} else {
+		c.entries[key] = bucket
+	}
+	c.lru.Remove(e)
+}
+
+// fingerprint returns an FNV-1a hash of the dimensions and element bit
+// patterns of m. Matrices with equal contents have equal fingerprints
+// regardless of stride.
+func fingerprint(m *Dense) uint64 {
+	h := fnv.New64a()
+	r, c := m.Dims()
+	var buf [8]byte
+	put := func(v uint64, n int) {
+		for i := 0; i < n; i++ {
+			buf[i] = byte(v >> (8 * i))
+		}
+		h.Write(buf[:n])
+	}
+	put(uint64(r), 8)
+	put(uint64(c), 8)
+	for i := 0; i < r; i++ {
+		for _, v := range m.mat.Data[i*m.mat.Stride : i*m.mat.Stride+c] {
+			put(uint64(math.Float32bits(v)), 4)
+		}
+	}
+	return h.Sum64()
+}
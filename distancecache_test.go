@@ -0,0 +1,132 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/chewxy/math32"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestDistanceCache(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	inputs := make([]*Dense, 4)
+	results := make([]*Dense, len(inputs))
+	for i := range inputs {
+		inputs[i] = randNormDense(5, 3, rnd)
+		results[i] = &Dense{}
+		CrossL2(results[i], inputs[i], inputs[i])
+	}
+
+	c := NewDistanceCache(2)
+	if _, ok := c.Get(inputs[0]); ok {
+		t.Errorf("unexpected hit in empty cache")
+	}
+
+	c.Put(inputs[0], results[0])
+	got, ok := c.Get(inputs[0])
+	if !ok {
+		t.Fatalf("unexpected miss for stored input")
+	}
+	if !Equal(got, results[0]) {
+		t.Errorf("unexpected cached result: got: %v want: %v", Formatted(got), Formatted(results[0]))
+	}
+
+	// Lookups are by content, not by identity or stride.
+	view := NewDense(7, 5, nil).Slice(1, 6, 1, 4).(*Dense)
+	view.Copy(inputs[0])
+	if _, ok := c.Get(view); !ok {
+		t.Errorf("unexpected miss for equal input with different storage")
+	}
+
+	// Returned results are copies.
+	got.Set(0, 0, 100)
+	if got, _ := c.Get(inputs[0]); !Equal(got, results[0]) {
+		t.Errorf("cached result modified through returned matrix")
+	}
+
+	// Fill the cache, then touch input 0 so that input 1
+	// is the least recently used entry.
+	c.Put(inputs[1], results[1])
+	c.Get(inputs[0])
+	c.Put(inputs[2], results[2])
+	if c.Len() != 2 {
+		t.Errorf("unexpected cache length: got: %d want: %d", c.Len(), 2)
+	}
+	for i, want := range []bool{true, false, true, false} {
+		if _, ok := c.Get(inputs[i]); ok != want {
+			t.Errorf("unexpected hit status for input %d after eviction: got: %t want: %t", i, ok, want)
+		}
+	}
+
+	// Replacing an existing entry does not evict.
+	c.Put(inputs[2], results[3])
+	if c.Len() != 2 {
+		t.Errorf("unexpected cache length after replacement: got: %d want: %d", c.Len(), 2)
+	}
+	if got, _ := c.Get(inputs[2]); !Equal(got, results[3]) {
+		t.Errorf("unexpected replaced result")
+	}
+	if _, ok := c.Get(inputs[0]); !ok {
+		t.Errorf("unexpected eviction on replacement")
+	}
+
+	// Inputs holding NaN are matched by bit pattern, so repeated
+	// stores of the same input share one entry.
+	c = NewDistanceCache(2)
+	nan := NewDense(2, 2, []float32{1, math32.NaN(), 3, 4})
+	c.Put(inputs[0], results[0])
+	for i := 0; i < 3; i++ {
+		c.Put(DenseCopyOf(nan), results[i])
+	}
+	if c.Len() != 2 {
+		t.Errorf("unexpected cache length after repeated NaN input: got: %d want: %d", c.Len(), 2)
+	}
+	if got, ok := c.Get(nan); !ok || !Equal(got, results[2]) {
+		t.Errorf("unexpected miss or result for NaN input")
+	}
+	if _, ok := c.Get(inputs[0]); !ok {
+		t.Errorf("unexpected eviction by repeated NaN input")
+	}
+
+	panicked, _ := panics(func() { NewDistanceCache(0) })
+	if !panicked {
+		t.Errorf("expected panic for zero capacity")
+	}
+}
+
+func TestDistanceCacheConcurrent(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	inputs := make([]*Dense, 8)
+	results := make([]*Dense, len(inputs))
+	for i := range inputs {
+		inputs[i] = randNormDense(4, 2, rnd)
+		results[i] = &Dense{}
+		CrossL2(results[i], inputs[i], inputs[i])
+	}
+
+	c := NewDistanceCache(4)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for k := 0; k < 100; k++ {
+				i := (g + k) % len(inputs)
+				if got, ok := c.Get(inputs[i]); ok && !Equal(got, results[i]) {
+					t.Errorf("unexpected result for input %d", i)
+				}
+				c.Put(inputs[i], results[i])
+			}
+		}(g)
+	}
+	wg.Wait()
+	if c.Len() > 4 {
+		t.Errorf("cache exceeded capacity: got: %d want: <= %d", c.Len(), 4)
+	}
+}
@@ -123,6 +123,14 @@
 // value. If there are specific special cases that are needed, please submit a
 // pull-request or file an issue.
 //
+// Concurrency
+//
+// Matrix values are not safe for concurrent mutation. Concurrent reads, such
+// as At, AtVec, Dot and the use of a matrix as a non-receiver argument, are
+// safe provided no goroutine modifies the data being read. Distinct receivers
+// that do not share backing data may be written concurrently. The workspace
+// pools used internally by operations are safe for concurrent use.
+//
 // Invariants
 //
 // Matrix input arguments to functions are never directly modified. If an operation
@@ -0,0 +1,266 @@
+// Copyright ©2013 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"sort"
+
+	"github.com/chewxy/math32"
+
+	"gonum.org/v1/gonum/blas/blas32"
+)
+
+const (
+	badFact = "mat: use without successful factorization"
+
+	// maxJacobiSweeps is the maximum number of sweeps over the
+	// off-diagonal elements performed by the Jacobi methods.
+	maxJacobiSweeps = 100
+)
+
+// EigenSym is a type for creating and manipulating the Eigen decomposition of
+// symmetric matrices.
+type EigenSym struct {
+	values  []float32
+	vectors *Dense
+}
+
+// Factorize computes the eigenvalue decomposition of the symmetric matrix a.
+// The Eigen decomposition is defined as
+//  A = P * D * P^T
+// where D is a diagonal matrix containing the eigenvalues of the matrix, and
+// P is an orthogonal matrix of the eigenvectors of A. Factorize computes the
+// eigenvalues in ascending order.
+//
+// The decomposition is computed with the cyclic Jacobi rotation method.
+// Sweeps of rotations are applied until the Frobenius norm of the
+// off-diagonal part is at most epsilon times the Frobenius norm of a.
+// If epsilon is not positive, a tolerance suitable for float32 is used.
+//
+// Factorize returns whether the decomposition converged within the sweep
+// limit. If the decomposition failed, methods that require a successful
+// factorization will panic.
+func (e *EigenSym) Factorize(a Symmetric, epsilon float32) (ok bool) {
+	n := a.Symmetric()
+	if n == 0 {
+		panic(ErrZeroLength)
+	}
+	if epsilon <= 0 {
+		epsilon = 1e-6
+	}
+
+	w := NewDense(n, n, nil)
+	w.Copy(a)
+	v := NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		v.mat.Data[i*n+i] = 1
+	}
+	ok = jacobiEigen(w.mat.Data, v.mat.Data, n, epsilon)
+	if !ok {
+		e.values = nil
+		e.vectors = nil
+		return false
+	}
+
+	values := make([]float32, n)
+	for i := range values {
+		values[i] = w.mat.Data[i*n+i]
+	}
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return values[order[i]] < values[order[j]] })
+
+	e.values = make([]float32, n)
+	e.vectors = NewDense(n, n, nil)
+	for j, k := range order {
+		e.values[j] = values[k]
+		for i := 0; i < n; i++ {
+			e.vectors.mat.Data[i*n+j] = v.mat.Data[i*n+k]
+		}
+	}
+	return true
+}
+
+// jacobiEigen diagonalizes the full n×n symmetric matrix a in place by
+// applying Jacobi rotations, accumulating the rotations into the columns
+// of v, which must hold the identity on entry. Both matrices are stored
+// row-major with stride n. jacobiEigen returns whether the off-diagonal
+// norm fell below tol times the Frobenius norm of a.
+func jacobiEigen(a, v []float32, n int, tol float32) bool {
+	var norm float32
+	for _, x := range a {
+		norm += x * x
+	}
+	if norm == 0 {
+		return true
+	}
+	thresh := tol * tol * norm
+	for sweep := 0; sweep < maxJacobiSweeps; sweep++ {
+		var off float32
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				off += 2 * a[p*n+q] * a[p*n+q]
+			}
+		}
+		if off <= thresh {
+			return true
+		}
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				apq := a[p*n+q]
+				if apq == 0 {
+					continue
+				}
+				c, s := jacobiRotation(a[p*n+p], a[q*n+q], apq)
+				for k := 0; k < n; k++ {
+					akp, akq := a[k*n+p], a[k*n+q]
+					a[k*n+p] = c*akp - s*akq
+					a[k*n+q] = s*akp + c*akq
+				}
+				for k := 0; k < n; k++ {
+					apk, aqk := a[p*n+k], a[q*n+k]
+					a[p*n+k] = c*apk - s*aqk
+					a[q*n+k] = s*apk + c*aqk
+				}
+				// The rotation is constructed to annihilate
+				// these elements; clear rounding residue.
+				a[p*n+q] = 0
+				a[q*n+p] = 0
+				for k := 0; k < n; k++ {
+					vkp, vkq := v[k*n+p], v[k*n+q]
+					v[k*n+p] = c*vkp - s*vkq
+					v[k*n+q] = s*vkp + c*vkq
+				}
+			}
+		}
+	}
+	return false
+}
+
+// jacobiRotation returns the cosine and sine of the plane rotation that
+// annihilates the off-diagonal element apq of the symmetric 2×2 matrix
+//  [app apq]
+//  [apq aqq]
+func jacobiRotation(app, aqq, apq float32) (c, s float32) {
+	theta := (aqq - app) / (2 * apq)
+	var t float32
+	if math32.Abs(theta) > 1e18 {
+		// theta^2 would overflow.
+		t = 1 / (2 * theta)
+	} else {
+		t = 1 / (math32.Abs(theta) + math32.Sqrt(theta*theta+1))
+		if theta < 0 {
+			t = -t
+		}
+	}
+	c = 1 / math32.Sqrt(t*t+1)
+	return c, t * c
+}
+
+// succFact returns whether the receiver contains a successful factorization.
+func (e *EigenSym) succFact() bool {
+	return len(e.values) != 0
+}
+
+// Values extracts the eigenvalues of the factorized matrix. If dst is
+// non-nil, the values are stored in-place into dst. In this case
+// dst must have length n, otherwise Values will panic. If dst is
+// nil, then a new slice will be allocated of the proper length and filled
+// with the eigenvalues.
+//
+// Values panics if the Eigen decomposition was not successful.
+func (e *EigenSym) Values(dst []float32) []float32 {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	if dst == nil {
+		dst = make([]float32, len(e.values))
+	}
+	if len(dst) != len(e.values) {
+		panic(ErrSliceLengthMismatch)
+	}
+	copy(dst, e.values)
+	return dst
+}
+
+// VectorsTo stores the eigenvectors of the factorized matrix into dst. Each
+// eigenvector is a column corresponding to the respective eigenvalue returned
+// by e.Values. If dst is empty, dst is resized to be n×n; otherwise dst must
+// be n×n.
+//
+// VectorsTo panics if the factorization was not successful.
+func (e *EigenSym) VectorsTo(dst *Dense) {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	n := len(e.values)
+	dst.reuseAs(n, n)
+	dst.Copy(e.vectors)
+}
+
+// DominantEigen estimates the eigenvalue of the square matrix a with the
+// largest magnitude and its corresponding unit eigenvector using power
+// iteration. Each iteration multiplies the current estimate by a and
+// normalizes the result. Iteration stops when successive eigenvector
+// estimates differ by at most tol in the 2-norm, up to sign, or after iters
+// iterations. The returned eigenvalue is the Rayleigh quotient of the final
+// eigenvector estimate.
+//
+// The error in the estimate decreases by a factor of |λ2/λ1| per iteration,
+// where λ1 and λ2 are the eigenvalues with the largest and second largest
+// magnitudes. When these are close, convergence is slow and the returned
+// vector may be a mixture of the corresponding eigenvectors; when they have
+// equal magnitude, power iteration does not converge. A zero vector is
+// returned with a zero eigenvalue if the iteration reaches the null space
+// of a.
+//
+// DominantEigen panics with ErrSquare if a is not square.
+func DominantEigen(a *Dense, iters int, tol float32) (lambda float32, v *VecDense) {
+	r, c := a.Dims()
+	if r != c {
+		panic(ErrSquare)
+	}
+	if r == 0 {
+		panic(ErrZeroLength)
+	}
+
+	// Start from a vector that is unlikely to be
+	// orthogonal to the dominant eigenvector.
+	v = NewVecDense(r, nil)
+	for i := 0; i < r; i++ {
+		v.SetVec(i, 1+float32(i)/float32(r))
+	}
+	v.ScaleVec(1/blas32.Nrm2(r, v.mat), v)
+
+	w := NewVecDense(r, nil)
+	for k := 0; k < iters; k++ {
+		w.MulVec(a, v)
+		lambda = Dot(v, w)
+		norm := blas32.Nrm2(r, w.mat)
+		if norm == 0 {
+			zero(v.mat.Data)
+			return 0, v
+		}
+		w.ScaleVec(1/norm, w)
+
+		// Compare up to sign since the iterates alternate
+		// direction for a negative dominant eigenvalue.
+		var dPos, dNeg float32
+		for i, wi := range w.mat.Data {
+			vi := v.mat.Data[i]
+			dPos += (wi - vi) * (wi - vi)
+			dNeg += (wi + vi) * (wi + vi)
+		}
+		v, w = w, v
+		if math32.Sqrt(math32.Min(dPos, dNeg)) <= tol {
+			break
+		}
+	}
+	var av VecDense
+	av.MulVec(a, v)
+	return Dot(v, &av), v
+}
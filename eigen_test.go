@@ -0,0 +1,158 @@
+// Copyright ©2013 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestEigenSym(t *testing.T) {
+	for i, test := range []struct {
+		mat    *SymDense
+		values []float32
+	}{
+		{
+			mat: NewSymDense(3, []float32{
+				8, 2, 4,
+				2, 6, 10,
+				4, 10, 5,
+			}),
+			values: []float32{-4.707679201365891, 6.294580208480216, 17.413098992885672},
+		},
+		{
+			mat: NewSymDense(2, []float32{
+				2, 1,
+				1, 2,
+			}),
+			values: []float32{1, 3},
+		},
+		{
+			mat: NewSymDense(3, []float32{
+				3, 0, 0,
+				0, -1, 0,
+				0, 0, 2,
+			}),
+			values: []float32{-1, 2, 3},
+		},
+	} {
+		var es EigenSym
+		ok := es.Factorize(test.mat, 0)
+		if !ok {
+			t.Errorf("unexpected factorization failure for test %d", i)
+			continue
+		}
+		values := es.Values(nil)
+		for j, v := range values {
+			if !EqualWithinAbsOrRel(v, test.values[j], 1e-4, 1e-4) {
+				t.Errorf("unexpected eigenvalues for test %d: got: %v want: %v", i, values, test.values)
+				break
+			}
+		}
+		checkEigenSym(t, i, test.mat, &es, 1e-4)
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 5, 10, 20} {
+		a := NewSymDense(n, nil)
+		for i := 0; i < n; i++ {
+			for j := i; j < n; j++ {
+				a.SetSym(i, j, rnd.Float32()*2-1)
+			}
+		}
+		var es EigenSym
+		if !es.Factorize(a, 0) {
+			t.Errorf("unexpected factorization failure for n=%d", n)
+			continue
+		}
+		values := es.Values(nil)
+		for j := 1; j < n; j++ {
+			if values[j] < values[j-1] {
+				t.Errorf("eigenvalues not ascending for n=%d: %v", n, values)
+				break
+			}
+		}
+		checkEigenSym(t, n, a, &es, 1e-4)
+	}
+}
+
+// checkEigenSym checks that the factorization reconstructs a as
+// V * diag(λ) * V^T and that V is orthonormal.
+func checkEigenSym(t *testing.T, i int, a Symmetric, es *EigenSym, tol float32) {
+	t.Helper()
+	n := a.Symmetric()
+	var vecs Dense
+	es.VectorsTo(&vecs)
+
+	var vtv Dense
+	vtv.Mul(vecs.T(), &vecs)
+	if !EqualApprox(&vtv, eye(n), tol) {
+		t.Errorf("eigenvectors not orthonormal for test %d: V^T*V = %v", i, Formatted(&vtv))
+	}
+
+	d := NewDiagonalRect(n, n, es.Values(nil))
+	var vd, got Dense
+	vd.Mul(&vecs, d)
+	got.Mul(&vd, vecs.T())
+	if !EqualApprox(&got, a, tol) {
+		t.Errorf("unexpected reconstruction for test %d: got: %v want: %v", i, Formatted(&got), Formatted(a))
+	}
+}
+
+func TestDominantEigen(t *testing.T) {
+	for i, test := range []struct {
+		a      *Dense
+		lambda float32
+		vec    []float32
+	}{
+		{
+			a:      NewDense(2, 2, []float32{2, 0, 0, 1}),
+			lambda: 2,
+			vec:    []float32{1, 0},
+		},
+		{
+			a:      NewDense(2, 2, []float32{4, 1, 2, 3}),
+			lambda: 5,
+			vec:    []float32{0.70710678, 0.70710678},
+		},
+		{
+			a:      NewDense(3, 3, []float32{-10, 0, 0, 0, 1, 0, 0, 0, 2}),
+			lambda: -10,
+			vec:    []float32{1, 0, 0},
+		},
+		{
+			a: NewDense(3, 3, []float32{
+				8, 2, 4,
+				2, 6, 10,
+				4, 10, 5,
+			}),
+			lambda: 17.413098992885672,
+		},
+	} {
+		lambda, v := DominantEigen(test.a, 1000, 1e-6)
+		if !EqualWithinAbsOrRel(lambda, test.lambda, 1e-4, 1e-4) {
+			t.Errorf("unexpected eigenvalue for test %d: got: %v want: %v", i, lambda, test.lambda)
+		}
+		if n := Norm(v, 2); !EqualWithinAbsOrRel(n, 1, 1e-5, 1e-5) {
+			t.Errorf("unexpected eigenvector norm for test %d: got: %v want: 1", i, n)
+		}
+		var av, lv VecDense
+		av.MulVec(test.a, v)
+		lv.ScaleVec(lambda, v)
+		if !EqualApprox(&av, &lv, 1e-3) {
+			t.Errorf("eigenpair does not satisfy A*v = λ*v for test %d: A*v=%v λ*v=%v", i, av.RawVector().Data, lv.RawVector().Data)
+		}
+		if test.vec != nil {
+			want := NewVecDense(len(test.vec), test.vec)
+			if Dot(want, v) < 0 {
+				want.ScaleVec(-1, want)
+			}
+			if !EqualApprox(v, want, 1e-4) {
+				t.Errorf("unexpected eigenvector for test %d: got: %v want: %v", i, v.RawVector().Data, test.vec)
+			}
+		}
+	}
+}
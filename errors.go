@@ -28,7 +28,9 @@ func (c Condition) Error() string {
 
 // ConditionTolerance is the tolerance limit of the condition number. If the
 // condition number is above this value, the matrix is considered singular.
-const ConditionTolerance = 1e16
+// The limit is close to the reciprocal of the float32 machine epsilon, beyond
+// which a computed solution may have no correct digits.
+const ConditionTolerance = 1e7
 
 const stackTraceBufferSize = 1 << 20
 
@@ -38,10 +40,7 @@ const stackTraceBufferSize = 1 << 20
 func Maybe(fn func()) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			if e, ok := r.(Error); ok {
-				if e.string == "" {
-					panic("mat: invalid error")
-				}
+			if e, ok := recoverable(r); ok {
 				buf := make([]byte, stackTraceBufferSize)
 				n := runtime.Stack(buf, false)
 				err = ErrorStack{Err: e, StackTrace: string(buf[:n])}
@@ -60,10 +59,7 @@ func Maybe(fn func()) (err error) {
 func MaybeFloat(fn func() float32) (f float32, err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			if e, ok := r.(Error); ok {
-				if e.string == "" {
-					panic("mat: invalid error")
-				}
+			if e, ok := recoverable(r); ok {
 				buf := make([]byte, stackTraceBufferSize)
 				n := runtime.Stack(buf, false)
 				err = ErrorStack{Err: e, StackTrace: string(buf[:n])}
@@ -81,10 +77,7 @@ func MaybeFloat(fn func() float32) (f float32, err error) {
 func MaybeComplex(fn func() complex128) (f complex128, err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			if e, ok := r.(Error); ok {
-				if e.string == "" {
-					panic("mat: invalid error")
-				}
+			if e, ok := recoverable(r); ok {
 				buf := make([]byte, stackTraceBufferSize)
 				n := runtime.Stack(buf, false)
 				err = ErrorStack{Err: e, StackTrace: string(buf[:n])}
@@ -96,6 +89,43 @@ func MaybeComplex(fn func() complex128) (f complex128, err error) {
 	return fn(), nil
 }
 
+// Try calls fn and returns any matrix handling error that fn panics with,
+// such as ErrShape or a ShapeError, as a plain error without the stack
+// trace recorded by Maybe. Any other panic is propagated. Try is intended
+// as a boundary around operations on untrusted input, for example
+//  err := mat.Try(func() { v.MulVec(a, b) })
+//  if errors.Is(err, mat.ErrShape) {
+//  	...
+//  }
+func Try(fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := recoverable(r); ok {
+				err = e
+				return
+			}
+			panic(r)
+		}
+	}()
+	fn()
+	return nil
+}
+
+// recoverable returns the panic value r as an error if it is a matrix
+// handling error that may be recovered by the Maybe wrappers.
+func recoverable(r interface{}) (error, bool) {
+	switch e := r.(type) {
+	case Error:
+		if e.string == "" {
+			panic("mat: invalid error")
+		}
+		return e, true
+	case ShapeError:
+		return e, true
+	}
+	return nil, false
+}
+
 // Error represents matrix handling errors. These errors can be recovered by Maybe wrappers.
 type Error struct{ string }
 
@@ -121,8 +151,29 @@ var (
 	ErrSliceLengthMismatch = Error{"matrix: input slice length mismatch"}
 	ErrNotPSD              = Error{"matrix: input not positive symmetric definite"}
 	ErrFailedEigen         = Error{"matrix: eigendecomposition not successful"}
+	ErrMetric              = Error{"matrix: unknown distance metric"}
+	ErrNotConverged        = Error{"matrix: iteration did not converge"}
+	ErrZeroDiagonal        = Error{"matrix: zero diagonal element"}
 )
 
+// ShapeError is a dimension mismatch error that records the dimensions of
+// the offending operand, Got, and the dimensions the operation required of
+// it, Want, each as {rows, cols}. Vector and slice lengths are reported
+// as n×1.
+//
+// A ShapeError matches ErrShape under errors.Is, so existing checks for
+// ErrShape continue to hold. ShapeErrors can be recovered by Maybe wrappers.
+type ShapeError struct {
+	Got, Want [2]int
+}
+
+func (err ShapeError) Error() string {
+	return fmt.Sprintf("%s: got %d×%d, want %d×%d", ErrShape.string, err.Got[0], err.Got[1], err.Want[0], err.Want[1])
+}
+
+// Unwrap returns ErrShape.
+func (err ShapeError) Unwrap() error { return ErrShape }
+
 // ErrorStack represents matrix handling errors that have been recovered by Maybe wrappers.
 type ErrorStack struct {
 	Err error
@@ -134,3 +185,6 @@ type ErrorStack struct {
 }
 
 func (err ErrorStack) Error() string { return err.Err.Error() }
+
+// Unwrap returns the recovered error.
+func (err ErrorStack) Unwrap() error { return err.Err }
@@ -4,7 +4,11 @@
 
 package mat32
 
-import "testing"
+import (
+	"errors"
+	"strings"
+	"testing"
+)
 
 func leaksPanic(fn func()) (panicked bool) {
 	defer func() {
@@ -54,3 +58,136 @@ func TestMaybe(t *testing.T) {
 		}
 	}
 }
+
+func TestTry(t *testing.T) {
+	if err := Try(func() {}); err != nil {
+		t.Errorf("unexpected error for non-panicking function: %v", err)
+	}
+
+	err := Try(func() {
+		var v VecDense
+		v.MulVec(NewDense(2, 3, nil), NewVecDense(2, nil))
+	})
+	if !errors.Is(err, ErrShape) {
+		t.Errorf("unexpected error for mismatched MulVec: got: %v want: %v", err, ErrShape)
+	}
+	if _, ok := err.(ErrorStack); ok {
+		t.Errorf("unexpected ErrorStack returned by Try")
+	}
+
+	err = Try(func() { NewDense(2, 2, nil).At(2, 0) })
+	if err != ErrRowAccess {
+		t.Errorf("unexpected error for out of range access: got: %v want: %v", err, ErrRowAccess)
+	}
+
+	panicked := func() (panicked bool) {
+		defer func() { panicked = recover() != nil }()
+		Try(func() {
+			var m *Dense
+			m.At(0, 0)
+		})
+		return false
+	}()
+	if !panicked {
+		t.Errorf("expected nil dereference to propagate through Try")
+	}
+
+	panicked, message := panics(func() { Try(func() { panic("not a matrix error") }) })
+	if !panicked || message != "not a matrix error" {
+		t.Errorf("unexpected propagated panic: got: %q", message)
+	}
+}
+
+func TestShapeError(t *testing.T) {
+	for i, test := range []struct {
+		fn   func()
+		dims string
+	}{
+		{
+			fn:   func() { var m Dense; m.Add(NewDense(4, 4, nil), NewDense(3, 4, nil)) },
+			dims: "got 3×4, want 4×4",
+		},
+		{
+			fn:   func() { var m Dense; m.Mul(NewDense(2, 3, nil), NewDense(4, 5, nil)) },
+			dims: "got 4×5, want 3×5",
+		},
+		{
+			fn:   func() { m := NewDense(2, 2, nil); m.Sub(NewDense(3, 3, nil), NewDense(3, 3, nil)) },
+			dims: "got 2×2, want 3×3",
+		},
+		{
+			fn:   func() { var v VecDense; v.AddVec(NewVecDense(3, nil), NewVecDense(2, nil)) },
+			dims: "got 2×1, want 3×1",
+		},
+		{
+			fn:   func() { var v VecDense; v.MulVec(NewDense(2, 3, nil), NewVecDense(4, nil)) },
+			dims: "got 4×1, want 3×1",
+		},
+		{
+			fn:   func() { Dot(NewVecDense(3, nil), NewVecDense(2, nil)) },
+			dims: "got 2×1, want 3×1",
+		},
+		{
+			fn:   func() { var v VecDense; v.AddScaledVec(NewVecDense(3, nil), 2, NewVecDense(4, nil)) },
+			dims: "got 4×1, want 3×1",
+		},
+		{
+			fn:   func() { var m Dense; m.RankOne(NewDense(2, 3, nil), 1, NewVecDense(2, nil), NewVecDense(2, nil)) },
+			dims: "got 2×1, want 3×1",
+		},
+		{
+			fn:   func() { var m Dense; m.AddBroadcast(NewDense(2, 3, nil), NewDense(1, 2, nil)) },
+			dims: "got 1×2, want 1×3",
+		},
+		{
+			fn:   func() { var m Dense; m.Solve(eye(3), NewDense(2, 2, nil)) },
+			dims: "got 2×2, want 3×2",
+		},
+		{
+			fn:   func() { var m Dense; m.ScaleCols(NewDense(2, 3, nil), NewVecDense(2, nil)) },
+			dims: "got 2×1, want 3×1",
+		},
+		{
+			fn:   func() { NewVecDense(3, nil).DotMatrix(make([]float32, 2), NewDense(2, 4, nil)) },
+			dims: "got 3×1, want 4×1",
+		},
+		{
+			fn:   func() { NewDenseColMajor(2, 3, make([]float32, 5)) },
+			dims: "got 5×1, want 6×1",
+		},
+		{
+			fn:   func() { Axpy(1, make([]float32, 2), make([]float32, 3)) },
+			dims: "got 3×1, want 2×1",
+		},
+	} {
+		panicked, message := panics(test.fn)
+		if !panicked {
+			t.Errorf("expected panic for test %d", i)
+			continue
+		}
+		if !strings.HasPrefix(message, ErrShape.Error()) || !strings.Contains(message, test.dims) {
+			t.Errorf("unexpected panic message for test %d: got: %q want dimensions: %q", i, message, test.dims)
+		}
+
+		err := Maybe(test.fn)
+		if err == nil {
+			t.Errorf("expected error from Maybe for test %d", i)
+			continue
+		}
+		if !errors.Is(err, ErrShape) {
+			t.Errorf("expected error to match ErrShape for test %d: got: %v", i, err)
+		}
+		var shapeErr ShapeError
+		if !errors.As(err, &shapeErr) {
+			t.Errorf("expected ShapeError for test %d: got: %T", i, err)
+		}
+	}
+
+	err := ShapeError{Got: [2]int{3, 4}, Want: [2]int{4, 4}}
+	if !errors.Is(err, ErrShape) {
+		t.Errorf("expected ShapeError to match ErrShape")
+	}
+	if errors.Is(err, ErrSquare) {
+		t.Errorf("unexpected match of ShapeError with ErrSquare")
+	}
+}
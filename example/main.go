@@ -4,24 +4,15 @@ import (
 	"github.com/arjunsk/mat32"
 	"gonum.org/v1/gonum/mat"
 	"math"
-	"reflect"
-	"unsafe"
 )
 
 type RealNumbers interface {
 	float64 | float32
 }
 
-func L2Distance[T RealNumbers](v1, v2 []T) (float64, error) {
-	var result0 []float32
-	header0 := *(*reflect.SliceHeader)(unsafe.Pointer(&v1))
-	result0 = *(*[]float32)(unsafe.Pointer(&header0))
-	vec0 := mat32.NewVecDense(len(v1), result0)
-
-	var result1 []float32
-	header1 := *(*reflect.SliceHeader)(unsafe.Pointer(&v2))
-	result1 = *(*[]float32)(unsafe.Pointer(&header1))
-	vec1 := mat32.NewVecDense(len(v2), result1)
+func L2Distance(v1, v2 []float32) (float64, error) {
+	vec0 := mat32.UnsafeVecDense(v1)
+	vec1 := mat32.UnsafeVecDense(v2)
 
 	diff := mat32.NewVecDense(vec0.Len(), nil)
 	diff.SubVec(vec0, vec1)
@@ -62,7 +53,7 @@ func L2Distance2[T RealNumbers](v1, v2 []float64) (float64, error) {
 func main() {
 
 	vector2 := []float32{1, 2, 3}
-	res2, _ := L2Distance[float32](vector2, vector2)
+	res2, _ := L2Distance(vector2, vector2)
 	println(res2)
 
 	vector3 := []float64{1, 2, 3}
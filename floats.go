@@ -3,6 +3,34 @@ package mat32
 
 import "github.com/chewxy/math32"
 
+// epsilon32 is the float32 machine epsilon, the difference
+// between 1 and the next representable value.
+const epsilon32 = 1.0 / (1 << 23)
+
+// DefaultTol is a default tolerance for comparing float32 results that
+// have been through a modest amount of arithmetic. It corresponds to about
+// 84 units in the last place relative to 1.
+const DefaultTol float32 = 1e-5
+
+// WithinTol returns whether a and b are equal to within tol, either in
+// absolute terms,
+//  |a-b| <= tol
+// which dominates for values near zero, or relative to the larger
+// magnitude,
+//  |a-b| <= tol * max(|a|, |b|)
+// which dominates for large values. Infinities are only within tolerance
+// of themselves and NaN is not within tolerance of any value.
+func WithinTol(a, b, tol float32) bool {
+	if a == b {
+		return true
+	}
+	if math32.IsInf(a, 0) || math32.IsInf(b, 0) {
+		return false
+	}
+	d := math32.Abs(a - b)
+	return d <= tol || d <= tol*math32.Max(math32.Abs(a), math32.Abs(b))
+}
+
 // EqualWithinRel returns true if the difference between a and b
 // is not greater than tol times the greater value.
 func EqualWithinRel(a, b, tol float32) bool {
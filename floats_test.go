@@ -3,6 +3,7 @@ package mat32
 import (
 	"fmt"
 	"math/rand"
+	"testing"
 
 	"github.com/chewxy/math32"
 )
@@ -65,3 +66,40 @@ func floatsEqualApprox(s1, s2 []float32, tol float32) bool {
 func randNormFloat32() float32 {
 	return float32(rand.NormFloat64())
 }
+
+func TestWithinTol(t *testing.T) {
+	inf := math32.Inf(1)
+	for i, test := range []struct {
+		a, b, tol float32
+		want      bool
+	}{
+		// Near zero the absolute tolerance dominates; no
+		// relative tolerance would accept these.
+		{a: 0, b: 1e-6, tol: DefaultTol, want: true},
+		{a: 1e-7, b: -1e-7, tol: DefaultTol, want: true},
+		{a: 0, b: 2e-5, tol: DefaultTol, want: false},
+
+		// For large values the relative tolerance dominates;
+		// the absolute differences here far exceed tol.
+		{a: 1e6, b: 1e6 + 8, tol: DefaultTol, want: true},
+		{a: -3e10, b: -3.00001e10, tol: DefaultTol, want: true},
+		{a: 1e6, b: 1e6 + 16, tol: DefaultTol, want: false},
+		{a: 1e30, b: 1.001e30, tol: DefaultTol, want: false},
+
+		{a: 1, b: 1 + epsilon32, tol: 0, want: false},
+		{a: 1, b: 1, tol: 0, want: true},
+		{a: inf, b: inf, tol: DefaultTol, want: true},
+		{a: inf, b: -inf, tol: DefaultTol, want: false},
+		{a: inf, b: math32.MaxFloat32, tol: DefaultTol, want: false},
+		{a: math32.NaN(), b: math32.NaN(), tol: DefaultTol, want: false},
+		{a: math32.NaN(), b: 0, tol: inf, want: false},
+	} {
+		if got := WithinTol(test.a, test.b, test.tol); got != test.want {
+			t.Errorf("unexpected result for test %d WithinTol(%v, %v, %v): got: %t want: %t",
+				i, test.a, test.b, test.tol, got, test.want)
+		}
+		if got := WithinTol(test.b, test.a, test.tol); got != test.want {
+			t.Errorf("unexpected result for test %d with swapped arguments: got: %t want: %t", i, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import "github.com/chewxy/math32"
+
+// GaussSeidel solves the system of equations
+//  A * x = b
+// using Gauss-Seidel iteration, placing x into dst. Each sweep updates the
+// elements of x in place, in order, using the most recent values of the
+// other elements,
+//  x[i] = (b[i] - Σ_{j≠i} A[i,j]*x[j]) / A[i,i]
+// The iteration starts from x = 0 and stops when the relative residual
+// ‖b - A*x‖₂ / ‖b‖₂ is at most tol, or after maxIter sweeps.
+//
+// Gauss-Seidel iteration converges for strictly diagonally dominant and
+// for symmetric positive definite A, but may diverge otherwise. GaussSeidel
+// returns the number of sweeps performed, and ErrNotConverged if the
+// tolerance was not reached within maxIter sweeps, in which case dst holds
+// the last iterate. It returns ErrZeroDiagonal without modifying dst if a
+// diagonal element of A is zero.
+//
+// GaussSeidel panics with ErrSquare if A is not square and with ErrShape if
+// the length of b does not match the dimension of A. If dst is empty it is
+// resized to the length of b, otherwise it must have that length.
+func GaussSeidel(dst *VecDense, a Matrix, b Vector, tol float32, maxIter int) (iters int, err error) {
+	n, c := a.Dims()
+	if n != c {
+		panic(ErrSquare)
+	}
+	if b.Len() != n {
+		panic(ShapeError{Got: [2]int{b.Len(), 1}, Want: [2]int{n, 1}})
+	}
+	for i := 0; i < n; i++ {
+		if a.At(i, i) == 0 {
+			return 0, ErrZeroDiagonal
+		}
+	}
+
+	r := getWorkspaceVec(n, false)
+	defer putWorkspaceVec(r)
+	r.CopyVec(b)
+	bnorm := math32.Sqrt(Dot(r, r))
+	dst.reuseAs(n)
+	for i := 0; i < n; i++ {
+		dst.setVec(i, 0)
+	}
+	if bnorm == 0 {
+		return 0, nil
+	}
+
+	x := dst.mat.Data
+	inc := dst.mat.Inc
+	bv := r.mat.Data
+	rowDot := func(i int) float32 {
+		var s float32
+		for j := 0; j < n; j++ {
+			s += a.At(i, j) * x[j*inc]
+		}
+		return s
+	}
+	if rm, ok := a.(RawMatrixer); ok {
+		amat := rm.RawMatrix()
+		rowDot = func(i int) float32 {
+			var s float32
+			for j, v := range amat.Data[i*amat.Stride : i*amat.Stride+n] {
+				s += v * x[j*inc]
+			}
+			return s
+		}
+	}
+	diag := getFloats(n, false)
+	defer putFloats(diag)
+	for i := range diag {
+		diag[i] = a.At(i, i)
+	}
+
+	for iters < maxIter {
+		iters++
+		for i, d := range diag {
+			x[i*inc] += (bv[i] - rowDot(i)) / d
+		}
+
+		var ss float32
+		for i, v := range bv {
+			res := v - rowDot(i)
+			ss += res * res
+		}
+		if math32.Sqrt(ss)/bnorm <= tol {
+			return iters, nil
+		}
+	}
+	return iters, ErrNotConverged
+}
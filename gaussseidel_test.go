@@ -0,0 +1,73 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import "testing"
+
+func TestGaussSeidel(t *testing.T) {
+	for i, test := range []struct {
+		a    Matrix
+		b    []float32
+		want []float32
+	}{
+		{
+			a:    NewDense(1, 1, []float32{4}),
+			b:    []float32{2},
+			want: []float32{0.5},
+		},
+		{
+			a: NewDense(3, 3, []float32{
+				4, -1, 0,
+				-1, 4, -1,
+				0, -1, 4,
+			}),
+			b:    []float32{3, 2, 3},
+			want: []float32{1, 1, 1},
+		},
+		{
+			a: NewDense(3, 3, []float32{
+				10, 2, -1,
+				1, 8, 3,
+				-2, 1, 5,
+			}),
+			b:    []float32{11, 26, 15},
+			want: []float32{1, 2, 3},
+		},
+		{
+			// Non-Dense input uses element access.
+			a: NewSymDense(3, []float32{
+				4, -1, 0,
+				-1, 4, -1,
+				0, -1, 4,
+			}),
+			b:    []float32{3, 2, 3},
+			want: []float32{1, 1, 1},
+		},
+	} {
+		var got VecDense
+		iters, err := GaussSeidel(&got, test.a, NewVecDense(len(test.b), test.b), 1e-6, 100)
+		if err != nil {
+			t.Errorf("unexpected error for test %d after %d iterations: %v", i, iters, err)
+			continue
+		}
+		want := NewVecDense(len(test.want), test.want)
+		if !EqualApprox(&got, want, 1e-5) {
+			t.Errorf("unexpected result for test %d: got: %v want: %v", i, got.RawVector().Data, test.want)
+		}
+	}
+
+	// Gauss-Seidel diverges for this matrix, which is not diagonally dominant.
+	var got VecDense
+	iters, err := GaussSeidel(&got, NewDense(2, 2, []float32{1, 2, 3, 1}), NewVecDense(2, []float32{3, 4}), 1e-6, 20)
+	if err != ErrNotConverged || iters != 20 {
+		t.Errorf("unexpected result for non-dominant matrix: got: %d, %v want: 20, %v", iters, err, ErrNotConverged)
+	}
+
+	got.Reset()
+	_, err = GaussSeidel(&got, NewDense(2, 2, []float32{0, 1, 1, 1}), NewVecDense(2, []float32{1, 1}), 1e-6, 20)
+	if err != ErrZeroDiagonal {
+		t.Errorf("unexpected error for zero diagonal: got: %v want: %v", err, ErrZeroDiagonal)
+	}
+}
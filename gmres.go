@@ -0,0 +1,155 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"github.com/arjunsk/mat32/internal/asm/f32"
+	"github.com/chewxy/math32"
+)
+
+// GMRES solves the system of equations
+//  A * x = b
+// for a general square, non-singular A using the restarted generalized
+// minimal residual method, placing x into dst. Like CG, A is only accessed
+// through matrix-vector products. The iteration starts from x = 0 and stops
+// when the relative residual ‖b - A*x‖₂ / ‖b‖₂ is at most tol, or after
+// maxIter matrix-vector products with A. If maxIter is not positive, the
+// dimension of A is used.
+//
+// Each cycle of GMRES builds an orthonormal Krylov basis of up to restart
+// vectors by Arnoldi iteration and picks the update minimizing the residual
+// over that basis, after which the basis is discarded and a new cycle is
+// started from the current iterate. A larger restart converges in fewer
+// iterations, and without restarting in at most n, but storage grows as
+// restart×n and the cost of orthogonalization grows quadratically with
+// restart. A small restart is cheap per iteration but may stagnate on
+// difficult systems. If restart is not positive or exceeds the dimension of
+// A, no restarting is performed.
+//
+// GMRES returns the number of iterations performed and the relative
+// residual of the returned solution. It returns ErrNotConverged if the
+// tolerance was not reached within maxIter iterations, and ErrSingular if
+// the Arnoldi process breaks down because A is singular. In both cases dst
+// holds the last iterate.
+//
+// GMRES panics with ErrSquare if A is not square and with ErrShape if the
+// length of b does not match the dimension of A. If dst is empty it is
+// resized to the length of b, otherwise it must have that length.
+func GMRES(dst *VecDense, a Matrix, b Vector, restart, maxIter int, tol float32) (iters int, resid float32, err error) {
+	n, c := a.Dims()
+	if n != c {
+		panic(ErrSquare)
+	}
+	if b.Len() != n {
+		panic(ShapeError{Got: [2]int{b.Len(), 1}, Want: [2]int{n, 1}})
+	}
+	if maxIter <= 0 {
+		maxIter = n
+	}
+	m := restart
+	if m <= 0 || m > n {
+		m = n
+	}
+
+	r := getWorkspaceVec(n, false)
+	defer putWorkspaceVec(r)
+	r.CopyVec(b)
+	dst.reuseAs(n)
+	for i := 0; i < n; i++ {
+		dst.setVec(i, 0)
+	}
+
+	bnorm := math32.Sqrt(Dot(r, r))
+	if bnorm == 0 {
+		return 0, 0, nil
+	}
+
+	// The rows of v hold the Krylov basis and h holds the
+	// (m+1)×m upper Hessenberg matrix of the Arnoldi process,
+	// reduced to upper triangular form by Givens rotations
+	// held in cs and sn.
+	v := getWorkspace(m+1, n, false)
+	defer putWorkspace(v)
+	h := getFloats((m+1)*m, false)
+	defer putFloats(h)
+	g := getFloats(m+1, false)
+	defer putFloats(g)
+	cs := getFloats(m, false)
+	defer putFloats(cs)
+	sn := getFloats(m, false)
+	defer putFloats(sn)
+
+	for {
+		if iters > 0 {
+			r.MulVec(a, dst)
+			r.SubVec(b, r)
+		}
+		beta := math32.Sqrt(Dot(r, r))
+		resid = beta / bnorm
+		if resid <= tol {
+			return iters, resid, nil
+		}
+		if iters >= maxIter {
+			return iters, resid, ErrNotConverged
+		}
+
+		f32.ScalUnitaryTo(v.mat.Data[:n], 1/beta, r.mat.Data[:n])
+		zero(g)
+		g[0] = beta
+		k := 0
+		for j := 0; j < m && iters < maxIter; j++ {
+			iters++
+			k = j + 1
+
+			// Arnoldi step with modified Gram-Schmidt.
+			w := NewVecDense(n, v.mat.Data[(j+1)*n:(j+2)*n])
+			w.MulVec(a, v.RowView(j))
+			wd := w.mat.Data
+			for i := 0; i <= j; i++ {
+				vi := v.mat.Data[i*n : (i+1)*n]
+				hij := f32.DotUnitary(wd, vi)
+				h[i*m+j] = hij
+				f32.AxpyUnitary(-hij, vi, wd)
+			}
+			hnext := math32.Sqrt(f32.DotUnitary(wd, wd))
+			if hnext != 0 {
+				f32.ScalUnitary(1/hnext, wd)
+			}
+
+			// Apply the previous rotations to the new column
+			// and eliminate the subdiagonal element.
+			for i := 0; i < j; i++ {
+				t := cs[i]*h[i*m+j] + sn[i]*h[(i+1)*m+j]
+				h[(i+1)*m+j] = -sn[i]*h[i*m+j] + cs[i]*h[(i+1)*m+j]
+				h[i*m+j] = t
+			}
+			d := math32.Hypot(h[j*m+j], hnext)
+			if d == 0 {
+				return iters, resid, ErrSingular
+			}
+			cs[j] = h[j*m+j] / d
+			sn[j] = hnext / d
+			h[j*m+j] = d
+			g[j+1] = -sn[j] * g[j]
+			g[j] *= cs[j]
+			if math32.Abs(g[j+1])/bnorm <= tol || hnext == 0 {
+				break
+			}
+		}
+
+		// Solve the triangular system for the update coefficients
+		// and apply the update to the iterate.
+		for i := k - 1; i >= 0; i-- {
+			s := g[i]
+			for l := i + 1; l < k; l++ {
+				s -= h[i*m+l] * g[l]
+			}
+			g[i] = s / h[i*m+i]
+		}
+		for i := 0; i < k; i++ {
+			f32.AxpyInc(g[i], v.mat.Data[i*n:(i+1)*n], dst.mat.Data, uintptr(n), 1, uintptr(dst.mat.Inc), 0, 0)
+		}
+	}
+}
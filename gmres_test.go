@@ -0,0 +1,108 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestGMRES(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i, test := range []struct {
+		a       [][]float32
+		b       []float32
+		restart int
+	}{
+		{
+			a: [][]float32{{2}},
+			b: []float32{3},
+		},
+		{
+			a: [][]float32{
+				{4, 1, 0},
+				{-2, 3, 1},
+				{0, 5, 2},
+			},
+			b: []float32{1, 2, 3},
+		},
+		{
+			a: [][]float32{
+				{10, -1, 2, 0},
+				{3, 8, 0, 1},
+				{-2, 0, 6, -1},
+				{0, 4, 1, 5},
+			},
+			b: []float32{-1, 0, 4, 2},
+		},
+		{
+			a: [][]float32{
+				{10, -1, 2, 0},
+				{3, 8, 0, 1},
+				{-2, 0, 6, -1},
+				{0, 4, 1, 5},
+			},
+			b:       []float32{-1, 0, 4, 2},
+			restart: 2,
+		},
+	} {
+		a := NewDense(flatten(test.a))
+		n := len(test.b)
+		b := NewVecDense(n, test.b)
+
+		var lu LU
+		lu.Factorize(a)
+		want := NewVecDense(n, nil)
+		want.CopyVec(b)
+		lu.solveInPlace(want.mat.Data, false)
+
+		var got VecDense
+		_, resid, err := GMRES(&got, a, b, test.restart, 50, 1e-6)
+		if err != nil {
+			t.Errorf("unexpected error for test %d: %v", i, err)
+			continue
+		}
+		if resid > 1e-6 {
+			t.Errorf("unexpected residual for test %d: got: %v", i, resid)
+		}
+		if !EqualApprox(&got, want, 1e-5) {
+			t.Errorf("unexpected result for test %d: got: %v want: %v", i, got.RawVector().Data, want.RawVector().Data)
+		}
+	}
+
+	// A random diagonally dominant non-symmetric system.
+	const n = 40
+	a := randNormDense(n, n, rnd)
+	for i := 0; i < n; i++ {
+		a.Set(i, i, a.At(i, i)+2*float32(n))
+	}
+	b := NewVecDense(n, randSlice(n, rnd))
+	for _, restart := range []int{0, 5, 20} {
+		var got, ax VecDense
+		_, _, err := GMRES(&got, a, b, restart, 10*n, 1e-6)
+		if err != nil {
+			t.Errorf("unexpected error for random system with restart %d: %v", restart, err)
+		}
+		ax.MulVec(a, &got)
+		if !EqualApprox(&ax, b, 1e-4) {
+			t.Errorf("unexpected residual for random system with restart %d", restart)
+		}
+	}
+
+	// Too few iterations.
+	var got VecDense
+	iters, _, err := GMRES(&got, a, b, 0, 2, 1e-6)
+	if err != ErrNotConverged || iters != 2 {
+		t.Errorf("unexpected result for truncated iteration: got: %d, %v want: 2, %v", iters, err, ErrNotConverged)
+	}
+
+	// Zero right-hand side.
+	got.Reset()
+	iters, resid, err := GMRES(&got, a, NewVecDense(n, nil), 0, 0, 1e-6)
+	if iters != 0 || resid != 0 || err != nil {
+		t.Errorf("unexpected result for zero b: iters=%d resid=%v err=%v", iters, resid, err)
+	}
+}
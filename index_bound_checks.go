@@ -73,6 +73,42 @@ func (v *VecDense) setVec(i int, val float32) {
 	v.mat.Data[i*v.mat.Inc] = val
 }
 
+// At returns the element at row i, column j.
+func (s *SymDense) At(i, j int) float32 {
+	return s.at(i, j)
+}
+
+func (s *SymDense) at(i, j int) float32 {
+	if uint(i) >= uint(s.mat.N) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(s.mat.N) {
+		panic(ErrColAccess)
+	}
+	if i > j {
+		i, j = j, i
+	}
+	return s.mat.Data[i*s.mat.Stride+j]
+}
+
+// SetSym sets the elements at (i,j) and (j,i) to the value v.
+func (s *SymDense) SetSym(i, j int, v float32) {
+	s.set(i, j, v)
+}
+
+func (s *SymDense) set(i, j int, v float32) {
+	if uint(i) >= uint(s.mat.N) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(s.mat.N) {
+		panic(ErrColAccess)
+	}
+	if i > j {
+		i, j = j, i
+	}
+	s.mat.Data[i*s.mat.Stride+j] = v
+}
+
 // At returns the element at row i, column j.
 func (t *TriDense) At(i, j int) float32 {
 	return t.at(i, j)
@@ -20,10 +20,10 @@ import (
 func Inner(x Vector, a Matrix, y Vector) float32 {
 	m, n := a.Dims()
 	if x.Len() != m {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{x.Len(), 1}, Want: [2]int{m, 1}})
 	}
 	if y.Len() != n {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{y.Len(), 1}, Want: [2]int{n, 1}})
 	}
 	if m == 0 || n == 0 {
 		return 0
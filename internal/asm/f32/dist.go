@@ -0,0 +1,138 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package f32
+
+// L1DistUnitary is
+//  for i, v := range x {
+//  	d := v - y[i]
+//  	if d < 0 {
+//  		d = -d
+//  	}
+//  	sum += d
+//  }
+//  return sum
+func L1DistUnitary(x, y []float32) (sum float32) {
+	y = y[:len(x)]
+	for i, v := range x {
+		d := v - y[i]
+		if d < 0 {
+			d = -d
+		}
+		sum += d
+	}
+	return sum
+}
+
+// L1DistInc is
+//  for i := 0; i < int(n); i++ {
+//  	d := x[ix] - y[iy]
+//  	if d < 0 {
+//  		d = -d
+//  	}
+//  	sum += d
+//  	ix += incX
+//  	iy += incY
+//  }
+//  return sum
+func L1DistInc(x, y []float32, n, incX, incY, ix, iy uintptr) (sum float32) {
+	for i := 0; i < int(n); i++ {
+		d := x[ix] - y[iy]
+		if d < 0 {
+			d = -d
+		}
+		sum += d
+		ix += incX
+		iy += incY
+	}
+	return sum
+}
+
+// LinfDistUnitary is
+//  for i, v := range x {
+//  	d := v - y[i]
+//  	if d < 0 {
+//  		d = -d
+//  	}
+//  	if d > max || d != d {
+//  		max = d
+//  	}
+//  }
+//  return max
+func LinfDistUnitary(x, y []float32) (max float32) {
+	y = y[:len(x)]
+	for i, v := range x {
+		d := v - y[i]
+		if d < 0 {
+			d = -d
+		}
+		if d > max || d != d {
+			// A NaN difference is retained, since no
+			// later comparison can replace it.
+			max = d
+		}
+	}
+	return max
+}
+
+// LinfDistInc is
+//  for i := 0; i < int(n); i++ {
+//  	d := x[ix] - y[iy]
+//  	if d < 0 {
+//  		d = -d
+//  	}
+//  	if d > max || d != d {
+//  		max = d
+//  	}
+//  	ix += incX
+//  	iy += incY
+//  }
+//  return max
+func LinfDistInc(x, y []float32, n, incX, incY, ix, iy uintptr) (max float32) {
+	for i := 0; i < int(n); i++ {
+		d := x[ix] - y[iy]
+		if d < 0 {
+			d = -d
+		}
+		if d > max || d != d {
+			max = d
+		}
+		ix += incX
+		iy += incY
+	}
+	return max
+}
+
+// L2DistSqUnitary is
+//  for i, v := range x {
+//  	d := v - y[i]
+//  	sum += d * d
+//  }
+//  return sum
+func L2DistSqUnitary(x, y []float32) (sum float32) {
+	y = y[:len(x)]
+	for i, v := range x {
+		d := v - y[i]
+		sum += d * d
+	}
+	return sum
+}
+
+// L2DistSqInc is
+//  for i := 0; i < int(n); i++ {
+//  	d := x[ix] - y[iy]
+//  	sum += d * d
+//  	ix += incX
+//  	iy += incY
+//  }
+//  return sum
+func L2DistSqInc(x, y []float32, n, incX, incY, ix, iy uintptr) (sum float32) {
+	for i := 0; i < int(n); i++ {
+		d := x[ix] - y[iy]
+		sum += d * d
+		ix += incX
+		iy += incY
+	}
+	return sum
+}
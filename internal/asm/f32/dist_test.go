@@ -0,0 +1,134 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package f32
+
+import "testing"
+
+func TestL1Dist(t *testing.T) {
+	for j, v := range []struct {
+		x, y []float32
+		ex   float32
+	}{
+		{x: nil, y: nil, ex: 0},
+		{x: []float32{1}, y: []float32{1}, ex: 0},
+		{x: []float32{1}, y: []float32{-2}, ex: 3},
+		{x: []float32{1, 2, 3}, y: []float32{3, 2, 1}, ex: 4},
+		{x: []float32{-1, -2, -3, 4, 5}, y: []float32{1, 2, 3, -4, -5}, ex: 30},
+	} {
+		if got := L1DistUnitary(v.x, v.y); got != v.ex {
+			t.Errorf("test %d: L1DistUnitary got: %v want: %v", j, got, v.ex)
+		}
+
+		for _, inc := range []int{1, 2, 3} {
+			n := len(v.x)
+			x := make([]float32, n*inc+1)
+			y := make([]float32, n*inc+1)
+			for i := 0; i < n; i++ {
+				x[i*inc] = v.x[i]
+				y[(n-1-i)*inc+1] = v.y[i]
+			}
+			// Walk y backwards from its last element.
+			var iy uintptr
+			if n > 0 {
+				iy = uintptr((n-1)*inc + 1)
+			}
+			got := L1DistInc(x, y, uintptr(n), uintptr(inc), uintptr(-inc), 0, iy)
+			if got != v.ex {
+				t.Errorf("test %d inc %d: L1DistInc got: %v want: %v", j, inc, got, v.ex)
+			}
+		}
+	}
+}
+
+func TestLinfDist(t *testing.T) {
+	for j, v := range []struct {
+		x, y []float32
+		ex   float32
+	}{
+		{x: nil, y: nil, ex: 0},
+		{x: []float32{1, 2}, y: []float32{1, 2}, ex: 0},
+		{x: []float32{1}, y: []float32{-2}, ex: 3},
+		{x: []float32{1, 2, 3}, y: []float32{1, 7, 2}, ex: 5},
+		{x: []float32{-1, -2, -3, 4, 5}, y: []float32{1, 2, 3, -4, -4}, ex: 9},
+		{x: []float32{nan, 1, 2}, y: []float32{0, 5, 9}, ex: nan},
+		{x: []float32{1, 2, 3}, y: []float32{5, nan, 0}, ex: nan},
+		{x: []float32{1, 2, inf}, y: []float32{1, 2, inf}, ex: nan},
+	} {
+		if got := LinfDistUnitary(v.x, v.y); !same(got, v.ex) {
+			t.Errorf("test %d: LinfDistUnitary got: %v want: %v", j, got, v.ex)
+		}
+
+		for _, inc := range []int{1, 2, 3} {
+			n := len(v.x)
+			x := make([]float32, n*inc+1)
+			y := make([]float32, n*inc+1)
+			for i := 0; i < n; i++ {
+				x[i*inc] = v.x[i]
+				y[(n-1-i)*inc+1] = v.y[i]
+			}
+			// Walk y backwards from its last element.
+			var iy uintptr
+			if n > 0 {
+				iy = uintptr((n-1)*inc + 1)
+			}
+			got := LinfDistInc(x, y, uintptr(n), uintptr(inc), uintptr(-inc), 0, iy)
+			if !same(got, v.ex) {
+				t.Errorf("test %d inc %d: LinfDistInc got: %v want: %v", j, inc, got, v.ex)
+			}
+		}
+	}
+}
+
+func TestL2DistSq(t *testing.T) {
+	for j, v := range []struct {
+		x, y []float32
+		ex   float32
+	}{
+		{x: nil, y: nil, ex: 0},
+		{x: []float32{1, 2}, y: []float32{1, 2}, ex: 0},
+		{x: []float32{1}, y: []float32{-2}, ex: 9},
+		{x: []float32{0, 0}, y: []float32{3, 4}, ex: 25},
+		{x: []float32{-1, -2, -3, 4, 5}, y: []float32{1, 2, 3, -4, -4}, ex: 201},
+	} {
+		if got := L2DistSqUnitary(v.x, v.y); got != v.ex {
+			t.Errorf("test %d: L2DistSqUnitary got: %v want: %v", j, got, v.ex)
+		}
+
+		for _, inc := range []int{1, 2, 3} {
+			n := len(v.x)
+			x := make([]float32, n*inc+1)
+			y := make([]float32, n*inc+1)
+			for i := 0; i < n; i++ {
+				x[i*inc] = v.x[i]
+				y[(n-1-i)*inc+1] = v.y[i]
+			}
+			// Walk y backwards from its last element.
+			var iy uintptr
+			if n > 0 {
+				iy = uintptr((n-1)*inc + 1)
+			}
+			got := L2DistSqInc(x, y, uintptr(n), uintptr(inc), uintptr(-inc), 0, iy)
+			if got != v.ex {
+				t.Errorf("test %d inc %d: L2DistSqInc got: %v want: %v", j, inc, got, v.ex)
+			}
+		}
+	}
+}
+
+func TestWDotUnitary(t *testing.T) {
+	for j, v := range []struct {
+		w, x, y []float32
+		ex      float32
+	}{
+		{w: nil, x: nil, y: nil, ex: 0},
+		{w: []float32{1, 1, 1}, x: []float32{1, 2, 3}, y: []float32{4, 5, 6}, ex: 32},
+		{w: []float32{2, 0, -1}, x: []float32{1, 2, 3}, y: []float32{4, 5, 6}, ex: -10},
+		{w: []float32{0.5}, x: []float32{-4}, y: []float32{3}, ex: -6},
+	} {
+		if got := WDotUnitary(v.w, v.x, v.y); got != v.ex {
+			t.Errorf("test %d: WDotUnitary got: %v want: %v", j, got, v.ex)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package f32
+
+// Sum is
+//  var sum float32
+//  for _, v := range x {
+//  	sum += v
+//  }
+//  return sum
+// The elements are accumulated in four independent partial sums, so the
+// result may differ from the loop above by rounding.
+func Sum(x []float32) float32 {
+	var s0, s1, s2, s3 float32
+	n := len(x) &^ 3
+	for i := 0; i < n; i += 4 {
+		s0 += x[i]
+		s1 += x[i+1]
+		s2 += x[i+2]
+		s3 += x[i+3]
+	}
+	for _, v := range x[n:] {
+		s0 += v
+	}
+	return (s0 + s1) + (s2 + s3)
+}
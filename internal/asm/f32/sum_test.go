@@ -0,0 +1,25 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package f32
+
+import "testing"
+
+func TestSum(t *testing.T) {
+	for j, v := range []struct {
+		x  []float32
+		ex float32
+	}{
+		{x: nil, ex: 0},
+		{x: []float32{1}, ex: 1},
+		{x: []float32{1, 2, 3}, ex: 6},
+		{x: []float32{1, 2, 3, 4}, ex: 10},
+		{x: []float32{-1, 2, -3, 4, -5, 6, -7}, ex: -4},
+		{x: []float32{1, 1, 1, 1, 1, 1, 1, 1, 1}, ex: 9},
+	} {
+		if got := Sum(v.x); got != v.ex {
+			t.Errorf("test %d: Sum got: %v want: %v", j, got, v.ex)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package f32
+
+// WDotUnitary is
+//  for i, v := range w {
+//  	sum += v * x[i] * y[i]
+//  }
+//  return sum
+func WDotUnitary(w, x, y []float32) (sum float32) {
+	x = x[:len(w)]
+	y = y[:len(w)]
+	for i, v := range w {
+		sum += v * x[i] * y[i]
+	}
+	return sum
+}
@@ -0,0 +1,128 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"github.com/arjunsk/mat32/internal/asm/f32"
+	"golang.org/x/exp/rand"
+)
+
+// KMeans clusters the rows of data into k clusters, returning the k×c
+// matrix of cluster centroids and the index of the cluster assigned to each
+// row. The clusters are computed with Lloyd's algorithm using the squared
+// Euclidean distance, starting from centroids chosen by k-means++ seeding
+// drawn from src. Iteration stops when no assignment changes or after iters
+// updates of the centroids. A cluster that becomes empty is reseeded with
+// the row farthest from its assigned centroid.
+//
+// KMeans panics with ErrIndexOutOfRange if k is not in [1, r] where r is
+// the number of rows of data.
+func KMeans(data *Dense, k, iters int, src rand.Source) (centroids *Dense, assignments []int) {
+	r, _ := data.Dims()
+	if k < 1 || k > r {
+		panic(ErrIndexOutOfRange)
+	}
+	return lloyd(data, k, iters, rand.New(src))
+}
+
+// lloyd clusters the rows of data into k clusters using Lloyd's algorithm
+// with k-means++ initialization and squared Euclidean distance, returning
+// the k×c matrix of centroids and the cluster assignment of each row.
+// Iteration stops when the assignments no longer change or after iters
+// updates of the centroids. A cluster that becomes empty is reseeded with
+// the row that is farthest from its assigned centroid.
+func lloyd(data *Dense, k, iters int, rnd *rand.Rand) (centroids *Dense, assign []int) {
+	r, c := data.Dims()
+	centroids = NewDense(k, c, nil)
+	assign = make([]int, r)
+	dist := make([]float32, r)
+
+	// k-means++ seeding: choose each subsequent centroid with probability
+	// proportional to its squared distance from the nearest chosen one.
+	copy(centroids.RawRowView(0), data.RawRowView(rnd.Intn(r)))
+	for i := range dist {
+		dist[i] = f32.L2DistSqUnitary(data.RawRowView(i), centroids.RawRowView(0))
+	}
+	for j := 1; j < k; j++ {
+		var sum float64
+		for _, d := range dist {
+			sum += float64(d)
+		}
+		next := rnd.Intn(r)
+		if sum > 0 {
+			target := rnd.Float64() * sum
+			for i, d := range dist {
+				target -= float64(d)
+				if target < 0 {
+					next = i
+					break
+				}
+			}
+		}
+		cj := centroids.RawRowView(j)
+		copy(cj, data.RawRowView(next))
+		for i := range dist {
+			if d := f32.L2DistSqUnitary(data.RawRowView(i), cj); d < dist[i] {
+				dist[i] = d
+			}
+		}
+	}
+
+	counts := make([]int, k)
+	for it := 0; ; it++ {
+		changed := assignNearest(assign, dist, data, centroids)
+		if (!changed && it > 0) || it == iters {
+			break
+		}
+
+		zero(centroids.mat.Data)
+		for j := range counts {
+			counts[j] = 0
+		}
+		for i, j := range assign {
+			f32.AxpyUnitary(1, data.RawRowView(i), centroids.RawRowView(j))
+			counts[j]++
+		}
+		for j, n := range counts {
+			if n != 0 {
+				f32.ScalUnitary(1/float32(n), centroids.RawRowView(j))
+				continue
+			}
+			// Reseed the empty cluster with the worst-fitting row.
+			far := 0
+			for i, d := range dist {
+				if d > dist[far] {
+					far = i
+				}
+			}
+			copy(centroids.RawRowView(j), data.RawRowView(far))
+			dist[far] = 0
+		}
+	}
+	return centroids, assign
+}
+
+// assignNearest sets assign[i] to the index of the row of centroids
+// nearest to row i of data, and dist[i] to the squared distance between
+// them. It returns whether any assignment changed.
+func assignNearest(assign []int, dist []float32, data, centroids *Dense) (changed bool) {
+	k, _ := centroids.Dims()
+	for i := range assign {
+		row := data.RawRowView(i)
+		best := 0
+		bestDist := f32.L2DistSqUnitary(row, centroids.RawRowView(0))
+		for j := 1; j < k; j++ {
+			if d := f32.L2DistSqUnitary(row, centroids.RawRowView(j)); d < bestDist {
+				best, bestDist = j, d
+			}
+		}
+		if assign[i] != best {
+			changed = true
+		}
+		assign[i] = best
+		dist[i] = bestDist
+	}
+	return changed
+}
@@ -0,0 +1,90 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestKMeans(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i, test := range []struct {
+		centers [][]float32
+		perBlob int
+	}{
+		{
+			centers: [][]float32{{0, 0}, {10, 10}},
+			perBlob: 20,
+		},
+		{
+			centers: [][]float32{{-20, 0, 0}, {0, 20, 0}, {0, 0, 20}, {20, 20, 20}},
+			perBlob: 30,
+		},
+		{
+			centers: [][]float32{{0}, {5}, {10}, {15}, {20}},
+			perBlob: 1,
+		},
+	} {
+		k := len(test.centers)
+		c := len(test.centers[0])
+		data := NewDense(k*test.perBlob, c, nil)
+		blob := make([]int, k*test.perBlob)
+		for b, center := range test.centers {
+			for p := 0; p < test.perBlob; p++ {
+				row := b*test.perBlob + p
+				blob[row] = b
+				for j, v := range center {
+					data.Set(row, j, v+0.5*float32(rnd.NormFloat64()))
+				}
+			}
+		}
+
+		centroids, assign := KMeans(data, k, 100, rand.NewSource(uint64(i)))
+		if r, cc := centroids.Dims(); r != k || cc != c {
+			t.Errorf("unexpected centroid shape for test %d: got: %d×%d want: %d×%d", i, r, cc, k, c)
+			continue
+		}
+
+		// Each blob must map to a single distinct cluster.
+		label := make(map[int]int)
+		used := make(map[int]bool)
+		ok := true
+		for row, b := range blob {
+			l, seen := label[b]
+			if !seen {
+				if used[assign[row]] {
+					ok = false
+					break
+				}
+				label[b] = assign[row]
+				used[assign[row]] = true
+				continue
+			}
+			if l != assign[row] {
+				ok = false
+				break
+			}
+		}
+		if !ok {
+			t.Errorf("clusters not recovered for test %d: got assignments: %v", i, assign)
+			continue
+		}
+		for b, center := range test.centers {
+			got := centroids.RawRowView(label[b])
+			if d := L2Distance(NewVecDense(c, got), NewVecDense(c, center)); d > 1 {
+				t.Errorf("unexpected centroid for blob %d in test %d: got: %v want near: %v", b, i, got, center)
+			}
+		}
+	}
+
+	for _, k := range []int{0, 3} {
+		panicked, message := panics(func() { KMeans(NewDense(2, 2, nil), k, 1, rand.NewSource(1)) })
+		if !panicked || message != ErrIndexOutOfRange.Error() {
+			t.Errorf("expected ErrIndexOutOfRange for k=%d: got: %q", k, message)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+// Copyright ©2013 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"math"
+
+	"github.com/chewxy/math32"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas32"
+)
+
+// LU is a type for creating and using the LU factorization of a matrix.
+type LU struct {
+	lu    *Dense
+	pivot []int
+	cond  float32
+}
+
+// Factorize computes the LU factorization of the square matrix a and stores the
+// result. The LU decomposition will complete regardless of the singularity of a.
+//
+// The LU factorization is computed with partial pivoting, and so really the
+// decomposition is a PLU decomposition where P is a permutation matrix.
+func (lu *LU) Factorize(a Matrix) {
+	lu.factorize(a, 1)
+}
+
+// factorize computes the LU factorization of a and estimates its condition
+// number in the given norm, which must be 1 or +Inf.
+func (lu *LU) factorize(a Matrix, norm float32) {
+	r, c := a.Dims()
+	if r != c {
+		panic(ErrSquare)
+	}
+	if lu.lu == nil {
+		lu.lu = NewDense(r, r, nil)
+	} else {
+		lu.lu.Reset()
+		lu.lu.reuseAs(r, r)
+	}
+	lu.lu.Copy(a)
+	lu.pivot = useInt(lu.pivot, r)
+	anorm := Norm(lu.lu, norm)
+	getrf(lu.lu.mat, lu.pivot)
+	lu.updateCond(anorm, norm)
+}
+
+// getrf computes the LU factorization of the n×n matrix a in place using
+// partial pivoting with row interchanges. The unit lower triangular factor L
+// is stored below the diagonal of a and U is stored on and above it. Row i
+// was interchanged with row ipiv[i].
+func getrf(a blas32.General, ipiv []int) {
+	n := a.Rows
+	for j := 0; j < n; j++ {
+		col := blas32.Vector{Inc: a.Stride, Data: a.Data[j*a.Stride+j:]}
+		p := j + blas32.Iamax(n-j, col)
+		ipiv[j] = p
+		if a.Data[p*a.Stride+j] == 0 {
+			// The matrix is singular; there is nothing to eliminate.
+			continue
+		}
+		if p != j {
+			blas32.Swap(n,
+				blas32.Vector{Inc: 1, Data: a.Data[j*a.Stride : j*a.Stride+n]},
+				blas32.Vector{Inc: 1, Data: a.Data[p*a.Stride : p*a.Stride+n]})
+		}
+		if j == n-1 {
+			break
+		}
+		below := blas32.Vector{Inc: a.Stride, Data: a.Data[(j+1)*a.Stride+j:]}
+		blas32.Scal(n-j-1, 1/a.Data[j*a.Stride+j], below)
+		blas32.Ger(-1, below,
+			blas32.Vector{Inc: 1, Data: a.Data[j*a.Stride+j+1 : j*a.Stride+n]},
+			blas32.General{
+				Rows:   n - j - 1,
+				Cols:   n - j - 1,
+				Stride: a.Stride,
+				Data:   a.Data[(j+1)*a.Stride+j+1:],
+			})
+	}
+}
+
+// updateCond updates the stored condition number of the matrix. anorm is the
+// norm of the original matrix in the given norm.
+func (lu *LU) updateCond(anorm, norm float32) {
+	n := lu.lu.mat.Rows
+	for i := 0; i < n; i++ {
+		if lu.lu.mat.Data[i*lu.lu.mat.Stride+i] == 0 {
+			lu.cond = math32.Inf(1)
+			return
+		}
+	}
+	// The infinity norm of A^-1 is the 1-norm of A^-T.
+	trans := norm != 1
+	lu.cond = anorm * lu.invNorm1(trans)
+}
+
+// invNorm1 returns an estimate of the 1-norm of A^-1, or of A^-T if trans
+// is true, using Hager's method as refined by Higham. The estimate is a
+// lower bound that is exact in most cases.
+func (lu *LU) invNorm1(trans bool) float32 {
+	n := lu.lu.mat.Rows
+	x := getFloats(n, false)
+	defer putFloats(x)
+	y := getFloats(n, false)
+	defer putFloats(y)
+	z := getFloats(n, false)
+	defer putFloats(z)
+
+	for i := range x {
+		x[i] = 1 / float32(n)
+	}
+	var est float32
+	for iter := 0; iter < 5; iter++ {
+		copy(y, x)
+		lu.solveInPlace(y, trans)
+		var ynorm float32
+		for _, v := range y {
+			ynorm += math32.Abs(v)
+		}
+		if iter > 0 && ynorm <= est {
+			break
+		}
+		est = ynorm
+		for i, v := range y {
+			if v >= 0 {
+				z[i] = 1
+			} else {
+				z[i] = -1
+			}
+		}
+		lu.solveInPlace(z, !trans)
+		j := blas32.Iamax(n, blas32.Vector{Inc: 1, Data: z})
+		if iter > 0 && math32.Abs(z[j]) <= blas32.Dot(n, blas32.Vector{Inc: 1, Data: z}, blas32.Vector{Inc: 1, Data: x}) {
+			break
+		}
+		zero(x)
+		x[j] = 1
+	}
+
+	// Guard against the estimate being poor for specially
+	// structured matrices with an alternating test vector.
+	if n > 1 {
+		sign := float32(1)
+		for i := range x {
+			x[i] = sign * (1 + float32(i)/float32(n-1))
+			sign = -sign
+		}
+		lu.solveInPlace(x, trans)
+		var alt float32
+		for _, v := range x {
+			alt += math32.Abs(v)
+		}
+		alt *= 2 / float32(3*n)
+		if alt > est {
+			est = alt
+		}
+	}
+	return est
+}
+
+// solveInPlace overwrites b with the solution of A * x = b, or A^T * x = b
+// if trans is true, using the stored factorization.
+func (lu *LU) solveInPlace(b []float32, trans bool) {
+	n := lu.lu.mat.Rows
+	l := lu.lu.asTriDense(n, blas.Unit, blas.Lower)
+	u := lu.lu.asTriDense(n, blas.NonUnit, blas.Upper)
+	x := blas32.Vector{Inc: 1, Data: b}
+	if !trans {
+		for i, p := range lu.pivot {
+			b[i], b[p] = b[p], b[i]
+		}
+		blas32.Trsv(blas.NoTrans, l.mat, x)
+		blas32.Trsv(blas.NoTrans, u.mat, x)
+		return
+	}
+	blas32.Trsv(blas.Trans, u.mat, x)
+	blas32.Trsv(blas.Trans, l.mat, x)
+	for i := n - 1; i >= 0; i-- {
+		p := lu.pivot[i]
+		b[i], b[p] = b[p], b[i]
+	}
+}
+
+// solveDenseInPlace overwrites the n×k matrix b with the solution of
+// A * X = b, or A^T * X = b if trans is true, using the stored factorization.
+func (lu *LU) solveDenseInPlace(b *Dense, trans bool) {
+	n, k := b.Dims()
+	col := getFloats(n, false)
+	defer putFloats(col)
+	for j := 0; j < k; j++ {
+		blas32.Copy(n, blas32.Vector{Inc: b.mat.Stride, Data: b.mat.Data[j:]}, blas32.Vector{Inc: 1, Data: col})
+		lu.solveInPlace(col, trans)
+		blas32.Copy(n, blas32.Vector{Inc: 1, Data: col}, blas32.Vector{Inc: b.mat.Stride, Data: b.mat.Data[j:]})
+	}
+}
+
+// Solve solves a system of linear equations using the LU decomposition of a
+// matrix. It computes
+//  A * X = B if trans == false
+//  A^T * X = B if trans == true
+// In both cases, A is represented in LU decomposed form, and the matrix X is
+// stored into x.
+//
+// If A is singular to working precision, a Condition error is returned with
+// a value of +Inf and x is left unchanged. If A is ill-conditioned, with an
+// estimated condition number above ConditionTolerance, the best-effort
+// solution is still stored into x but a Condition error holding the estimate
+// is returned, so callers that care about accuracy should check the error.
+// The estimated condition number can be obtained by converting the error to
+// a float32.
+//
+// Solve panics with ErrShape if the number of rows of b is not the dimension
+// of A, and if the receiver does not contain a factorization.
+func (lu *LU) Solve(x *Dense, trans bool, b Matrix) error {
+	if lu.lu == nil || lu.lu.IsZero() {
+		panic("lu: no decomposition computed")
+	}
+	n := lu.lu.mat.Rows
+	br, bc := b.Dims()
+	if br != n {
+		panic(ShapeError{Got: [2]int{br, bc}, Want: [2]int{n, bc}})
+	}
+	if math32.IsInf(lu.cond, 1) {
+		return Condition(lu.cond)
+	}
+
+	x.reuseAs(n, bc)
+	x.Copy(b)
+	lu.solveDenseInPlace(x, trans)
+	if lu.cond > ConditionTolerance {
+		return Condition(lu.cond)
+	}
+	return nil
+}
+
+// Det returns the determinant of the matrix that has been factorized. In many
+// expressions, using LogDet will be more numerically stable.
+// Det will panic if the receiver does not contain a successful factorization.
+func (lu *LU) Det() float32 {
+	det, sign := lu.LogDet()
+	return math32.Exp(det) * sign
+}
+
+// LogDet returns the log of the absolute value of the determinant of the
+// factorized matrix, along with its sign, so that
+//  det(A) = sign * exp(logAbsDet)
+// The logarithms of the pivots are accumulated in float64, so LogDet does
+// not overflow for large matrices where the determinant itself is not
+// representable as a float32. LogDet returns (-Inf, 0) if the matrix is
+// singular.
+//
+// LogDet will panic if the receiver does not contain a successful factorization.
+func (lu *LU) LogDet() (logAbsDet float32, sign float32) {
+	if lu.lu == nil || lu.lu.IsZero() {
+		panic("lu: no decomposition computed")
+	}
+	n := lu.lu.mat.Rows
+	sign = 1
+	var sum float64
+	for i := 0; i < n; i++ {
+		v := lu.lu.mat.Data[i*lu.lu.mat.Stride+i]
+		if v == 0 {
+			return math32.Inf(-1), 0
+		}
+		if v < 0 {
+			sign = -sign
+		}
+		if lu.pivot[i] != i {
+			sign = -sign
+		}
+		sum += math.Log(math.Abs(float64(v)))
+	}
+	return float32(sum), sign
+}
+
+// Cond returns the condition number for the factorized matrix.
+// Cond will panic if the receiver does not contain a successful factorization.
+func (lu *LU) Cond() float32 {
+	if lu.lu == nil || lu.lu.IsZero() {
+		panic("lu: no decomposition computed")
+	}
+	return lu.cond
+}
+
+// Reset resets the factorization so that it can be reused as the receiver of a
+// dimensionally restricted operation.
+func (lu *LU) Reset() {
+	if lu.lu != nil {
+		lu.lu.Reset()
+	}
+	lu.pivot = lu.pivot[:0]
+}
@@ -0,0 +1,227 @@
+// Copyright ©2013 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"testing"
+
+	"github.com/chewxy/math32"
+
+	"golang.org/x/exp/rand"
+)
+
+// condExact returns the condition number of the invertible matrix a
+// in the given norm, computing the inverse column by column.
+func condExact(a *Dense, norm float32) float32 {
+	n, _ := a.Dims()
+	var lu LU
+	lu.Factorize(a)
+	inv := NewDense(n, n, nil)
+	col := make([]float32, n)
+	for j := 0; j < n; j++ {
+		zero(col)
+		col[j] = 1
+		lu.solveInPlace(col, false)
+		inv.SetCol(j, col)
+	}
+	return Norm(a, norm) * Norm(inv, norm)
+}
+
+func TestLUCond(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 5, 10} {
+		a := NewDense(n, n, nil)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				a.Set(i, j, float32(rnd.NormFloat64()))
+			}
+			a.Set(i, i, a.At(i, i)+float32(n))
+		}
+		for _, norm := range []float32{1, math32.Inf(1)} {
+			// The estimate is a lower bound on the true
+			// condition number and is rarely poor by more
+			// than a small factor.
+			want := condExact(a, norm)
+			got := a.Cond(norm)
+			if got > want*(1+1e-3) || got < want/3 {
+				t.Errorf("unexpected condition number for n=%d norm=%v: got: %v want: %v", n, norm, got, want)
+			}
+		}
+	}
+}
+
+func TestDenseCond(t *testing.T) {
+	for i, test := range []struct {
+		a    *Dense
+		norm float32
+		want float32
+		tol  float32
+	}{
+		{a: eye(4), norm: 1, want: 1, tol: 1e-6},
+		{a: eye(4), norm: math32.Inf(1), want: 1, tol: 1e-6},
+		{a: NewDense(2, 2, []float32{1, 2, 3, 4}), norm: 1, want: 21, tol: 1e-4},
+		{a: NewDense(2, 2, []float32{1, 2, 3, 4}), norm: math32.Inf(1), want: 21, tol: 1e-4},
+		{a: NewDense(2, 2, []float32{1, 2, 2, 4}), norm: 1, want: math32.Inf(1)},
+		{a: NewDense(2, 2, []float32{0, 0, 0, 0}), norm: 1, want: math32.Inf(1)},
+	} {
+		got := test.a.Cond(test.norm)
+		if math32.IsInf(test.want, 1) {
+			if !math32.IsInf(got, 1) {
+				t.Errorf("expected infinite condition number for test %d: got: %v", i, got)
+			}
+			continue
+		}
+		if !EqualWithinRel(got, test.want, test.tol) {
+			t.Errorf("unexpected condition number for test %d: got: %v want: %v", i, got, test.want)
+		}
+	}
+
+	near := NewDense(2, 2, []float32{1, 1, 1, 1.0001})
+	if c := near.Cond(1); c < 1e4 {
+		t.Errorf("expected large condition number for near-singular matrix: got: %v", c)
+	}
+
+	panicked, message := panics(func() { eye(2).Cond(2) })
+	if !panicked || message != ErrNormOrder.Error() {
+		t.Errorf("expected ErrNormOrder for 2-norm: got: %q", message)
+	}
+	panicked, message = panics(func() { NewDense(2, 3, nil).Cond(1) })
+	if !panicked || message != ErrSquare.Error() {
+		t.Errorf("expected ErrSquare for non-square matrix: got: %q", message)
+	}
+}
+
+func TestLogDet(t *testing.T) {
+	for i, test := range []struct {
+		a    *Dense
+		want float32
+	}{
+		{a: eye(3), want: 1},
+		{a: NewDense(1, 1, []float32{-2}), want: -2},
+		{a: NewDense(2, 2, []float32{1, 2, 3, 4}), want: -2},
+		{a: NewDense(2, 2, []float32{3, 4, 1, 2}), want: 2},
+		{a: NewDense(3, 3, []float32{2, -1, 0, -1, 2, -1, 0, -1, 2}), want: 4},
+		{a: NewDense(3, 3, []float32{0, 1, 2, 1, 0, 3, 4, -3, 8}), want: -2},
+		{a: NewDense(2, 2, []float32{1, 2, 2, 4}), want: 0},
+	} {
+		det := test.a.Det()
+		if !EqualWithinAbsOrRel(det, test.want, 1e-5, 1e-5) {
+			t.Errorf("unexpected Det for test %d: got: %v want: %v", i, det, test.want)
+		}
+		logAbs, sign := test.a.LogDet()
+		if test.want == 0 {
+			if !math32.IsInf(logAbs, -1) || sign != 0 {
+				t.Errorf("unexpected LogDet for singular test %d: got: (%v, %v) want: (-Inf, 0)", i, logAbs, sign)
+			}
+			continue
+		}
+		got := sign * math32.Exp(logAbs)
+		if !EqualWithinAbsOrRel(got, test.want, 1e-5, 1e-5) {
+			t.Errorf("unexpected LogDet for test %d: got: %v want: %v", i, got, test.want)
+		}
+	}
+
+	// The determinant of this upper triangular matrix is -10^100,
+	// which overflows float32, but its log is representable.
+	const n = 100
+	a := NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		a.Set(i, i, 10)
+	}
+	a.Set(0, 0, -10)
+	a.Set(0, 1, 3)
+	if det := a.Det(); !math32.IsInf(det, -1) {
+		t.Errorf("expected Det to overflow: got: %v", det)
+	}
+	logAbs, sign := a.LogDet()
+	want := float32(n) * math32.Log(10)
+	if sign != -1 || !EqualWithinRel(logAbs, want, 1e-5) {
+		t.Errorf("unexpected LogDet for large matrix: got: (%v, %v) want: (%v, -1)", logAbs, sign, want)
+	}
+}
+
+func TestLUSolve(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 5, 10} {
+		for _, bc := range []int{1, 3} {
+			a := randNormDense(n, n, rnd)
+			for i := 0; i < n; i++ {
+				a.Set(i, i, a.At(i, i)+float32(n))
+			}
+			want := randNormDense(n, bc, rnd)
+			for _, trans := range []bool{false, true} {
+				var b Dense
+				if trans {
+					b.Mul(a.T(), want)
+				} else {
+					b.Mul(a, want)
+				}
+				var lu LU
+				lu.Factorize(a)
+				var x Dense
+				err := lu.Solve(&x, trans, &b)
+				if err != nil {
+					t.Errorf("unexpected error for n=%d bc=%d trans=%t: %v", n, bc, trans, err)
+				}
+				if !EqualApprox(&x, want, 1e-4) {
+					t.Errorf("unexpected solution for n=%d bc=%d trans=%t", n, bc, trans)
+				}
+			}
+		}
+	}
+}
+
+func TestDenseSolve(t *testing.T) {
+	a := NewDense(3, 3, []float32{
+		4, -2, 1,
+		-2, 4, -2,
+		1, -2, 4,
+	})
+	want := NewDense(3, 2, []float32{1, 0, 2, -1, 3, 4})
+	var b Dense
+	b.Mul(a, want)
+	var x Dense
+	err := x.Solve(a, &b)
+	if err != nil {
+		t.Errorf("unexpected error for well-conditioned system: %v", err)
+	}
+	if !EqualApprox(&x, want, 1e-5) {
+		t.Errorf("unexpected solution\ngot:\n%v\nwant:\n%v", Formatted(&x), Formatted(want))
+	}
+
+	// A near-singular system still produces a solution, but
+	// reports its condition number.
+	near := NewDense(2, 2, []float32{1, 1, 1, 1 + 2*epsilon32})
+	x.Reset()
+	err = x.Solve(near, NewDense(2, 1, []float32{2, 2}))
+	cond, ok := err.(Condition)
+	if !ok {
+		t.Fatalf("expected Condition error for near-singular system: got: %v", err)
+	}
+	if float32(cond) <= ConditionTolerance || math32.IsInf(float32(cond), 1) {
+		t.Errorf("unexpected condition number for near-singular system: got: %v", cond)
+	}
+	if x.IsZero() || !x.IsFinite() {
+		t.Errorf("expected best-effort solution for near-singular system: got: %v", Formatted(&x))
+	}
+
+	// An exactly singular system is not solved.
+	x.Reset()
+	err = x.Solve(NewDense(2, 2, []float32{1, 2, 2, 4}), NewDense(2, 1, []float32{1, 1}))
+	if cond, ok := err.(Condition); !ok || !math32.IsInf(float32(cond), 1) {
+		t.Errorf("expected infinite Condition error for singular system: got: %v", err)
+	}
+	if !x.IsZero() {
+		t.Errorf("unexpected modification of receiver for singular system")
+	}
+
+	matched, message := panicsWith(func() {
+		var x Dense
+		x.Solve(eye(3), NewDense(2, 1, nil))
+	}, ErrShape)
+	if !matched {
+		t.Errorf("expected shape panic: got: %q", message)
+	}
+}
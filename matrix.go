@@ -7,6 +7,7 @@ package mat32
 import (
 	"github.com/chewxy/math32"
 
+	"github.com/arjunsk/mat32/internal/asm/f32"
 	"gonum.org/v1/gonum/blas"
 	"gonum.org/v1/gonum/blas/blas32"
 )
@@ -62,6 +63,13 @@ func (t Transpose) Untranspose() Matrix {
 	return t.Matrix
 }
 
+// ToDense returns a newly allocated Dense holding the elements of the
+// transposed matrix, so that the result is laid out contiguously in the
+// transposed order.
+func (t Transpose) ToDense() *Dense {
+	return DenseCopyOf(t)
+}
+
 // Untransposer is a type that can undo an implicit transpose.
 type Untransposer interface {
 	// Note: This interface is needed to unify all of the Transpose types. In
@@ -275,7 +283,7 @@ func Dot(a, b Vector) float32 {
 	la := a.Len()
 	lb := b.Len()
 	if la != lb {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{lb, 1}, Want: [2]int{la, 1}})
 	}
 	if arv, ok := a.(RawVectorer); ok {
 		if brv, ok := b.(RawVectorer); ok {
@@ -289,6 +297,127 @@ func Dot(a, b Vector) float32 {
 	return sum
 }
 
+// DotF64Acc returns the sum of the element-wise product of a and b, like
+// Dot, but accumulates the sum in float64 before rounding the result to
+// float32. This avoids the growth in rounding error of a float32
+// accumulator for long vectors, at some cost in speed.
+// DotF64Acc panics with ErrShape if the lengths of a and b differ.
+func DotF64Acc(a, b Vector) float32 {
+	n := a.Len()
+	if b.Len() != n {
+		panic(ShapeError{Got: [2]int{b.Len(), 1}, Want: [2]int{n, 1}})
+	}
+	if arv, ok := a.(RawVectorer); ok {
+		if brv, ok := b.(RawVectorer); ok {
+			amat := arv.RawVector()
+			bmat := brv.RawVector()
+			if amat.Inc == 1 && bmat.Inc == 1 {
+				return float32(f32.DdotUnitary(amat.Data[:n], bmat.Data[:n]))
+			}
+			return float32(f32.DdotInc(amat.Data, bmat.Data, uintptr(n), uintptr(amat.Inc), uintptr(bmat.Inc), 0, 0))
+		}
+	}
+	var sum float64
+	for i := 0; i < n; i++ {
+		sum += float64(a.AtVec(i)) * float64(b.AtVec(i))
+	}
+	return float32(sum)
+}
+
+// DotChecked returns the sum of the element-wise product of a and b.
+// Unlike Dot, DotChecked does not panic on mismatched input. It returns
+// ErrShape if the lengths of a and b differ, and ErrIllegalStride if the
+// increment and backing data of a raw vector do not describe a.Len() elements.
+func DotChecked(a, b Vector) (float32, error) {
+	n := a.Len()
+	if n != b.Len() {
+		return 0, ErrShape
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	for _, v := range [2]Vector{a, b} {
+		if rv, ok := v.(RawVectorer); ok {
+			vec := rv.RawVector()
+			if vec.Inc <= 0 || len(vec.Data) <= (n-1)*vec.Inc {
+				return 0, ErrIllegalStride
+			}
+		}
+	}
+	return Dot(a, b), nil
+}
+
+// WeightedDot returns the sum of the element-wise product of a, b and the
+// weights w,
+//  sum_i w_i * a_i * b_i
+// WeightedDot panics with ErrShape if the lengths of a, b and w are unequal.
+func WeightedDot(a, b, w Vector) float32 {
+	n := a.Len()
+	if b.Len() != n {
+		panic(ShapeError{Got: [2]int{b.Len(), 1}, Want: [2]int{n, 1}})
+	}
+	if w.Len() != n {
+		panic(ShapeError{Got: [2]int{w.Len(), 1}, Want: [2]int{n, 1}})
+	}
+	if arv, ok := a.(RawVectorer); ok {
+		if brv, ok := b.(RawVectorer); ok {
+			if wrv, ok := w.(RawVectorer); ok {
+				amat := arv.RawVector()
+				bmat := brv.RawVector()
+				wmat := wrv.RawVector()
+				if amat.Inc == 1 && bmat.Inc == 1 && wmat.Inc == 1 {
+					return f32.WDotUnitary(wmat.Data[:n], amat.Data, bmat.Data)
+				}
+			}
+		}
+	}
+	var sum float32
+	for i := 0; i < n; i++ {
+		sum += w.AtVec(i) * a.AtVec(i) * b.AtVec(i)
+	}
+	return sum
+}
+
+// DotIgnoreNaN returns the sum of the element-wise product of a and b over
+// the positions where both a[i] and b[i] are finite, together with the
+// number of such positions. Pairs containing a NaN, which conventionally
+// marks a missing value, or an infinity are skipped. DotIgnoreNaN panics
+// with ErrShape if the lengths of a and b differ.
+func DotIgnoreNaN(a, b Vector) (dot float32, count int) {
+	n := a.Len()
+	if b.Len() != n {
+		panic(ShapeError{Got: [2]int{b.Len(), 1}, Want: [2]int{n, 1}})
+	}
+	for i := 0; i < n; i++ {
+		av := a.AtVec(i)
+		bv := b.AtVec(i)
+		if !isFinite(av) || !isFinite(bv) {
+			continue
+		}
+		dot += av * bv
+		count++
+	}
+	return dot, count
+}
+
+// SameDims returns whether the matrices a and b have the same dimensions,
+// and so are valid operands for element-wise operations such as Add and
+// MulElem.
+func SameDims(a, b Matrix) bool {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	return ar == br && ac == bc
+}
+
+// BroadcastCompatible returns whether b can be broadcast across a by
+// AddBroadcast without panicking. For an r×c matrix a, this holds when b
+// is r×c, 1×c or r×1. The relation is not symmetric.
+func BroadcastCompatible(a, b Matrix) bool {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	return (br == ar || br == 1) && (bc == ac || bc == 1) && (br == ar || bc == ac)
+}
+
 // Equal returns whether the matrices a and b have the same size
 // and are element-wise equal.
 func Equal(a, b Matrix) bool {
@@ -347,8 +476,9 @@ func Equal(a, b Matrix) bool {
 }
 
 // EqualApprox returns whether the matrices a and b have the same size and contain all equal
-// elements with tolerance for element-wise equality specified by epsilon. Matrices
-// with non-equal shapes are not equal.
+// elements with tolerance for element-wise equality specified by epsilon, as
+// defined by WithinTol. DefaultTol is a reasonable choice of epsilon for float32
+// results. Matrices with non-equal shapes are not equal.
 func EqualApprox(a, b Matrix, epsilon float32) bool {
 	ar, ac := a.Dims()
 	br, bc := b.Dims()
@@ -364,7 +494,7 @@ func EqualApprox(a, b Matrix, epsilon float32) bool {
 			if aTrans == bTrans {
 				for i := 0; i < ra.Rows; i++ {
 					for j := 0; j < ra.Cols; j++ {
-						if !EqualWithinAbsOrRel(ra.Data[i*ra.Stride+j], rb.Data[i*rb.Stride+j], epsilon, epsilon) {
+						if !WithinTol(ra.Data[i*ra.Stride+j], rb.Data[i*rb.Stride+j], epsilon) {
 							return false
 						}
 					}
@@ -373,7 +503,7 @@ func EqualApprox(a, b Matrix, epsilon float32) bool {
 			}
 			for i := 0; i < ra.Rows; i++ {
 				for j := 0; j < ra.Cols; j++ {
-					if !EqualWithinAbsOrRel(ra.Data[i*ra.Stride+j], rb.Data[j*rb.Stride+i], epsilon, epsilon) {
+					if !WithinTol(ra.Data[i*ra.Stride+j], rb.Data[j*rb.Stride+i], epsilon) {
 						return false
 					}
 				}
@@ -387,7 +517,7 @@ func EqualApprox(a, b Matrix, epsilon float32) bool {
 			// If the raw vectors are the same length they must either both be
 			// transposed or both not transposed (or have length 1).
 			for i := 0; i < ra.n; i++ {
-				if !EqualWithinAbsOrRel(ra.mat.Data[i*ra.mat.Inc], rb.mat.Data[i*rb.mat.Inc], epsilon, epsilon) {
+				if !WithinTol(ra.mat.Data[i*ra.mat.Inc], rb.mat.Data[i*rb.mat.Inc], epsilon) {
 					return false
 				}
 			}
@@ -396,7 +526,7 @@ func EqualApprox(a, b Matrix, epsilon float32) bool {
 	}
 	for i := 0; i < ar; i++ {
 		for j := 0; j < ac; j++ {
-			if !EqualWithinAbsOrRel(a.At(i, j), b.At(i, j), epsilon, epsilon) {
+			if !WithinTol(a.At(i, j), b.At(i, j), epsilon) {
 				return false
 			}
 		}
@@ -404,6 +534,49 @@ func EqualApprox(a, b Matrix, epsilon float32) bool {
 	return true
 }
 
+// FirstNonFinite returns the row and column of the first element of a, in
+// row-major order, that is NaN or ±Inf. If all elements of a are finite,
+// ok is false.
+func FirstNonFinite(a Matrix) (i, j int, ok bool) {
+	aU, trans := untranspose(a)
+	if rma, isRaw := aU.(RawMatrixer); isRaw {
+		rm := rma.RawMatrix()
+		if !trans {
+			for i := 0; i < rm.Rows; i++ {
+				for j, v := range rm.Data[i*rm.Stride : i*rm.Stride+rm.Cols] {
+					if !isFinite(v) {
+						return i, j, true
+					}
+				}
+			}
+			return 0, 0, false
+		}
+		for i := 0; i < rm.Cols; i++ {
+			for j := 0; j < rm.Rows; j++ {
+				if !isFinite(rm.Data[j*rm.Stride+i]) {
+					return i, j, true
+				}
+			}
+		}
+		return 0, 0, false
+	}
+
+	r, c := a.Dims()
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			if !isFinite(a.At(i, j)) {
+				return i, j, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// isFinite returns whether v is neither NaN nor ±Inf.
+func isFinite(v float32) bool {
+	return !math32.IsNaN(v) && !math32.IsInf(v, 0)
+}
+
 // Max returns the largest element value of the matrix A.
 // Max will panic with matrix.ErrShape if the matrix has zero size.
 func Max(a Matrix) float32 {
@@ -553,7 +726,8 @@ func Sum(a Matrix) float32 {
 }
 
 // Trace returns the trace of the matrix. Trace will panic if the
-// matrix is not square.
+// matrix is not square. Transposed views are handled without
+// materializing the transpose.
 func Trace(a Matrix) float32 {
 	r, c := a.Dims()
 	if r != c {
@@ -576,6 +750,13 @@ func Trace(a Matrix) float32 {
 			t += rm.Data[i*rm.Stride+i]
 		}
 		return t
+	case RawSymmetricer:
+		rm := m.RawSymmetric()
+		var t float32
+		for i := 0; i < r; i++ {
+			t += rm.Data[i*rm.Stride+i]
+		}
+		return t
 
 	default:
 		var t float32
@@ -586,6 +767,44 @@ func Trace(a Matrix) float32 {
 	}
 }
 
+// TraceProduct returns the trace of the product of the transpose of a
+// with b,
+//  trace(aᵀ * b) = sum_{i,j} a[i,j] * b[i,j]
+// which is the Frobenius inner product of a and b. The product aᵀ * b is
+// not formed. TraceProduct panics with ErrShape if a and b do not have
+// the same dimensions.
+func TraceProduct(a, b Matrix) float32 {
+	r, c := a.Dims()
+	br, bc := b.Dims()
+	if r != br || c != bc {
+		panic(ShapeError{Got: [2]int{br, bc}, Want: [2]int{r, c}})
+	}
+
+	aU, aTrans := untranspose(a)
+	bU, bTrans := untranspose(b)
+	if ra, ok := aU.(RawMatrixer); ok {
+		if rb, ok := bU.(RawMatrixer); ok && aTrans == bTrans {
+			amat := ra.RawMatrix()
+			bmat := rb.RawMatrix()
+			// Both operands share a storage order, so the
+			// sum may be taken over the stored rows.
+			var t float32
+			for i := 0; i < amat.Rows; i++ {
+				t += f32.DotUnitary(amat.Data[i*amat.Stride:i*amat.Stride+amat.Cols], bmat.Data[i*bmat.Stride:i*bmat.Stride+bmat.Cols])
+			}
+			return t
+		}
+	}
+
+	var t float32
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			t += a.At(i, j) * b.At(i, j)
+		}
+	}
+	return t
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -5,11 +5,13 @@
 package mat32
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
 
 	"github.com/chewxy/math32"
+	"golang.org/x/exp/rand"
 
 	"gonum.org/v1/gonum/blas"
 	"gonum.org/v1/gonum/blas/blas32"
@@ -25,6 +27,19 @@ func panics(fn func()) (panicked bool, message string) {
 	return
 }
 
+// panicsWith returns whether fn panics with an error matching target
+// under errors.Is, along with the panic message.
+func panicsWith(fn func(), target error) (matched bool, message string) {
+	defer func() {
+		r := recover()
+		err, ok := r.(error)
+		matched = ok && errors.Is(err, target)
+		message = fmt.Sprint(r)
+	}()
+	fn()
+	return
+}
+
 func flatten(f [][]float32) (r, c int, d []float32) {
 	r = len(f)
 	if r == 0 {
@@ -250,6 +265,161 @@ func TestDot(t *testing.T) {
 	testTwoInputFunc(t, "Dot", f, denseComparison, sameAnswerFloatApproxTol(1e-6), legalTypesVectorVector, legalSizeSameVec)
 }
 
+func TestDotF64Acc(t *testing.T) {
+	t.Parallel()
+	// 0.1 is not representable, and summing many copies of its
+	// rounded product in float32 accumulates rounding error.
+	const n = 1 << 17
+	a := make([]float32, n)
+	b := make([]float32, n)
+	var want float64
+	for i := range a {
+		a[i] = 0.1
+		b[i] = 1 + float32(i%7)/8
+		want += float64(a[i]) * float64(b[i])
+	}
+
+	got := DotF64Acc(NewVecDense(n, a), NewVecDense(n, b))
+	if got != float32(want) {
+		t.Errorf("unexpected float64-accumulated dot: got: %v want: %v", got, float32(want))
+	}
+	if plain := Dot(NewVecDense(n, a), NewVecDense(n, b)); math32.Abs(plain-float32(want)) <= math32.Abs(got-float32(want)) {
+		t.Errorf("plain float32 dot unexpectedly as accurate: got: %v float64 accumulation: %v want: %v", plain, got, want)
+	}
+
+	// Strided and non-raw inputs give the same result.
+	strided := make([]float32, 2*n)
+	for i, v := range a {
+		strided[2*i] = v
+	}
+	col := NewDense(n, 2, strided).ColView(0)
+	if got := DotF64Acc(col, NewVecDense(n, b)); got != float32(want) {
+		t.Errorf("unexpected strided dot: got: %v want: %v", got, float32(want))
+	}
+	if got := DotF64Acc(&basicVector{a}, NewVecDense(n, b)); got != float32(want) {
+		t.Errorf("unexpected non-raw dot: got: %v want: %v", got, float32(want))
+	}
+
+	matched, message := panicsWith(func() { DotF64Acc(NewVecDense(2, nil), NewVecDense(3, nil)) }, ErrShape)
+	if !matched {
+		t.Errorf("expected ErrShape for mismatched lengths: got: %q", message)
+	}
+}
+
+func TestDotChecked(t *testing.T) {
+	for i, test := range []struct {
+		a, b Vector
+		want float32
+		err  error
+	}{
+		{
+			a:    NewVecDense(3, []float32{1, 2, 3}),
+			b:    NewVecDense(3, []float32{4, 5, 6}),
+			want: 32,
+		},
+		{
+			a:    NewDense(3, 2, []float32{1, 0, 2, 0, 3, 0}).ColView(0),
+			b:    &basicVector{m: []float32{4, 5, 6}},
+			want: 32,
+		},
+		{
+			a:   NewVecDense(3, []float32{1, 2, 3}),
+			b:   NewVecDense(2, []float32{4, 5}),
+			err: ErrShape,
+		},
+		{
+			a: NewVecDense(0, nil),
+			b: NewVecDense(0, nil),
+		},
+		{
+			a:   &VecDense{mat: blas32.Vector{Inc: 2, Data: []float32{1, 0, 2}}, n: 3},
+			b:   NewVecDense(3, []float32{4, 5, 6}),
+			err: ErrIllegalStride,
+		},
+	} {
+		got, err := DotChecked(test.a, test.b)
+		if err != test.err {
+			t.Errorf("unexpected error for test %d: got: %v want: %v", i, err, test.err)
+		}
+		if got != test.want {
+			t.Errorf("unexpected dot product for test %d: got: %v want: %v", i, got, test.want)
+		}
+	}
+}
+
+func TestWeightedDot(t *testing.T) {
+	for i, test := range []struct {
+		a, b, w []float32
+	}{
+		{a: []float32{1}, b: []float32{2}, w: []float32{3}},
+		{a: []float32{1, 2, 3}, b: []float32{4, 5, 6}, w: []float32{1, 1, 1}},
+		{a: []float32{1, 2, 3}, b: []float32{4, 5, 6}, w: []float32{0.5, 0, -2}},
+		{a: []float32{-1, 2, 0.25, 8, 3}, b: []float32{4, -5, 6, 0.5, 1}, w: []float32{1, 2, 3, 4, 5}},
+	} {
+		n := len(test.a)
+		var want float32
+		for j := range test.a {
+			want += test.w[j] * test.a[j] * test.b[j]
+		}
+		a := NewVecDense(n, test.a)
+		b := NewVecDense(n, test.b)
+		w := NewVecDense(n, test.w)
+		if got := WeightedDot(a, b, w); !EqualWithinAbsOrRel(got, want, 1e-6, 1e-6) {
+			t.Errorf("unexpected weighted dot for test %d: got: %v want: %v", i, got, want)
+		}
+		if got := WeightedDot(&basicVector{test.a}, b, w); !EqualWithinAbsOrRel(got, want, 1e-6, 1e-6) {
+			t.Errorf("unexpected non-raw weighted dot for test %d: got: %v want: %v", i, got, want)
+		}
+
+		ones := make([]float32, n)
+		for j := range ones {
+			ones[j] = 1
+		}
+		if got, want := WeightedDot(a, b, NewVecDense(n, ones)), Dot(a, b); !EqualWithinAbsOrRel(got, want, 1e-6, 1e-6) {
+			t.Errorf("uniform weights do not reduce to Dot for test %d: got: %v want: %v", i, got, want)
+		}
+	}
+
+	matched, message := panicsWith(func() { WeightedDot(NewVecDense(2, nil), NewVecDense(2, nil), NewVecDense(3, nil)) }, ErrShape)
+	if !matched {
+		t.Errorf("expected ErrShape for mismatched weights: got: %q", message)
+	}
+}
+
+func TestDotIgnoreNaN(t *testing.T) {
+	nan := math32.NaN()
+	inf := math32.Inf(1)
+	for i, test := range []struct {
+		a, b  []float32
+		dot   float32
+		count int
+	}{
+		{a: []float32{1, 2, 3}, b: []float32{4, 5, 6}, dot: 32, count: 3},
+		{a: []float32{1, nan, 3}, b: []float32{4, 5, 6}, dot: 22, count: 2},
+		{a: []float32{1, 2, 3, 4}, b: []float32{nan, 5, nan, 2}, dot: 18, count: 2},
+		{a: []float32{nan, 2, 3, -1, 0.5}, b: []float32{1, nan, 2, 3, 4}, dot: 5, count: 3},
+		{a: []float32{inf, 2}, b: []float32{1, 3}, dot: 6, count: 1},
+		{a: []float32{nan, nan}, b: []float32{nan, 1}, dot: 0, count: 0},
+	} {
+		n := len(test.a)
+		for _, args := range [][2]Vector{
+			{NewVecDense(n, test.a), NewVecDense(n, test.b)},
+			{&basicVector{test.a}, &basicVector{test.b}},
+		} {
+			dot, count := DotIgnoreNaN(args[0], args[1])
+			if dot != test.dot || count != test.count {
+				t.Errorf("unexpected result for test %d: got: (%v, %d) want: (%v, %d)",
+					i, dot, count, test.dot, test.count)
+			}
+		}
+	}
+
+	matched, message := panicsWith(func() { DotIgnoreNaN(NewVecDense(2, nil), NewVecDense(3, nil)) }, ErrShape)
+	if !matched {
+		t.Errorf("expected ErrShape for mismatched lengths: got: %q", message)
+	}
+}
+
 func TestEqual(t *testing.T) {
 	f := func(a, b Matrix) interface{} {
 		return Equal(a, b)
@@ -282,6 +452,194 @@ func TestMin(t *testing.T) {
 	testOneInputFunc(t, "Min", f, denseComparison, sameAnswerFloat, isAnyType, isAnySize)
 }
 
+func TestSameDimsBroadcastCompatible(t *testing.T) {
+	t.Parallel()
+	for i, test := range []struct {
+		a, b      Matrix
+		same      bool
+		broadcast bool
+	}{
+		{a: NewDense(3, 4, nil), b: NewDense(3, 4, nil), same: true, broadcast: true},
+		{a: NewDense(3, 4, nil), b: NewDense(4, 3, nil).T(), same: true, broadcast: true},
+		{a: NewDense(3, 4, nil), b: NewDense(1, 4, nil), broadcast: true},
+		{a: NewDense(3, 4, nil), b: NewDense(3, 1, nil), broadcast: true},
+		{a: NewDense(3, 4, nil), b: NewVecDense(3, nil), broadcast: true},
+		{a: NewDense(3, 4, nil), b: NewVecDense(4, nil).T(), broadcast: true},
+		{a: NewDense(1, 4, nil), b: NewDense(1, 1, nil), broadcast: true},
+		{a: NewDense(3, 4, nil), b: NewDense(1, 1, nil)},
+		{a: NewDense(3, 4, nil), b: NewDense(4, 3, nil)},
+		{a: NewDense(3, 4, nil), b: NewDense(1, 3, nil)},
+		{a: NewDense(3, 4, nil), b: NewDense(4, 1, nil)},
+		{a: NewDense(1, 4, nil), b: NewDense(3, 4, nil)},
+	} {
+		if got := SameDims(test.a, test.b); got != test.same {
+			t.Errorf("unexpected SameDims result for test %d: got: %t want: %t", i, got, test.same)
+		}
+		if got := BroadcastCompatible(test.a, test.b); got != test.broadcast {
+			t.Errorf("unexpected BroadcastCompatible result for test %d: got: %t want: %t", i, got, test.broadcast)
+		}
+		panicked, _ := panics(func() {
+			var m Dense
+			m.AddBroadcast(test.a, test.b)
+		})
+		if panicked == test.broadcast {
+			t.Errorf("BroadcastCompatible disagrees with AddBroadcast for test %d", i)
+		}
+	}
+}
+
+func TestIsFinite(t *testing.T) {
+	nan := math32.NaN()
+	inf := math32.Inf(1)
+	for k, test := range []struct {
+		a      [][]float32
+		i, j   int
+		finite bool
+	}{
+		{
+			a:      [][]float32{{1, 2, 3}, {4, 5, 6}},
+			finite: true,
+		},
+		{
+			a: [][]float32{{1, 2, 3}, {4, nan, 6}},
+			i: 1, j: 1,
+		},
+		{
+			a: [][]float32{{1, 2, inf}, {4, 5, 6}},
+			i: 0, j: 2,
+		},
+		{
+			a: [][]float32{{1, 2}, {3, 4}, {-inf, nan}},
+			i: 2, j: 0,
+		},
+	} {
+		a := NewDense(flatten(test.a))
+		if got := a.IsFinite(); got != test.finite {
+			t.Errorf("unexpected IsFinite for test %d: got: %t want: %t", k, got, test.finite)
+		}
+		i, j, ok := FirstNonFinite(a)
+		if ok == test.finite || i != test.i || j != test.j {
+			t.Errorf("unexpected FirstNonFinite for test %d: got: (%d, %d, %t) want: (%d, %d, %t)",
+				k, i, j, ok, test.i, test.j, !test.finite)
+		}
+		i, j, ok = FirstNonFinite(asBasicMatrix(a))
+		if ok == test.finite || i != test.i || j != test.j {
+			t.Errorf("unexpected FirstNonFinite for basic matrix test %d: got: (%d, %d, %t) want: (%d, %d, %t)",
+				k, i, j, ok, test.i, test.j, !test.finite)
+		}
+		// The transpose is scanned in its own row-major order.
+		at := DenseCopyOf(a.T())
+		wi, wj, wok := FirstNonFinite(at)
+		i, j, ok = FirstNonFinite(a.T())
+		if ok != wok || i != wi || j != wj {
+			t.Errorf("unexpected FirstNonFinite for transpose test %d: got: (%d, %d, %t) want: (%d, %d, %t)",
+				k, i, j, ok, wi, wj, wok)
+		}
+
+		for r := range test.a {
+			v := NewVecDense(len(test.a[r]), test.a[r])
+			want := true
+			for _, x := range test.a[r] {
+				want = want && isFinite(x)
+			}
+			if got := v.IsFinite(); got != want {
+				t.Errorf("unexpected VecDense IsFinite for test %d row %d: got: %t want: %t", k, r, got, want)
+			}
+		}
+	}
+
+	// Strided vector views skip non-finite elements between them.
+	col := NewDense(3, 2, []float32{1, nan, 2, inf, 3, nan}).ColView(0).(*VecDense)
+	if !col.IsFinite() {
+		t.Errorf("unexpected non-finite result for strided finite column")
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	nan := math32.NaN()
+	inf := math32.Inf(1)
+	for i, test := range []struct {
+		a, want [][]float32
+	}{
+		{
+			a:    [][]float32{{1, 2}, {3, 4}},
+			want: [][]float32{{1, 2}, {3, 4}},
+		},
+		{
+			a:    [][]float32{{nan, 2, inf}, {-inf, 0, -1}},
+			want: [][]float32{{0, 2, 1e6}, {-1e6, 0, -1}},
+		},
+		{
+			a:    [][]float32{{nan}, {nan}, {5}},
+			want: [][]float32{{0}, {0}, {5}},
+		},
+	} {
+		a := NewDense(flatten(test.a))
+		a.Sanitize(0, 1e6, -1e6)
+		want := NewDense(flatten(test.want))
+		if !Equal(a, want) {
+			t.Errorf("unexpected result for test %d\ngot:\n%v\nwant:\n%v", i, Formatted(a), Formatted(want))
+		}
+	}
+
+	// Only the elements of a view are replaced.
+	a := NewDense(2, 3, []float32{nan, inf, nan, -inf, nan, inf})
+	a.Slice(0, 2, 1, 3).(*Dense).Sanitize(1, 2, 3)
+	if !math32.IsNaN(a.At(0, 0)) || !math32.IsInf(a.At(1, 0), -1) {
+		t.Errorf("unexpected modification outside view")
+	}
+	want := NewDense(2, 2, []float32{2, 1, 1, 2})
+	if !Equal(a.Slice(0, 2, 1, 3), want) {
+		t.Errorf("unexpected result for view: got: %v want: %v", Formatted(a.Slice(0, 2, 1, 3)), Formatted(want))
+	}
+}
+
+func TestTransposeToDense(t *testing.T) {
+	for i, a := range []Matrix{
+		NewDense(1, 1, []float32{5}),
+		NewDense(2, 3, []float32{1, 2, 3, 4, 5, 6}),
+		NewDense(4, 4, []float32{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}).Slice(1, 4, 0, 2),
+		NewSymDense(2, []float32{1, 2, 2, 3}),
+		asBasicMatrix(NewDense(3, 2, []float32{1, 2, 3, 4, 5, 6})),
+	} {
+		tr := Transpose{a}
+		got := tr.ToDense()
+		r, c := tr.Dims()
+		if gr, gc := got.Dims(); gr != r || gc != c {
+			t.Errorf("unexpected dimensions for test %d: got: %d×%d want: %d×%d", i, gr, gc, r, c)
+			continue
+		}
+		if got.mat.Stride != c {
+			t.Errorf("unexpected stride for test %d: got: %d want: %d", i, got.mat.Stride, c)
+		}
+		for j := 0; j < r; j++ {
+			for k := 0; k < c; k++ {
+				if got.At(j, k) != tr.At(j, k) {
+					t.Errorf("unexpected element for test %d at (%d, %d): got: %v want: %v", i, j, k, got.At(j, k), tr.At(j, k))
+				}
+			}
+		}
+	}
+
+	for i, v := range []Vector{
+		NewVecDense(3, []float32{1, 2, 3}),
+		NewDense(3, 2, []float32{1, 2, 3, 4, 5, 6}).ColView(1),
+		&basicVector{[]float32{4, 5}},
+	} {
+		tr := TransposeVec{v}
+		got := tr.ToDense()
+		if got.Len() != v.Len() || got.mat.Inc != 1 {
+			t.Errorf("unexpected shape for vector test %d: got: len=%d inc=%d want: len=%d inc=1", i, got.Len(), got.mat.Inc, v.Len())
+			continue
+		}
+		for j := 0; j < v.Len(); j++ {
+			if got.AtVec(j) != tr.At(0, j) {
+				t.Errorf("unexpected element for vector test %d at %d: got: %v want: %v", i, j, got.AtVec(j), tr.At(0, j))
+			}
+		}
+	}
+}
+
 func TestNorm(t *testing.T) {
 	for i, test := range []struct {
 		a    [][]float32
@@ -370,18 +728,42 @@ func TestSum(t *testing.T) {
 
 func TestTrace(t *testing.T) {
 	for _, test := range []struct {
-		a     *Dense
+		a     Matrix
 		trace float32
 	}{
 		{
 			a:     NewDense(3, 3, []float32{1, 2, 3, 4, 5, 6, 7, 8, 9}),
 			trace: 15,
 		},
+		{
+			a:     NewDense(3, 3, []float32{1, 2, 3, 4, 5, 6, 7, 8, 9}).T(),
+			trace: 15,
+		},
+		{
+			a:     NewDense(4, 4, []float32{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}).Slice(1, 3, 2, 4).T(),
+			trace: 19,
+		},
+		{
+			a:     NewSymDense(3, []float32{1, 2, 3, 2, -4, 5, 3, 5, 6}),
+			trace: 3,
+		},
+		{
+			a:     NewSymDense(2, []float32{7, 1, 1, 2}).T(),
+			trace: 9,
+		},
 	} {
 		trace := Trace(test.a)
 		if trace != test.trace {
 			t.Errorf("Trace mismatch. Want %v, got %v", test.trace, trace)
 		}
+		if d, ok := test.a.(*Dense); ok {
+			if trace := d.Trace(); trace != test.trace {
+				t.Errorf("Dense.Trace mismatch. Want %v, got %v", test.trace, trace)
+			}
+		}
+	}
+	if panicked, message := panics(func() { Trace(NewDense(2, 3, nil).T()) }); !panicked || message != ErrSquare.Error() {
+		t.Errorf("expected ErrSquare for non-square transpose: got: %q", message)
 	}
 	f := func(a Matrix) interface{} {
 		return Trace(a)
@@ -392,6 +774,46 @@ func TestTrace(t *testing.T) {
 	testOneInputFunc(t, "Trace", f, denseComparison, sameAnswerFloat, isAnyType, isSquare)
 }
 
+func TestTraceProduct(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for i, test := range []struct {
+		a, b Matrix
+	}{
+		{a: randNormDense(3, 3, rnd), b: randNormDense(3, 3, rnd)},
+		{a: randNormDense(4, 2, rnd), b: randNormDense(4, 2, rnd)},
+		{a: randNormDense(2, 4, rnd).T(), b: randNormDense(4, 2, rnd)},
+		{a: randNormDense(4, 2, rnd), b: randNormDense(2, 4, rnd).T()},
+		{a: randNormDense(2, 4, rnd).T(), b: randNormDense(2, 4, rnd).T()},
+		{a: randNormDense(5, 5, rnd).Slice(1, 4, 2, 4), b: randNormDense(3, 2, rnd)},
+		{a: asBasicMatrix(randNormDense(3, 4, rnd)), b: randNormDense(3, 4, rnd)},
+	} {
+		var p Dense
+		p.Mul(test.a.T(), test.b)
+		want := Trace(&p)
+		got := TraceProduct(test.a, test.b)
+		if !EqualWithinAbsOrRel(got, want, 1e-5, 1e-5) {
+			t.Errorf("unexpected trace product for test %d: got: %v want: %v", i, got, want)
+		}
+
+		var sum float32
+		r, c := test.a.Dims()
+		for i := 0; i < r; i++ {
+			for j := 0; j < c; j++ {
+				sum += test.a.At(i, j) * test.b.At(i, j)
+			}
+		}
+		if !EqualWithinAbsOrRel(got, sum, 1e-5, 1e-5) {
+			t.Errorf("trace product differs from element-wise sum for test %d: got: %v want: %v", i, got, sum)
+		}
+	}
+
+	matched, message := panicsWith(func() { TraceProduct(NewDense(2, 3, nil), NewDense(3, 2, nil)) }, ErrShape)
+	if !matched {
+		t.Errorf("expected ErrShape for mismatched dimensions: got: %q", message)
+	}
+}
+
 func TestDoer(t *testing.T) {
 	type MatrixDoer interface {
 		Matrix
@@ -259,3 +259,266 @@ func denseEqualApprox(a *Dense, acomp matComp, tol float32) bool {
 	}
 	return true
 }
+
+func TestMulVectorOperand(t *testing.T) {
+	// randDense returns an r×c matrix, stored transposed and as
+	// a strided view according to trans and view.
+	randDense := func(r, c int, trans, view bool) Matrix {
+		if trans {
+			r, c = c, r
+		}
+		m := NewDense(r+2, c+3, nil)
+		randomSlice(m.mat.Data)
+		if view {
+			m = m.Slice(1, r+1, 2, c+2).(*Dense)
+		} else {
+			m = m.Slice(0, r, 0, c).(*Dense)
+			m = DenseCopyOf(m)
+		}
+		if trans {
+			return m.T()
+		}
+		return m
+	}
+	for _, test := range []struct {
+		ar, ac, bc int
+	}{
+		{1, 1, 1},
+		{5, 3, 1},
+		{1, 4, 6},
+		{1, 7, 1},
+		{20, 30, 1},
+		{1, 30, 20},
+	} {
+		for _, aTrans := range []bool{false, true} {
+			for _, bTrans := range []bool{false, true} {
+				for _, view := range []bool{false, true} {
+					a := randDense(test.ar, test.ac, aTrans, view)
+					b := randDense(test.ac, test.bc, bTrans, view)
+
+					want := NewDense(test.ar, test.bc, nil)
+					for i := 0; i < test.ar; i++ {
+						for j := 0; j < test.bc; j++ {
+							var v float32
+							for k := 0; k < test.ac; k++ {
+								v += a.At(i, k) * b.At(k, j)
+							}
+							want.Set(i, j, v)
+						}
+					}
+
+					var got Dense
+					got.Mul(a, b)
+					if !EqualApprox(&got, want, 1e-5) {
+						t.Errorf("unexpected result for %d×%d * %d×%d aTrans=%t bTrans=%t view=%t",
+							test.ar, test.ac, test.ac, test.bc, aTrans, bTrans, view)
+					}
+
+					// A strided receiver.
+					dst := NewDense(test.ar+1, test.bc+2, nil).Slice(1, test.ar+1, 1, test.bc+1).(*Dense)
+					dst.Mul(a, b)
+					if !EqualApprox(dst, want, 1e-5) {
+						t.Errorf("unexpected result for strided receiver %d×%d * %d×%d aTrans=%t bTrans=%t view=%t",
+							test.ar, test.ac, test.ac, test.bc, aTrans, bTrans, view)
+					}
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkMulMatrixVector(b *testing.B) {
+	a := NewDense(256, 256, nil)
+	randomSlice(a.mat.Data)
+	x := NewDense(256, 1, nil)
+	randomSlice(x.mat.Data)
+	var c Dense
+	c.Mul(a, x)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Mul(a, x)
+	}
+}
+
+func BenchmarkMulMatrixVectorGemm(b *testing.B) {
+	a := NewDense(256, 256, nil)
+	randomSlice(a.mat.Data)
+	x := NewDense(256, 1, nil)
+	randomSlice(x.mat.Data)
+	c := NewDense(256, 1, nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		blas32.Gemm(blas.NoTrans, blas.NoTrans, 1, a.mat, x.mat, 0, c.mat)
+	}
+}
+
+// naiveMul computes a * b with the unblocked triple loop.
+func naiveMul(a, b Matrix) *Dense {
+	ar, ac := a.Dims()
+	_, bc := b.Dims()
+	c := NewDense(ar, bc, nil)
+	for i := 0; i < ar; i++ {
+		for j := 0; j < bc; j++ {
+			var v float32
+			for k := 0; k < ac; k++ {
+				v += a.At(i, k) * b.At(k, j)
+			}
+			c.Set(i, j, v)
+		}
+	}
+	return c
+}
+
+func TestMulBlocked(t *testing.T) {
+	defer SetMulBlockSize(SetMulBlockSize(64))
+	for _, test := range []struct {
+		ar, ac, bc int
+	}{
+		{2, 2, 2},
+		{7, 5, 3},
+		{64, 64, 64},
+		{65, 129, 33},
+		{100, 3, 70},
+	} {
+		a := NewDense(test.ar, test.ac, nil)
+		randomSlice(a.mat.Data)
+		b := NewDense(test.ac, test.bc, nil)
+		randomSlice(b.mat.Data)
+		want := naiveMul(a, b)
+		for _, bs := range []int{1, 4, 16, 64, 1000} {
+			SetMulBlockSize(bs)
+			var got Dense
+			got.Mul(asBasicMatrix(a), asBasicMatrix(b))
+			if !Equal(&got, want) {
+				t.Errorf("unexpected result for %d×%d * %d×%d with block size %d",
+					test.ar, test.ac, test.ac, test.bc, bs)
+			}
+		}
+	}
+
+	if prev := SetMulBlockSize(32); prev != 1000 {
+		t.Errorf("unexpected previous block size: got: %d want: %d", prev, 1000)
+	}
+	for _, n := range []int{-1, 0, maxMulBlockSize + 1} {
+		panicked, message := panics(func() { SetMulBlockSize(n) })
+		if !panicked || message != ErrIndexOutOfRange.Error() {
+			t.Errorf("expected ErrIndexOutOfRange for block size %d: got: %q", n, message)
+		}
+	}
+}
+
+func TestGemm(t *testing.T) {
+	for _, test := range []struct {
+		m, k, n     int
+		alpha, beta float32
+	}{
+		{m: 1, k: 1, n: 1, alpha: 1, beta: 0},
+		{m: 3, k: 4, n: 5, alpha: 1, beta: 0},
+		{m: 5, k: 2, n: 3, alpha: -2, beta: 0},
+		{m: 4, k: 4, n: 4, alpha: 1, beta: 1},
+		{m: 2, k: 6, n: 3, alpha: 0.5, beta: -1.5},
+	} {
+		for _, transA := range []bool{false, true} {
+			for _, transB := range []bool{false, true} {
+				a := NewDense(test.m, test.k, nil)
+				if transA {
+					a = NewDense(test.k, test.m, nil)
+				}
+				randomSlice(a.mat.Data)
+				b := NewDense(test.k, test.n, nil)
+				if transB {
+					b = NewDense(test.n, test.k, nil)
+				}
+				randomSlice(b.mat.Data)
+				c0 := NewDense(test.m, test.n, nil)
+				randomSlice(c0.mat.Data)
+
+				var opA, opB Matrix = a, b
+				if transA {
+					opA = a.T()
+				}
+				if transB {
+					opB = b.T()
+				}
+				want := naiveMul(opA, opB)
+				want.Scale(test.alpha, want)
+				var bc Dense
+				bc.Scale(test.beta, c0)
+				want.Add(want, &bc)
+
+				for _, args := range []struct {
+					name string
+					a, b Matrix
+					tA   bool
+					tB   bool
+				}{
+					{name: "raw", a: a, b: b, tA: transA, tB: transB},
+					{name: "transposed operands", a: a.T(), b: b.T(), tA: !transA, tB: !transB},
+					{name: "non-raw", a: asBasicMatrix(a), b: asBasicMatrix(b), tA: transA, tB: transB},
+				} {
+					c := DenseCopyOf(c0)
+					Gemm(args.tA, args.tB, test.alpha, args.a, args.b, test.beta, c)
+					if !EqualApprox(c, want, 1e-5) {
+						t.Errorf("unexpected %s result for %d×%d×%d transA=%t transB=%t alpha=%v beta=%v:\ngot:\n%v\nwant:\n%v",
+							args.name, test.m, test.k, test.n, transA, transB, test.alpha, test.beta,
+							Formatted(c), Formatted(want))
+					}
+				}
+
+				// An empty c is treated as zero.
+				var c Dense
+				Gemm(transA, transB, test.alpha, a, b, test.beta, &c)
+				want = naiveMul(opA, opB)
+				want.Scale(test.alpha, want)
+				if !EqualApprox(&c, want, 1e-5) {
+					t.Errorf("unexpected result with empty c for %d×%d×%d transA=%t transB=%t:\ngot:\n%v\nwant:\n%v",
+						test.m, test.k, test.n, transA, transB, Formatted(&c), Formatted(want))
+				}
+			}
+		}
+	}
+
+	// Accumulating with beta one into the receiver operand.
+	a := NewDense(2, 2, []float32{1, 2, 3, 4})
+	Gemm(false, true, 1, a, a, 1, a)
+	want := NewDense(2, 2, []float32{6, 13, 14, 29})
+	if !Equal(a, want) {
+		t.Errorf("unexpected result accumulating into operand:\ngot:\n%v\nwant:\n%v", Formatted(a), Formatted(want))
+	}
+
+	for i, fn := range []func(){
+		func() { Gemm(false, false, 1, NewDense(2, 3, nil), NewDense(2, 3, nil), 0, &Dense{}) },
+		func() { Gemm(true, true, 1, NewDense(2, 3, nil), NewDense(2, 3, nil), 0, &Dense{}) },
+		func() { Gemm(false, true, 1, NewDense(2, 3, nil), NewDense(4, 3, nil), 0, NewDense(4, 2, nil)) },
+	} {
+		matched, message := panicsWith(fn, ErrShape)
+		if !matched {
+			t.Errorf("expected shape panic for test %d: got: %q", i, message)
+		}
+	}
+}
+
+func BenchmarkMulNaive512(b *testing.B) {
+	a := NewDense(512, 512, nil)
+	randomSlice(a.mat.Data)
+	c := NewDense(512, 512, nil)
+	randomSlice(c.mat.Data)
+	ab, cb := asBasicMatrix(a), asBasicMatrix(c)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		naiveMul(ab, cb)
+	}
+}
+
+func BenchmarkMulBlocked512(b *testing.B) {
+	a := NewDense(512, 512, nil)
+	randomSlice(a.mat.Data)
+	c := NewDense(512, 512, nil)
+	randomSlice(c.mat.Data)
+	ab, cb := asBasicMatrix(a), asBasicMatrix(c)
+	var dst Dense
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst.Mul(ab, cb)
+	}
+}
@@ -6,6 +6,7 @@ package mat32
 
 import (
 	"sync"
+	"sync/atomic"
 
 	"gonum.org/v1/gonum/blas"
 	"gonum.org/v1/gonum/blas/blas32"
@@ -58,30 +59,86 @@ var (
 	poolInts [63]sync.Pool
 )
 
+var (
+	// poolDisabled is non-zero when workspace pooling has been
+	// disabled by SetWorkspacePoolEnabled.
+	poolDisabled int32
+
+	// poolGets, poolPuts and poolMisses count workspace requests,
+	// workspaces returned to the pools and newly allocated
+	// workspaces respectively.
+	poolGets, poolPuts, poolMisses int64
+)
+
+// SetWorkspacePoolEnabled sets whether temporary workspaces used internally
+// by matrix operations are reused through the package's sync.Pools. When
+// disabled, every workspace is freshly allocated and released to the garbage
+// collector after use. Pooling is enabled by default.
+//
+// SetWorkspacePoolEnabled is safe to call concurrently with matrix operations.
+func SetWorkspacePoolEnabled(enabled bool) {
+	var v int32
+	if !enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&poolDisabled, v)
+}
+
+// WorkspacePoolStats returns the number of workspace requests made by matrix
+// operations, the number of workspaces returned to the pools and the number
+// of requests that required a new allocation.
+func WorkspacePoolStats() (gets, puts, misses int64) {
+	return atomic.LoadInt64(&poolGets), atomic.LoadInt64(&poolPuts), atomic.LoadInt64(&poolMisses)
+}
+
+// poolGet returns a value from p, or a newly allocated value if
+// pooling is disabled.
+func poolGet(p *sync.Pool) interface{} {
+	atomic.AddInt64(&poolGets, 1)
+	if atomic.LoadInt32(&poolDisabled) != 0 {
+		return p.New()
+	}
+	return p.Get()
+}
+
+// poolPut places x into p unless pooling is disabled.
+func poolPut(p *sync.Pool, x interface{}) {
+	if atomic.LoadInt32(&poolDisabled) != 0 {
+		return
+	}
+	atomic.AddInt64(&poolPuts, 1)
+	p.Put(x)
+}
+
 func init() {
 	for i := range pool {
 		l := 1 << uint(i)
 		pool[i].New = func() interface{} {
+			atomic.AddInt64(&poolMisses, 1)
 			return &Dense{mat: blas32.General{
 				Data: make([]float32, l),
 			}}
 		}
 
 		poolTri[i].New = func() interface{} {
+			atomic.AddInt64(&poolMisses, 1)
 			return &TriDense{mat: blas32.Triangular{
 				Data: make([]float32, l),
 			}}
 		}
 		poolVec[i].New = func() interface{} {
+			atomic.AddInt64(&poolMisses, 1)
 			return &VecDense{mat: blas32.Vector{
 				Inc:  1,
 				Data: make([]float32, l),
 			}}
 		}
 		poolFloats[i].New = func() interface{} {
+			atomic.AddInt64(&poolMisses, 1)
 			return make([]float32, l)
 		}
 		poolInts[i].New = func() interface{} {
+			atomic.AddInt64(&poolMisses, 1)
 			return make([]int, l)
 		}
 	}
@@ -92,7 +149,7 @@ func init() {
 // data slice visible through the Matrix interface is zeroed.
 func getWorkspace(r, c int, clear bool) *Dense {
 	l := uint64(r * c)
-	w := pool[bits(l)].Get().(*Dense)
+	w := poolGet(&pool[bits(l)]).(*Dense)
 	w.mat.Data = w.mat.Data[:l]
 	if clear {
 		zero(w.mat.Data)
@@ -109,7 +166,7 @@ func getWorkspace(r, c int, clear bool) *Dense {
 // workspace pool. putWorkspace must not be called with a matrix
 // where references to the underlying data slice have been kept.
 func putWorkspace(w *Dense) {
-	pool[bits(uint64(cap(w.mat.Data)))].Put(w)
+	poolPut(&pool[bits(uint64(cap(w.mat.Data)))], w)
 }
 
 // getWorkspaceTri returns a *TriDense of size n and a cap that
@@ -118,7 +175,7 @@ func putWorkspace(w *Dense) {
 func getWorkspaceTri(n int, kind TriKind, clear bool) *TriDense {
 	l := uint64(n)
 	l *= l
-	t := poolTri[bits(l)].Get().(*TriDense)
+	t := poolGet(&poolTri[bits(l)]).(*TriDense)
 	t.mat.Data = t.mat.Data[:l]
 	if clear {
 		zero(t.mat.Data)
@@ -141,7 +198,7 @@ func getWorkspaceTri(n int, kind TriKind, clear bool) *TriDense {
 // workspace pool. putWorkspaceTri must not be called with a matrix
 // where references to the underlying data slice have been kept.
 func putWorkspaceTri(t *TriDense) {
-	poolTri[bits(uint64(cap(t.mat.Data)))].Put(t)
+	poolPut(&poolTri[bits(uint64(cap(t.mat.Data)))], t)
 }
 
 // getWorkspaceVec returns a *VecDense of length n and a cap that
@@ -149,7 +206,7 @@ func putWorkspaceTri(t *TriDense) {
 // through the Matrix interface is zeroed.
 func getWorkspaceVec(n int, clear bool) *VecDense {
 	l := uint64(n)
-	v := poolVec[bits(l)].Get().(*VecDense)
+	v := poolGet(&poolVec[bits(l)]).(*VecDense)
 	v.mat.Data = v.mat.Data[:l]
 	if clear {
 		zero(v.mat.Data)
@@ -162,13 +219,13 @@ func getWorkspaceVec(n int, clear bool) *VecDense {
 // workspace pool. putWorkspaceVec must not be called with a matrix
 // where references to the underlying data slice have been kept.
 func putWorkspaceVec(v *VecDense) {
-	poolVec[bits(uint64(cap(v.mat.Data)))].Put(v)
+	poolPut(&poolVec[bits(uint64(cap(v.mat.Data)))], v)
 }
 
 // getFloats returns a []float64 of length l and a cap that is
 // less than 2*l. If clear is true, the slice visible is zeroed.
 func getFloats(l int, clear bool) []float32 {
-	w := poolFloats[bits(uint64(l))].Get().([]float32)
+	w := poolGet(&poolFloats[bits(uint64(l))]).([]float32)
 	w = w[:l]
 	if clear {
 		zero(w)
@@ -180,13 +237,13 @@ func getFloats(l int, clear bool) []float32 {
 // workspace pool. putFloats must not be called with a slice
 // where references to the underlying data have been kept.
 func putFloats(w []float32) {
-	poolFloats[bits(uint64(cap(w)))].Put(w)
+	poolPut(&poolFloats[bits(uint64(cap(w)))], w)
 }
 
 // getInts returns a []ints of length l and a cap that is
 // less than 2*l. If clear is true, the slice visible is zeroed.
 func getInts(l int, clear bool) []int {
-	w := poolInts[bits(uint64(l))].Get().([]int)
+	w := poolGet(&poolInts[bits(uint64(l))]).([]int)
 	w = w[:l]
 	if clear {
 		for i := range w {
@@ -200,5 +257,5 @@ func getInts(l int, clear bool) []int {
 // workspace pool. putInts must not be called with a slice
 // where references to the underlying data have been kept.
 func putInts(w []int) {
-	poolInts[bits(uint64(cap(w)))].Put(w)
+	poolPut(&poolInts[bits(uint64(cap(w)))], w)
 }
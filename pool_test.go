@@ -43,6 +43,39 @@ func TestPool(t *testing.T) {
 	}
 }
 
+func TestWorkspacePoolDisabled(t *testing.T) {
+	defer SetWorkspacePoolEnabled(true)
+
+	a := NewDense(3, 3, []float32{1, 2, 3, 4, 5, 6, 7, 8, 10})
+	var want Dense
+	want.Pow(a, 5)
+
+	for _, enabled := range []bool{false, true} {
+		SetWorkspacePoolEnabled(enabled)
+
+		gets, puts, misses := WorkspacePoolStats()
+		var got Dense
+		got.Pow(a, 5)
+		if !Equal(&got, &want) {
+			t.Errorf("unexpected result with pool enabled=%t: got: %v want: %v", enabled, Formatted(&got), Formatted(&want))
+		}
+		g, p, m := WorkspacePoolStats()
+		if g <= gets {
+			t.Errorf("unexpected workspace request count with pool enabled=%t: got: %d want: >%d", enabled, g, gets)
+		}
+		if !enabled {
+			if p != puts {
+				t.Errorf("unexpected workspace put with pool disabled: got: %d want: %d", p, puts)
+			}
+			if m-misses != g-gets {
+				t.Errorf("unexpected miss count with pool disabled: got: %d want: %d", m-misses, g-gets)
+			}
+		} else if p <= puts {
+			t.Errorf("unexpected workspace put count with pool enabled: got: %d want: >%d", p, puts)
+		}
+	}
+}
+
 var benchmat *Dense
 
 func poolBenchmark(n, r, c int, clear bool) {
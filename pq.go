@@ -0,0 +1,111 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"github.com/arjunsk/mat32/internal/asm/f32"
+	"golang.org/x/exp/rand"
+)
+
+const badPQ = "mat: product quantizer not trained"
+
+// ProductQuantizer compresses vectors by splitting them into equal-length
+// subvectors and encoding each subvector as the index of its nearest
+// centroid in a codebook learned for that subspace.
+type ProductQuantizer struct {
+	m, k int
+	dsub int
+
+	// codebooks holds the k×dsub centroids
+	// of each of the m subspaces.
+	codebooks []*Dense
+}
+
+// Train learns the codebooks of the quantizer from the rows of data. Each
+// row is split into m contiguous subvectors of length c/m, where c is the
+// number of columns of data, and the subvectors of each subspace are
+// clustered into k centroids by running k-means for at most iters
+// iterations. Training is deterministic for a given input.
+//
+// Train panics with ErrShape if m is not positive or does not divide the
+// number of columns of data, and with ErrIndexOutOfRange if k is not in
+// [1, 256] or exceeds the number of rows of data.
+func (pq *ProductQuantizer) Train(data *Dense, m, k int, iters int) {
+	r, c := data.Dims()
+	if m <= 0 || c%m != 0 {
+		panic(ErrShape)
+	}
+	if k < 1 || k > 256 || k > r {
+		panic(ErrIndexOutOfRange)
+	}
+	dsub := c / m
+
+	rnd := rand.New(rand.NewSource(1))
+	codebooks := make([]*Dense, m)
+	sub := NewDense(r, dsub, nil)
+	for s := range codebooks {
+		sub.Copy(data.Slice(0, r, s*dsub, (s+1)*dsub))
+		codebooks[s], _ = lloyd(sub, k, iters, rnd)
+	}
+
+	pq.m = m
+	pq.k = k
+	pq.dsub = dsub
+	pq.codebooks = codebooks
+}
+
+// Encode returns the m centroid indices that encode v. Encode panics if
+// the quantizer has not been trained, and with ErrShape if the length of
+// v does not match the training data.
+func (pq *ProductQuantizer) Encode(v Vector) []uint8 {
+	if pq.codebooks == nil {
+		panic(badPQ)
+	}
+	if v.Len() != pq.m*pq.dsub {
+		panic(ShapeError{Got: [2]int{v.Len(), 1}, Want: [2]int{pq.m * pq.dsub, 1}})
+	}
+	x := make([]float32, pq.dsub)
+	codes := make([]uint8, pq.m)
+	for s, cb := range pq.codebooks {
+		for i := range x {
+			x[i] = v.AtVec(s*pq.dsub + i)
+		}
+		best := 0
+		bestDist := f32.L2DistSqUnitary(x, cb.RawRowView(0))
+		for j := 1; j < pq.k; j++ {
+			if d := f32.L2DistSqUnitary(x, cb.RawRowView(j)); d < bestDist {
+				best, bestDist = j, d
+			}
+		}
+		codes[s] = uint8(best)
+	}
+	return codes
+}
+
+// Decode places the reconstruction of the vector encoded by codes into
+// dst. If dst is empty it is resized to the length of the training
+// vectors, otherwise it must have that length.
+//
+// Decode panics if the quantizer has not been trained, with ErrShape if
+// len(codes) does not match the number of subspaces, and with
+// ErrIndexOutOfRange if a code does not index a centroid.
+func (pq *ProductQuantizer) Decode(codes []uint8, dst *VecDense) {
+	if pq.codebooks == nil {
+		panic(badPQ)
+	}
+	if len(codes) != pq.m {
+		panic(ShapeError{Got: [2]int{len(codes), 1}, Want: [2]int{pq.m, 1}})
+	}
+	dst.reuseAs(pq.m * pq.dsub)
+	for s, code := range codes {
+		if int(code) >= pq.k {
+			panic(ErrIndexOutOfRange)
+		}
+		centroid := pq.codebooks[s].RawRowView(int(code))
+		for i, v := range centroid {
+			dst.setVec(s*pq.dsub+i, v)
+		}
+	}
+}
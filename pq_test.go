@@ -0,0 +1,86 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestProductQuantizer(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	const (
+		rows = 500
+		cols = 16
+		m    = 4
+	)
+	data := randNormDense(rows, cols, rnd)
+
+	prev := float32(-1)
+	for _, k := range []int{1, 4, 16, 64} {
+		var pq ProductQuantizer
+		pq.Train(data, m, k, 20)
+
+		var err float32
+		var dst VecDense
+		for i := 0; i < rows; i++ {
+			v := data.RowView(i)
+			codes := pq.Encode(v)
+			if len(codes) != m {
+				t.Fatalf("unexpected code length for k=%d: got: %d want: %d", k, len(codes), m)
+			}
+			for _, c := range codes {
+				if int(c) >= k {
+					t.Fatalf("code out of range for k=%d: %d", k, c)
+				}
+			}
+			pq.Decode(codes, &dst)
+			err += SquaredL2Distance(v, &dst)
+		}
+		if prev >= 0 && err >= prev {
+			t.Errorf("reconstruction error did not decrease for k=%d: got: %v previous: %v", k, err, prev)
+		}
+		prev = err
+	}
+
+	// Data with exactly k distinct subvectors is reconstructed exactly.
+	exact := NewDense(6, 4, []float32{
+		1, 1, 5, 5,
+		2, 2, 6, 6,
+		1, 1, 6, 6,
+		2, 2, 5, 5,
+		1, 1, 5, 5,
+		2, 2, 6, 6,
+	})
+	var pq ProductQuantizer
+	pq.Train(exact, 2, 2, 10)
+	for i := 0; i < 6; i++ {
+		var dst VecDense
+		pq.Decode(pq.Encode(exact.RowView(i)), &dst)
+		if !EqualApprox(&dst, exact.RowView(i), 1e-6) {
+			t.Errorf("unexpected reconstruction of row %d: got: %v want: %v", i, dst.RawVector().Data, exact.RawRowView(i))
+		}
+	}
+
+	for _, test := range []struct {
+		name  string
+		fn    func()
+		panic string
+	}{
+		{name: "untrained", fn: func() { var pq ProductQuantizer; pq.Encode(NewVecDense(4, nil)) }, panic: badPQ},
+		{name: "m does not divide", fn: func() { var pq ProductQuantizer; pq.Train(exact, 3, 2, 1) }, panic: ErrShape.Error()},
+		{name: "k too large", fn: func() { var pq ProductQuantizer; pq.Train(exact, 2, 7, 1) }, panic: ErrIndexOutOfRange.Error()},
+	} {
+		panicked, message := panics(test.fn)
+		if !panicked || message != test.panic {
+			t.Errorf("expected %q panic for %s: got: %q", test.panic, test.name, message)
+		}
+	}
+	matched, message := panicsWith(func() { pq.Encode(NewVecDense(3, nil)) }, ErrShape)
+	if !matched {
+		t.Errorf("expected ErrShape panic for encode length: got: %q", message)
+	}
+}
@@ -27,7 +27,7 @@ func (m *Dense) Product(factors ...Matrix) {
 	switch len(factors) {
 	case 0:
 		if r != 0 || c != 0 {
-			panic(ErrShape)
+			panic(ShapeError{Got: [2]int{r, c}, Want: [2]int{0, 0}})
 		}
 		return
 	case 1:
@@ -73,10 +73,10 @@ func newMultiplier(m *Dense, factors []Matrix) *multiplier {
 	fr, fc := factors[0].Dims() // newMultiplier is only called with len(factors) > 2.
 	if !m.IsZero() {
 		if fr != r {
-			panic(ErrShape)
+			panic(ShapeError{Got: [2]int{fr, fc}, Want: [2]int{r, fc}})
 		}
-		if _, lc := factors[len(factors)-1].Dims(); lc != c {
-			panic(ErrShape)
+		if lr, lc := factors[len(factors)-1].Dims(); lc != c {
+			panic(ShapeError{Got: [2]int{lr, lc}, Want: [2]int{lr, c}})
 		}
 	}
 
@@ -88,7 +88,7 @@ func newMultiplier(m *Dense, factors []Matrix) *multiplier {
 		cr, cc := f.Dims()
 		dims[i+1] = cr
 		if pc != cr {
-			panic(ErrShape)
+			panic(ShapeError{Got: [2]int{cr, cc}, Want: [2]int{pc, cc}})
 		}
 		pc = cc
 	}
@@ -0,0 +1,166 @@
+// Copyright ©2013 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"github.com/arjunsk/mat32/internal/asm/f32"
+	"github.com/chewxy/math32"
+
+	"gonum.org/v1/gonum/blas/blas32"
+)
+
+// QR is a type for creating and using the QR factorization of a matrix.
+type QR struct {
+	qr  *Dense
+	tau []float32
+	piv []int
+}
+
+// Factorize computes the QR factorization of an m×n matrix a where m >= n.
+// The QR factorization always exists even if A is singular.
+//
+// The QR decomposition is a factorization of the matrix A such that A = Q * R.
+// The matrix Q is an orthonormal m×m matrix, and R is an m×n upper triangular
+// matrix. Q and R can be extracted using the QTo and RTo methods.
+func (qr *QR) Factorize(a Matrix) {
+	qr.factorize(a, false)
+}
+
+// FactorizePivoted computes the QR factorization with column pivoting of an
+// m×n matrix a where m >= n, such that
+//  A * P = Q * R
+// where P is a permutation matrix. At each step the remaining column with the
+// largest norm, after projection onto the complement of the columns already
+// chosen, is moved to the front, so the magnitudes of the diagonal elements
+// of R are non-increasing. A column that is nearly linearly dependent on
+// earlier columns is therefore moved to the end, and the numerical rank of A
+// can be read reliably from the diagonal of R.
+//
+// FactorizePivoted returns the column permutation piv, where column j of
+// A * P is column piv[j] of A. The returned slice is owned by the caller.
+func (qr *QR) FactorizePivoted(a Matrix) (piv []int) {
+	qr.factorize(a, true)
+	piv = make([]int, len(qr.piv))
+	copy(piv, qr.piv)
+	return piv
+}
+
+func (qr *QR) factorize(a Matrix, pivot bool) {
+	m, n := a.Dims()
+	if m < n {
+		panic(ErrShape)
+	}
+	if qr.qr == nil {
+		qr.qr = &Dense{}
+	}
+	qr.qr.Clone(a)
+	qr.tau = use(qr.tau, n)
+	qr.piv = useInt(qr.piv, n)
+	for j := range qr.piv {
+		qr.piv[j] = j
+	}
+	geqp(qr.qr.mat, qr.tau, qr.piv, pivot)
+}
+
+// geqp computes the QR factorization of the m×n matrix a in place using
+// Householder reflections. The reflector vectors are stored below the
+// diagonal of a with an implicit unit leading element and their scalar
+// factors are stored in tau, while R is stored on and above the diagonal.
+// If pivot is true, columns are interchanged so that the column with the
+// largest remaining norm is eliminated at each step, and the interchanges
+// are recorded in piv.
+func geqp(a blas32.General, tau []float32, piv []int, pivot bool) {
+	m, n := a.Rows, a.Cols
+	norms := getFloats(n, false)
+	defer putFloats(norms)
+	for k := 0; k < n; k++ {
+		if pivot {
+			for j := k; j < n; j++ {
+				norms[j] = blas32.Nrm2(m-k, blas32.Vector{Inc: a.Stride, Data: a.Data[k*a.Stride+j:]})
+			}
+			p := k + blas32.Iamax(n-k, blas32.Vector{Inc: 1, Data: norms[k:n]})
+			if p != k {
+				blas32.Swap(m,
+					blas32.Vector{Inc: a.Stride, Data: a.Data[k:]},
+					blas32.Vector{Inc: a.Stride, Data: a.Data[p:]})
+				piv[k], piv[p] = piv[p], piv[k]
+			}
+		}
+
+		// Generate the reflector H_k that annihilates a[k+1:m, k].
+		alpha := a.Data[k*a.Stride+k]
+		var xnorm float32
+		if k < m-1 {
+			xnorm = blas32.Nrm2(m-k-1, blas32.Vector{Inc: a.Stride, Data: a.Data[(k+1)*a.Stride+k:]})
+		}
+		if xnorm == 0 {
+			tau[k] = 0
+			continue
+		}
+		beta := -math32.Copysign(math32.Hypot(alpha, xnorm), alpha)
+		tau[k] = (beta - alpha) / beta
+		f32.ScalInc(1/(alpha-beta), a.Data[(k+1)*a.Stride+k:], uintptr(m-k-1), uintptr(a.Stride))
+		a.Data[k*a.Stride+k] = beta
+
+		// Apply H_k to the trailing columns.
+		for j := k + 1; j < n; j++ {
+			applyReflector(a, k, tau[k], a.Data[j:], a.Stride)
+		}
+	}
+}
+
+// applyReflector applies the Householder reflector stored in column k of a,
+// below the diagonal, with scalar factor tau to rows k through m-1 of the
+// column vector x with stride inc.
+func applyReflector(a blas32.General, k int, tau float32, x []float32, inc int) {
+	if tau == 0 {
+		return
+	}
+	m := a.Rows
+	s := x[k*inc]
+	if k < m-1 {
+		s += f32.DotInc(a.Data[(k+1)*a.Stride+k:], x[(k+1)*inc:], uintptr(m-k-1), uintptr(a.Stride), uintptr(inc), 0, 0)
+	}
+	s *= tau
+	x[k*inc] -= s
+	if k < m-1 {
+		f32.AxpyInc(-s, a.Data[(k+1)*a.Stride+k:], x[(k+1)*inc:], uintptr(m-k-1), uintptr(a.Stride), uintptr(inc), 0, 0)
+	}
+}
+
+// RTo extracts the m×n upper trapezoidal matrix R from a QR decomposition.
+// If dst is empty, RTo will resize dst to be m×n. When dst is non-empty,
+// RTo will panic if dst is not m×n.
+func (qr *QR) RTo(dst *Dense) {
+	if qr.qr == nil || qr.qr.IsZero() {
+		panic(badFact)
+	}
+	r, c := qr.qr.Dims()
+	dst.reuseAsZeroed(r, c)
+	for i := 0; i < c; i++ {
+		copy(dst.mat.Data[i*dst.mat.Stride+i:i*dst.mat.Stride+c], qr.qr.mat.Data[i*qr.qr.mat.Stride+i:i*qr.qr.mat.Stride+c])
+	}
+}
+
+// QTo extracts the m×m orthonormal matrix Q from a QR decomposition.
+// If dst is empty, QTo will resize dst to be m×m. When dst is non-empty,
+// QTo will panic if dst is not m×m.
+func (qr *QR) QTo(dst *Dense) {
+	if qr.qr == nil || qr.qr.IsZero() {
+		panic(badFact)
+	}
+	r, c := qr.qr.Dims()
+	dst.reuseAsZeroed(r, r)
+	for i := 0; i < r; i++ {
+		dst.mat.Data[i*dst.mat.Stride+i] = 1
+	}
+	// Q = H_0 * H_1 * ... * H_{c-1}, so the reflectors
+	// are applied to the identity in reverse order.
+	for k := c - 1; k >= 0; k-- {
+		for j := 0; j < r; j++ {
+			applyReflector(qr.qr.mat, k, qr.tau[k], dst.mat.Data[j:], dst.mat.Stride)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright ©2013 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"testing"
+
+	"github.com/chewxy/math32"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestQR(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n int
+	}{
+		{1, 1},
+		{3, 3},
+		{5, 3},
+		{10, 10},
+		{20, 8},
+	} {
+		m, n := test.m, test.n
+		a := randNormDense(m, n, rnd)
+		var qr QR
+		qr.Factorize(a)
+		var q, r Dense
+		qr.QTo(&q)
+		qr.RTo(&r)
+
+		var qtq Dense
+		qtq.Mul(q.T(), &q)
+		if !EqualApprox(&qtq, eye(m), 1e-5) {
+			t.Errorf("Q is not orthonormal for %d×%d", m, n)
+		}
+		for i := 0; i < m; i++ {
+			for j := 0; j < min(i, n); j++ {
+				if r.At(i, j) != 0 {
+					t.Errorf("R is not upper triangular for %d×%d at (%d, %d)", m, n, i, j)
+				}
+			}
+		}
+		var got Dense
+		got.Mul(&q, &r)
+		if !EqualApprox(&got, a, 1e-5) {
+			t.Errorf("unexpected Q * R for %d×%d", m, n)
+		}
+	}
+
+	matched, message := panicsWith(func() {
+		var qr QR
+		qr.Factorize(NewDense(2, 3, nil))
+	}, ErrShape)
+	if !matched {
+		t.Errorf("expected shape panic for wide matrix: got: %q", message)
+	}
+}
+
+func TestQRPivoted(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i, test := range []struct {
+		a    *Dense
+		rank int
+		last int
+	}{
+		{
+			// Column 1 is twice column 0, so column 0 has zero norm
+			// after column 1 has been eliminated.
+			a: NewDense(4, 3, []float32{
+				1, 2, 0,
+				2, 4, 1,
+				0, 0, 3,
+				1, 2, -1,
+			}),
+			rank: 2,
+			last: 0,
+		},
+		{
+			// Column 2 is the sum of columns 0 and 1, so the
+			// short column 0 is left with zero norm.
+			a: NewDense(3, 3, []float32{
+				1, 4, 5,
+				0, 5, 5,
+				1, 6, 7,
+			}),
+			rank: 2,
+			last: 0,
+		},
+		{
+			a:    randNormDense(8, 5, rnd),
+			rank: 5,
+			last: -1,
+		},
+	} {
+		m, n := test.a.Dims()
+		var qr QR
+		piv := qr.FactorizePivoted(test.a)
+		if len(piv) != n {
+			t.Errorf("unexpected pivot length for test %d: got: %d want: %d", i, len(piv), n)
+			continue
+		}
+		if test.last >= 0 && piv[n-1] != test.last {
+			t.Errorf("unexpected last pivot for test %d: got: %v want last: %d", i, piv, test.last)
+		}
+
+		var q, r Dense
+		qr.QTo(&q)
+		qr.RTo(&r)
+		for k := 1; k < n; k++ {
+			if math32.Abs(r.At(k, k)) > math32.Abs(r.At(k-1, k-1))*(1+1e-5) {
+				t.Errorf("diagonal of R not non-increasing for test %d: %v", i, Formatted(&r))
+				break
+			}
+		}
+		var rank int
+		tol := 1e-5 * math32.Abs(r.At(0, 0))
+		for k := 0; k < n; k++ {
+			if math32.Abs(r.At(k, k)) > tol {
+				rank++
+			}
+		}
+		if rank != test.rank {
+			t.Errorf("unexpected rank for test %d: got: %d want: %d", i, rank, test.rank)
+		}
+
+		// Q * R must equal A with its columns permuted.
+		ap := NewDense(m, n, nil)
+		for j, p := range piv {
+			for k := 0; k < m; k++ {
+				ap.Set(k, j, test.a.At(k, p))
+			}
+		}
+		var got Dense
+		got.Mul(&q, &r)
+		if !EqualApprox(&got, ap, 1e-5) {
+			t.Errorf("unexpected Q * R for test %d\ngot:\n%v\nwant:\n%v", i, Formatted(&got), Formatted(ap))
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"math"
+
+	"github.com/chewxy/math32"
+	"gonum.org/v1/gonum/blas/blas32"
+)
+
+// QuantizeInt8 returns the symmetric scalar quantization of v to int8 codes
+// and the scale that maps codes back to values,
+//  v_i ≈ float32(codes[i]) * scale
+// The scale is chosen as max_i |v_i| / 127, and each element is rounded to
+// the nearest code with ties to even and clamped to [-127, 127], so the
+// reconstruction error of each element is at most scale/2. If v is all
+// zeros, scale is zero and all codes are zero.
+func QuantizeInt8(v Vector) (codes []int8, scale float32) {
+	n := v.Len()
+	codes = make([]int8, n)
+	var maxAbs float32
+	for i := 0; i < n; i++ {
+		maxAbs = math32.Max(maxAbs, math32.Abs(v.AtVec(i)))
+	}
+	if maxAbs == 0 {
+		return codes, 0
+	}
+	scale = maxAbs / 127
+	inv := 1 / scale
+	for i := range codes {
+		codes[i] = roundInt8(v.AtVec(i) * inv)
+	}
+	return codes, scale
+}
+
+// roundInt8 returns x rounded to the nearest integer with ties to even and
+// clamped to [-127, 127].
+func roundInt8(x float32) int8 {
+	q := math.RoundToEven(float64(x))
+	if q > 127 {
+		q = 127
+	} else if q < -127 {
+		q = -127
+	}
+	return int8(q)
+}
+
+// NormalizeAndQuantize returns the int8 quantization, as by QuantizeInt8, of
+// each row of db after scaling it to unit Euclidean norm, together with the
+// per-row scales, so that
+//  db[i,j] / ‖db[i,:]‖ ≈ float32(codes[i][j]) * scales[i]
+// Normalization is folded into the scale rather than applied to the data,
+// so db is not modified and no normalized copy is formed. The codes share
+// a single backing slice. Rows with zero norm have zero codes and a zero
+// scale.
+func NormalizeAndQuantize(db *Dense) (codes [][]int8, scales []float32) {
+	r, c := db.Dims()
+	codes = make([][]int8, r)
+	scales = make([]float32, r)
+	data := make([]int8, r*c)
+	for i := range codes {
+		codes[i] = data[i*c : i*c+c : i*c+c]
+		row := db.mat.Data[i*db.mat.Stride : i*db.mat.Stride+c]
+		norm := blas32.Nrm2(c, blas32.Vector{Inc: 1, Data: row})
+		if norm == 0 {
+			continue
+		}
+		var maxAbs float32
+		for _, v := range row {
+			maxAbs = math32.Max(maxAbs, math32.Abs(v))
+		}
+		// Quantizing row/norm with scale maxAbs/(norm*127)
+		// gives the same codes as quantizing row with scale
+		// maxAbs/127.
+		inv := 127 / maxAbs
+		for j, v := range row {
+			codes[i][j] = roundInt8(v * inv)
+		}
+		scales[i] = maxAbs / norm / 127
+	}
+	return codes, scales
+}
+
+// DequantizeInt8 places the values represented by the int8 codes and scale
+// returned by QuantizeInt8 into dst,
+//  dst_i = float32(codes[i]) * scale
+// If dst is empty it is resized to len(codes), otherwise it must have
+// length len(codes).
+func DequantizeInt8(codes []int8, scale float32, dst *VecDense) {
+	dst.reuseAs(len(codes))
+	for i, c := range codes {
+		dst.setVec(i, float32(c)*scale)
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"testing"
+
+	"github.com/chewxy/math32"
+	"golang.org/x/exp/rand"
+)
+
+func TestQuantizeInt8(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i, test := range []struct {
+		v     []float32
+		codes []int8
+		scale float32
+	}{
+		{
+			v:     []float32{0, 0, 0},
+			codes: []int8{0, 0, 0},
+			scale: 0,
+		},
+		{
+			v:     []float32{127, -127, 0.5, 1.5, -2.5},
+			codes: []int8{127, -127, 0, 2, -2},
+			scale: 1,
+		},
+		{
+			v:     []float32{-2.54, 1.27, 0.01},
+			codes: []int8{-127, 64, 0},
+			scale: 0.02,
+		},
+		{v: randSlice(64, rnd)},
+		{v: randSlice(1000, rnd)},
+	} {
+		v := NewVecDense(len(test.v), test.v)
+		codes, scale := QuantizeInt8(v)
+		if test.codes != nil {
+			if !EqualWithinAbsOrRel(scale, test.scale, 1e-6, 1e-6) {
+				t.Errorf("unexpected scale for test %d: got: %v want: %v", i, scale, test.scale)
+			}
+			for j, c := range codes {
+				if c != test.codes[j] {
+					t.Errorf("unexpected codes for test %d: got: %v want: %v", i, codes, test.codes)
+					break
+				}
+			}
+		}
+		for j, c := range codes {
+			if c < -127 {
+				t.Errorf("code out of range for test %d at %d: %d", i, j, c)
+			}
+		}
+
+		var dst VecDense
+		DequantizeInt8(codes, scale, &dst)
+		bound := scale/2 + 1e-6*math32.Abs(scale)
+		for j, want := range test.v {
+			if d := math32.Abs(dst.AtVec(j) - want); d > bound {
+				t.Errorf("round-trip error too large for test %d at %d: got: %v want: <= %v", i, j, d, bound)
+				break
+			}
+		}
+	}
+}
+
+func TestNormalizeAndQuantize(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	const r, c = 20, 64
+	db := randNormDense(r, c, rnd)
+	// Give the rows a range of norms and include a zero row.
+	for i := 0; i < r; i++ {
+		f := math32.Pow(10, float32(i%5-2))
+		for j := 0; j < c; j++ {
+			db.Set(i, j, f*db.At(i, j))
+		}
+	}
+	for j := 0; j < c; j++ {
+		db.Set(3, j, 0)
+	}
+	orig := DenseCopyOf(db)
+
+	codes, scales := NormalizeAndQuantize(db)
+	if !Equal(db, orig) {
+		t.Errorf("NormalizeAndQuantize modified its input")
+	}
+	if len(codes) != r || len(scales) != r {
+		t.Fatalf("unexpected result lengths: got: %d and %d want: %d", len(codes), len(scales), r)
+	}
+
+	// Dequantized rows approximate the normalized rows.
+	normalized := DenseCopyOf(db)
+	normalized.NormalizeRowsL2()
+	deq := NewDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		if len(codes[i]) != c {
+			t.Fatalf("unexpected code length for row %d: got: %d want: %d", i, len(codes[i]), c)
+		}
+		var v VecDense
+		DequantizeInt8(codes[i], scales[i], &v)
+		deq.SetRow(i, v.RawVector().Data)
+
+		bound := scales[i]/2 + 1e-6
+		for j := 0; j < c; j++ {
+			if d := math32.Abs(deq.At(i, j) - normalized.At(i, j)); d > bound {
+				t.Errorf("dequantization error too large for row %d at %d: got: %v want: <= %v", i, j, d, bound)
+				break
+			}
+		}
+
+		// The codes match quantizing the normalized row directly.
+		want, _ := QuantizeInt8(normalized.RowView(i))
+		for j := range want {
+			if d := int(codes[i][j]) - int(want[j]); d < -1 || d > 1 {
+				t.Errorf("codes differ from QuantizeInt8 of normalized row %d at %d: got: %d want: %d", i, j, codes[i][j], want[j])
+				break
+			}
+		}
+	}
+	if scales[3] != 0 {
+		t.Errorf("unexpected scale for zero row: got: %v want: 0", scales[3])
+	}
+
+	// Cosine similarities from the dequantized rows are close to
+	// those computed in float32.
+	var got, want Dense
+	got.Mul(deq, deq.T())
+	want.Mul(normalized, normalized.T())
+	for i := 0; i < r; i++ {
+		for j := 0; j < r; j++ {
+			if d := math32.Abs(got.At(i, j) - want.At(i, j)); d > 0.02 {
+				t.Errorf("cosine similarity of rows %d and %d differs: got: %v want: %v", i, j, got.At(i, j), want.At(i, j))
+			}
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"container/heap"
+
+	"github.com/chewxy/math32"
+
+	"gonum.org/v1/gonum/blas/blas32"
+)
+
+// TopK returns the indices of the k rows of db closest to query under the
+// given metric, along with their distances, ordered by increasing distance.
+// Rows at equal distance are ordered by increasing index, and rows at a NaN
+// distance are ordered after all others.
+//
+// TopK keeps the k best candidates in a bounded max-heap, so it takes
+// O(r log k) time for r rows rather than sorting all r distances.
+//
+// TopK panics with ErrShape if the length of query does not match the
+// number of columns of db, with ErrIndexOutOfRange if k is negative or
+// greater than the number of rows of db, and with ErrMetric if metric is
+// not a known Metric.
+func TopK(query Vector, db *Dense, k int, metric Metric) (indices []int, dists []float32) {
+	r, c := db.Dims()
+	if query.Len() != c {
+		panic(ShapeError{Got: [2]int{query.Len(), 1}, Want: [2]int{c, 1}})
+	}
+	if k < 0 || k > r {
+		panic(ErrIndexOutOfRange)
+	}
+	acc := NewTopKAccumulator(query, k, metric)
+
+	// Reuse a single view over the rows of db.
+	row := &VecDense{
+		mat: blas32.Vector{Inc: 1},
+		n:   c,
+	}
+	for i := 0; i < r; i++ {
+		row.mat.Data = db.rawRowView(i)
+		acc.Push(i, row)
+	}
+	return acc.Result()
+}
+
+// TopKAccumulator maintains the k vectors closest to a query over a stream
+// of candidates, so that nearest-neighbor search can be performed without
+// holding all candidates in memory. The retained candidates do not depend
+// on the order in which they are pushed.
+type TopKAccumulator struct {
+	dist *DistanceComputer
+	k    int
+	heap neighborHeap
+}
+
+// NewTopKAccumulator returns a TopKAccumulator that retains the k candidates
+// closest to query under the given metric. NewTopKAccumulator panics with
+// ErrIndexOutOfRange if k is negative and with ErrMetric if metric is not a
+// known Metric.
+func NewTopKAccumulator(query Vector, k int, metric Metric) *TopKAccumulator {
+	if k < 0 {
+		panic(ErrIndexOutOfRange)
+	}
+	return &TopKAccumulator{
+		dist: NewDistanceComputer(query, metric),
+		k:    k,
+		heap: make(neighborHeap, 0, k),
+	}
+}
+
+// Push offers the candidate v, identified by index, to the accumulator.
+// The vector v is not retained. Push panics with ErrShape if the length of
+// v does not match the length of the query.
+func (t *TopKAccumulator) Push(index int, v Vector) {
+	t.push(neighbor{index: index, dist: t.dist.To(v)})
+}
+
+func (t *TopKAccumulator) push(n neighbor) {
+	switch {
+	case t.k == 0:
+	case len(t.heap) < t.k:
+		heap.Push(&t.heap, n)
+	case n.closer(t.heap[0]):
+		t.heap[0] = n
+		heap.Fix(&t.heap, 0)
+	}
+}
+
+// Result returns the indices and distances of the retained candidates,
+// ordered by increasing distance and then by increasing index. Fewer
+// than k results are returned if fewer than k candidates have been pushed.
+// The accumulator is not modified and may continue to receive candidates.
+func (t *TopKAccumulator) Result() (indices []int, dists []float32) {
+	h := make(neighborHeap, len(t.heap))
+	copy(h, t.heap)
+	indices = make([]int, len(h))
+	dists = make([]float32, len(h))
+	for i := len(h) - 1; i >= 0; i-- {
+		n := heap.Pop(&h).(neighbor)
+		indices[i] = n.index
+		dists[i] = n.dist
+	}
+	return indices, dists
+}
+
+// neighbor is a candidate result of a nearest-neighbor search.
+type neighbor struct {
+	index int
+	dist  float32
+}
+
+// closer returns whether n is a better candidate than m. A NaN distance
+// is farther than any number, so that the ordering remains total.
+func (n neighbor) closer(m neighbor) bool {
+	nNaN, mNaN := math32.IsNaN(n.dist), math32.IsNaN(m.dist)
+	switch {
+	case nNaN != mNaN:
+		return mNaN
+	case !nNaN && n.dist != m.dist:
+		return n.dist < m.dist
+	}
+	return n.index < m.index
+}
+
+// neighborHeap is a max-heap of neighbors ordered by distance and then
+// by index, so the root is the worst candidate retained.
+type neighborHeap []neighbor
+
+func (h neighborHeap) Len() int { return len(h) }
+func (h neighborHeap) Less(i, j int) bool {
+	return h[j].closer(h[i])
+}
+func (h neighborHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *neighborHeap) Push(x interface{}) { *h = append(*h, x.(neighbor)) }
+func (h *neighborHeap) Pop() interface{} {
+	old := *h
+	n := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return n
+}
@@ -0,0 +1,178 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/chewxy/math32"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestTopK(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i, test := range []struct {
+		rows, cols, k int
+	}{
+		{rows: 1, cols: 1, k: 1},
+		{rows: 10, cols: 3, k: 0},
+		{rows: 10, cols: 3, k: 1},
+		{rows: 10, cols: 3, k: 10},
+		{rows: 100, cols: 8, k: 5},
+		{rows: 257, cols: 16, k: 31},
+	} {
+		db := randNormDense(test.rows, test.cols, rnd)
+		query := NewVecDense(test.cols, randSlice(test.cols, rnd))
+		for _, metric := range []Metric{L2, SquaredL2, L1, Cosine, InnerProduct, Chebyshev} {
+			gotIdx, gotDist := TopK(query, db, test.k, metric)
+
+			// Brute force by sorting all distances.
+			all := make([]neighbor, test.rows)
+			for j := range all {
+				d, _ := Distance(metric, query, db.RowView(j))
+				all[j] = neighbor{index: j, dist: d}
+			}
+			sort.SliceStable(all, func(a, b int) bool { return all[a].dist < all[b].dist })
+
+			if len(gotIdx) != test.k || len(gotDist) != test.k {
+				t.Errorf("unexpected result length for test %d metric %d: got: %d,%d want: %d", i, metric, len(gotIdx), len(gotDist), test.k)
+				continue
+			}
+			for j := 0; j < test.k; j++ {
+				if gotIdx[j] != all[j].index || gotDist[j] != all[j].dist {
+					t.Errorf("unexpected neighbor %d for test %d metric %d: got: (%d, %v) want: (%d, %v)",
+						j, i, metric, gotIdx[j], gotDist[j], all[j].index, all[j].dist)
+					break
+				}
+			}
+		}
+	}
+
+	// Ties are broken by row index.
+	db := NewDense(4, 1, []float32{1, 0, 1, 0})
+	idx, _ := TopK(NewVecDense(1, []float32{0}), db, 3, L1)
+	if want := []int{1, 3, 0}; !equalInts(idx, want) {
+		t.Errorf("unexpected tie order: got: %v want: %v", idx, want)
+	}
+
+	// Rows at a NaN distance are farther than any other row, wherever
+	// they appear in db.
+	for _, test := range []struct {
+		data    []float32
+		k       int
+		wantIdx []int
+	}{
+		{data: []float32{math32.NaN(), 5, 1, 2}, k: 2, wantIdx: []int{2, 3}},
+		{data: []float32{5, 1, 2, math32.NaN()}, k: 2, wantIdx: []int{1, 2}},
+		{data: []float32{math32.NaN(), 5, math32.NaN(), 1}, k: 3, wantIdx: []int{3, 1, 0}},
+		{data: []float32{math32.NaN(), math32.NaN(), 3}, k: 3, wantIdx: []int{2, 0, 1}},
+	} {
+		db := NewDense(len(test.data), 1, test.data)
+		idx, dist := TopK(NewVecDense(1, []float32{0}), db, test.k, L2)
+		if !equalInts(idx, test.wantIdx) {
+			t.Errorf("unexpected NaN order for %v: got: %v want: %v", test.data, idx, test.wantIdx)
+		}
+		for j, d := range dist {
+			if math32.IsNaN(d) != math32.IsNaN(test.data[test.wantIdx[j]]) {
+				t.Errorf("unexpected distances for %v: got: %v", test.data, dist)
+				break
+			}
+		}
+	}
+
+	for _, test := range []struct {
+		name  string
+		fn    func()
+		panic error
+	}{
+		{name: "k too large", fn: func() { TopK(NewVecDense(2, nil), NewDense(3, 2, nil), 4, L2) }, panic: ErrIndexOutOfRange},
+		{name: "negative k", fn: func() { TopK(NewVecDense(2, nil), NewDense(3, 2, nil), -1, L2) }, panic: ErrIndexOutOfRange},
+		{name: "query length", fn: func() { TopK(NewVecDense(3, nil), NewDense(3, 2, nil), 1, L2) }, panic: ErrShape},
+		{name: "metric", fn: func() { TopK(NewVecDense(2, nil), NewDense(3, 2, nil), 1, 0) }, panic: ErrMetric},
+	} {
+		matched, message := panicsWith(test.fn, test.panic)
+		if !matched {
+			t.Errorf("expected %v panic for %s: got: %q", test.panic, test.name, message)
+		}
+	}
+}
+
+func TestTopKAccumulator(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i, test := range []struct {
+		rows, cols, k int
+	}{
+		{rows: 1, cols: 2, k: 1},
+		{rows: 20, cols: 4, k: 0},
+		{rows: 20, cols: 4, k: 3},
+		{rows: 50, cols: 8, k: 50},
+		{rows: 200, cols: 16, k: 17},
+	} {
+		db := randNormDense(test.rows, test.cols, rnd)
+		// Duplicate a row to exercise tie breaking.
+		if test.rows > 1 {
+			db.SetRow(test.rows-1, db.RawRowView(0))
+		}
+		query := NewVecDense(test.cols, randSlice(test.cols, rnd))
+		for _, metric := range []Metric{L2, L1, Cosine, InnerProduct} {
+			wantIdx, wantDist := TopK(query, db, test.k, metric)
+
+			acc := NewTopKAccumulator(query, test.k, metric)
+			for _, j := range rnd.Perm(test.rows) {
+				acc.Push(j, db.RowView(j))
+			}
+			gotIdx, gotDist := acc.Result()
+			if !equalInts(gotIdx, wantIdx) {
+				t.Errorf("unexpected indices for test %d metric %d: got: %v want: %v", i, metric, gotIdx, wantIdx)
+			}
+			for j := range gotDist {
+				if gotDist[j] != wantDist[j] {
+					t.Errorf("unexpected distances for test %d metric %d: got: %v want: %v", i, metric, gotDist, wantDist)
+					break
+				}
+			}
+
+			// Result must not disturb the accumulator.
+			againIdx, _ := acc.Result()
+			if !equalInts(againIdx, gotIdx) {
+				t.Errorf("repeated Result changed for test %d metric %d: got: %v want: %v", i, metric, againIdx, gotIdx)
+			}
+		}
+	}
+
+	acc := NewTopKAccumulator(NewVecDense(2, nil), 5, L2)
+	acc.Push(7, NewVecDense(2, []float32{1, 1}))
+	if idx, _ := acc.Result(); !equalInts(idx, []int{7}) {
+		t.Errorf("unexpected partial result: got: %v want: %v", idx, []int{7})
+	}
+	matched, message := panicsWith(func() { acc.Push(8, NewVecDense(3, nil)) }, ErrShape)
+	if !matched {
+		t.Errorf("expected ErrShape for mismatched candidate: got: %q", message)
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, v := range a {
+		if v != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func BenchmarkTopK100000x128(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	db := randNormDense(100000, 128, rnd)
+	query := NewVecDense(128, randSlice(128, rnd))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		TopK(query, db, 10, SquaredL2)
+	}
+}
@@ -98,6 +98,10 @@ func generalFromSymmetric(a blas32.Symmetric) blas32.General {
 	}
 }
 
+func (s *SymDense) checkOverlap(a blas32.General) bool {
+	return checkOverlap(generalFromSymmetric(s.RawSymmetric()), a)
+}
+
 func (t *TriDense) checkOverlap(a blas32.General) bool {
 	return checkOverlap(generalFromTriangular(t.RawTriangular()), a)
 }
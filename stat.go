@@ -0,0 +1,196 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"github.com/arjunsk/mat32/internal/asm/f32"
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas32"
+)
+
+// Covariance places the sample covariance matrix of the columns of data
+// into dst. Each row of data is an observation and each column a variable,
+// so dst is c×c for an r×c data matrix, and
+//  dst = X^T * X / (r-1)
+// where X is data with its column means subtracted. If centered is true,
+// the columns of data are assumed to already have zero mean and no mean
+// subtraction is performed.
+//
+// Covariance panics with ErrShape if data has fewer than two rows. If dst
+// is empty it is resized to c×c, otherwise it must be c×c.
+func Covariance(dst *SymDense, data *Dense, centered bool) {
+	r, c := data.Dims()
+	if r < 2 {
+		panic(ErrShape)
+	}
+	dst.reuseAs(c)
+
+	x := data
+	if !centered {
+		x = getWorkspace(r, c, false)
+		defer putWorkspace(x)
+		x.Copy(data)
+		mean := getFloats(c, true)
+		defer putFloats(mean)
+		for i := 0; i < r; i++ {
+			f32.AxpyUnitary(1, x.RawRowView(i), mean)
+		}
+		f32.ScalUnitary(1/float32(r), mean)
+		for i := 0; i < r; i++ {
+			f32.AxpyUnitary(-1, mean, x.RawRowView(i))
+		}
+	}
+
+	cov := getWorkspace(c, c, false)
+	defer putWorkspace(cov)
+	blas32.Gemm(blas.Trans, blas.NoTrans, 1/float32(r-1), x.mat, x.mat, 0, cov.mat)
+	for i := 0; i < c; i++ {
+		copy(dst.mat.Data[i*dst.mat.Stride+i:i*dst.mat.Stride+c], cov.mat.Data[i*c+i:i*c+c])
+	}
+}
+
+// WeightedCentroid places the weighted mean of the rows of data into dst,
+//  dst = sum_i weights[i] * data[i,:] / sum_i weights[i]
+// If the weights sum to zero, dst is set to the zero vector. The weights
+// may be negative, in which case the result is an affine combination of
+// the rows rather than a weighted mean.
+//
+// WeightedCentroid panics with ErrSliceLengthMismatch if the length of
+// weights is not the number of rows of data. If dst is empty it is resized
+// to the number of columns of data, otherwise it must have that length.
+func WeightedCentroid(dst *VecDense, data *Dense, weights []float32) {
+	r, c := data.Dims()
+	if len(weights) != r {
+		panic(ErrSliceLengthMismatch)
+	}
+	dst.reuseAs(c)
+
+	var sum float32
+	for _, w := range weights {
+		sum += w
+	}
+	if sum == 0 {
+		for i := 0; i < c; i++ {
+			dst.setVec(i, 0)
+		}
+		return
+	}
+	blas32.Gemv(blas.Trans, 1/sum, data.mat, blas32.Vector{Inc: 1, Data: weights}, 0, dst.mat)
+}
+
+// DoubleCenter places the double-centered matrix
+//  dst = -1/2 * J * a * J
+// into dst, where J = I - 1/n * 1 * 1^T is the n×n centering matrix. When a
+// holds the squared Euclidean distances between n points, dst is their
+// Gram matrix about the centroid, as used in classical multidimensional
+// scaling. Rather than forming J, each element is computed from the row,
+// column and overall means of a,
+//  dst[i,j] = -1/2 * (a[i,j] - rowMean[i] - colMean[j] + mean)
+//
+// DoubleCenter panics with ErrSquare if a is not square. If dst is empty it
+// is resized to n×n, otherwise it must be n×n. dst may be a.
+func DoubleCenter(dst *Dense, a *Dense) {
+	n, c := a.Dims()
+	if n != c {
+		panic(ErrSquare)
+	}
+	if dst != a {
+		dst.reuseAs(n, n)
+		dst.Copy(a)
+	}
+
+	rowMean := getFloats(n, false)
+	defer putFloats(rowMean)
+	colMean := getFloats(n, true)
+	defer putFloats(colMean)
+	var mean float32
+	for i := 0; i < n; i++ {
+		row := dst.RawRowView(i)
+		rowMean[i] = f32.Sum(row) / float32(n)
+		mean += rowMean[i]
+		f32.AxpyUnitary(1, row, colMean)
+	}
+	f32.ScalUnitary(1/float32(n), colMean)
+	mean /= float32(n)
+
+	for i := 0; i < n; i++ {
+		row := dst.RawRowView(i)
+		ri := mean - rowMean[i]
+		for j, v := range row {
+			row[j] = -0.5 * (v - colMean[j] + ri)
+		}
+	}
+}
+
+// VecStats accumulates the per-element mean and variance of a stream of
+// vectors of equal length, without retaining the vectors. The statistics
+// are updated with Welford's online algorithm, which avoids the
+// cancellation suffered by accumulating sums of squares.
+//
+// The zero value of VecStats is ready to use. The length of the vectors is
+// set by the first call to Observe.
+type VecStats struct {
+	n    int
+	mean []float32
+	m2   []float32
+}
+
+// Observe adds v to the accumulated statistics. Observe panics with
+// ErrShape if the length of v differs from that of previously observed
+// vectors, and with ErrZeroLength if v has zero length.
+func (s *VecStats) Observe(v Vector) {
+	l := v.Len()
+	if l == 0 {
+		panic(ErrZeroLength)
+	}
+	if s.n == 0 {
+		s.mean = make([]float32, l)
+		s.m2 = make([]float32, l)
+	} else if l != len(s.mean) {
+		panic(ShapeError{Got: [2]int{l, 1}, Want: [2]int{len(s.mean), 1}})
+	}
+
+	s.n++
+	inv := 1 / float32(s.n)
+	for i := range s.mean {
+		x := v.AtVec(i)
+		d := x - s.mean[i]
+		s.mean[i] += d * inv
+		s.m2[i] += d * (x - s.mean[i])
+	}
+}
+
+// Count returns the number of vectors observed.
+func (s *VecStats) Count() int {
+	return s.n
+}
+
+// Mean returns a newly allocated vector holding the mean of each element
+// over the observed vectors. Mean panics with ErrZeroLength if no vectors
+// have been observed.
+func (s *VecStats) Mean() *VecDense {
+	if s.n == 0 {
+		panic(ErrZeroLength)
+	}
+	mean := make([]float32, len(s.mean))
+	copy(mean, s.mean)
+	return NewVecDense(len(mean), mean)
+}
+
+// Variance returns a newly allocated vector holding the unbiased sample
+// variance of each element over the observed vectors, normalized by n-1
+// as in Covariance. The variance is NaN if only one vector has been
+// observed. Variance panics with ErrZeroLength if no vectors have been
+// observed.
+func (s *VecStats) Variance() *VecDense {
+	if s.n == 0 {
+		panic(ErrZeroLength)
+	}
+	v := make([]float32, len(s.m2))
+	for i, m2 := range s.m2 {
+		v[i] = m2 / float32(s.n-1)
+	}
+	return NewVecDense(len(v), v)
+}
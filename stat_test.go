@@ -0,0 +1,282 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"testing"
+
+	"github.com/chewxy/math32"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestCovariance(t *testing.T) {
+	for i, test := range []struct {
+		data     *Dense
+		centered bool
+		want     *SymDense
+	}{
+		{
+			data: NewDense(3, 2, []float32{
+				1, 2,
+				2, 4,
+				3, 6,
+			}),
+			want: NewSymDense(2, []float32{
+				1, 2,
+				2, 4,
+			}),
+		},
+		{
+			data: NewDense(4, 3, []float32{
+				2, 0, 1,
+				4, 1, 1,
+				6, 0, 1,
+				8, 3, 1,
+			}),
+			// Column means are 5, 1, 1.
+			want: NewSymDense(3, []float32{
+				20.0 / 3, 8.0 / 3, 0,
+				8.0 / 3, 2, 0,
+				0, 0, 0,
+			}),
+		},
+		{
+			data: NewDense(2, 2, []float32{
+				1, -1,
+				-1, 1,
+			}),
+			centered: true,
+			want: NewSymDense(2, []float32{
+				2, -2,
+				-2, 2,
+			}),
+		},
+		{
+			// Uncentered data treated as centered.
+			data: NewDense(2, 1, []float32{
+				1,
+				3,
+			}),
+			centered: true,
+			want:     NewSymDense(1, []float32{10}),
+		},
+	} {
+		var got SymDense
+		Covariance(&got, test.data, test.centered)
+		if !EqualApprox(&got, test.want, 1e-6) {
+			t.Errorf("unexpected covariance for test %d:\ngot:\n%v\nwant:\n%v", i, Formatted(&got), Formatted(test.want))
+		}
+	}
+
+	matched, message := panicsWith(func() {
+		var dst SymDense
+		Covariance(&dst, NewDense(1, 3, nil), false)
+	}, ErrShape)
+	if !matched {
+		t.Errorf("expected ErrShape for a single observation: got: %q", message)
+	}
+}
+
+func TestWeightedCentroid(t *testing.T) {
+	data := NewDense(4, 3, []float32{
+		1, 2, 3,
+		4, 5, 6,
+		7, 8, 9,
+		0, -1, 2,
+	})
+	for i, test := range []struct {
+		weights []float32
+		want    []float32
+	}{
+		{
+			// Uniform weights give the column means.
+			weights: []float32{1, 1, 1, 1},
+			want:    []float32{3, 3.5, 5},
+		},
+		{
+			weights: []float32{0.25, 0.25, 0.25, 0.25},
+			want:    []float32{3, 3.5, 5},
+		},
+		{
+			weights: []float32{0, 2, 0, 0},
+			want:    []float32{4, 5, 6},
+		},
+		{
+			weights: []float32{1, 0, 3, 0},
+			want:    []float32{5.5, 6.5, 7.5},
+		},
+		{
+			// A zero weight sum gives the zero vector.
+			weights: []float32{1, -1, 0, 0},
+			want:    []float32{0, 0, 0},
+		},
+	} {
+		var got VecDense
+		WeightedCentroid(&got, data, test.weights)
+		want := NewVecDense(len(test.want), test.want)
+		if !EqualApprox(&got, want, 1e-6) {
+			t.Errorf("unexpected centroid for test %d: got: %v want: %v", i, got.RawVector().Data, test.want)
+		}
+	}
+
+	// The uniform centroid matches the column means for a strided view.
+	sub := NewDense(6, 5, nil)
+	for i := range sub.mat.Data {
+		sub.mat.Data[i] = float32(i % 7)
+	}
+	view := sub.Slice(1, 5, 1, 4).(*Dense)
+	dst := NewDense(3, 2, nil).ColView(1).(*VecDense)
+	WeightedCentroid(dst, view, []float32{2, 2, 2, 2})
+	for j := 0; j < 3; j++ {
+		var mean float32
+		for i := 0; i < 4; i++ {
+			mean += view.At(i, j)
+		}
+		mean /= 4
+		if got := dst.AtVec(j); math32.Abs(got-mean) > 1e-6 {
+			t.Errorf("unexpected centroid element %d for view: got: %v want: %v", j, got, mean)
+		}
+	}
+
+	panicked, message := panics(func() {
+		var dst VecDense
+		WeightedCentroid(&dst, data, []float32{1, 2})
+	})
+	if !panicked || message != ErrSliceLengthMismatch.Error() {
+		t.Errorf("expected ErrSliceLengthMismatch panic: got: %q", message)
+	}
+}
+
+func TestVecStats(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		r, c   int
+		offset float32
+	}{
+		{2, 1, 0},
+		{10, 3, 0},
+		{1000, 8, 0},
+		// A large offset relative to the spread defeats the
+		// naive sum of squares but not Welford's algorithm.
+		{1000, 4, 1000},
+	} {
+		data := randNormDense(test.r, test.c, rnd)
+		for i := range data.mat.Data {
+			data.mat.Data[i] += test.offset
+		}
+
+		var s VecStats
+		for i := 0; i < test.r; i++ {
+			s.Observe(data.RowView(i))
+		}
+		if s.Count() != test.r {
+			t.Errorf("unexpected count: got: %d want: %d", s.Count(), test.r)
+		}
+
+		// Batch statistics computed in float64.
+		mean := s.Mean()
+		variance := s.Variance()
+		for j := 0; j < test.c; j++ {
+			var sum float64
+			for i := 0; i < test.r; i++ {
+				sum += float64(data.At(i, j))
+			}
+			m := sum / float64(test.r)
+			var ss float64
+			for i := 0; i < test.r; i++ {
+				d := float64(data.At(i, j)) - m
+				ss += d * d
+			}
+			v := ss / float64(test.r-1)
+
+			if !EqualWithinAbsOrRel(mean.AtVec(j), float32(m), 1e-4, 1e-5) {
+				t.Errorf("unexpected mean for %d×%d offset %v column %d: got: %v want: %v",
+					test.r, test.c, test.offset, j, mean.AtVec(j), m)
+			}
+			if !EqualWithinAbsOrRel(variance.AtVec(j), float32(v), 1e-3, 1e-3) {
+				t.Errorf("unexpected variance for %d×%d offset %v column %d: got: %v want: %v",
+					test.r, test.c, test.offset, j, variance.AtVec(j), v)
+			}
+		}
+	}
+
+	var s VecStats
+	s.Observe(NewVecDense(2, []float32{1, 2}))
+	if v := s.Variance(); !math32.IsNaN(v.AtVec(0)) {
+		t.Errorf("expected NaN variance for a single observation: got: %v", v.AtVec(0))
+	}
+	matched, message := panicsWith(func() { s.Observe(NewVecDense(3, nil)) }, ErrShape)
+	if !matched {
+		t.Errorf("expected shape panic: got: %q", message)
+	}
+	panicked, message := panics(func() {
+		var empty VecStats
+		empty.Mean()
+	})
+	if !panicked || message != ErrZeroLength.Error() {
+		t.Errorf("expected zero length panic: got: %q", message)
+	}
+}
+
+func TestDoubleCenter(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 4, 7} {
+		// Squared distances between random points.
+		x := randNormDense(n, 3, rnd)
+		d := NewDense(n, n, nil)
+		CrossL2(d, x, x)
+		d.MulElem(d, d)
+
+		// Explicit J = I - 1/n * 1 * 1^T.
+		j := NewDense(n, n, nil)
+		for r := 0; r < n; r++ {
+			for c := 0; c < n; c++ {
+				j.Set(r, c, -1/float32(n))
+			}
+			j.Set(r, r, 1-1/float32(n))
+		}
+		var want Dense
+		want.Mul(j, d)
+		want.Mul(&want, j)
+		want.Scale(-0.5, &want)
+
+		var got Dense
+		DoubleCenter(&got, d)
+		if !EqualApprox(&got, &want, 1e-4) {
+			t.Errorf("unexpected result for n=%d:\ngot:\n%v\nwant:\n%v", n, Formatted(&got), Formatted(&want))
+		}
+
+		// For squared distances the result is the Gram matrix
+		// of the centered points.
+		xc := DenseCopyOf(x)
+		for c := 0; c < 3; c++ {
+			var mean float32
+			for r := 0; r < n; r++ {
+				mean += x.At(r, c)
+			}
+			mean /= float32(n)
+			for r := 0; r < n; r++ {
+				xc.Set(r, c, x.At(r, c)-mean)
+			}
+		}
+		var gram Dense
+		gram.Mul(xc, xc.T())
+		if !EqualApprox(&got, &gram, 1e-4) {
+			t.Errorf("result is not the centered Gram matrix for n=%d:\ngot:\n%v\nwant:\n%v", n, Formatted(&got), Formatted(&gram))
+		}
+
+		inPlace := DenseCopyOf(d)
+		DoubleCenter(inPlace, inPlace)
+		if !Equal(inPlace, &got) {
+			t.Errorf("unexpected in-place result for n=%d", n)
+		}
+	}
+
+	panicked, message := panics(func() { DoubleCenter(&Dense{}, NewDense(2, 3, nil)) })
+	if !panicked || message != ErrSquare.Error() {
+		t.Errorf("expected ErrSquare for non-square input: got: %q", message)
+	}
+}
@@ -0,0 +1,303 @@
+// Copyright ©2013 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"math"
+	"sort"
+
+	"github.com/arjunsk/mat32/internal/asm/f32"
+	"github.com/chewxy/math32"
+)
+
+// svdTol is the relative orthogonality threshold below which a pair of
+// columns is considered converged by the one-sided Jacobi SVD.
+const svdTol = 1e-6
+
+// SVD is a type for creating and using the Singular Value Decomposition (SVD)
+// of a matrix.
+type SVD struct {
+	kind SVDKind
+
+	s []float32
+	u *Dense
+	v *Dense
+}
+
+// Factorize computes the singular value decomposition (SVD) of the input matrix A.
+// The singular values of A are computed in all cases, while the singular
+// vectors are optionally computed depending on the input kind.
+//
+// The thin singular value decomposition (kind == SVDThin) is a factorization
+// of an m×n matrix A of the form
+//  A = U * Σ * V^T
+// where Σ is a min(m,n)×min(m,n) diagonal matrix, U is an m×min(m,n) matrix
+// with orthonormal columns, and V is an n×min(m,n) matrix with orthonormal
+// columns. The diagonal elements of Σ are the singular values of A, and the
+// columns of U and V are, respectively, the left and right singular vectors
+// of A. Only the singular values are computed when kind == SVDNone. The full
+// decomposition, SVDFull, is not supported and Factorize will panic if it
+// is requested.
+//
+// The decomposition is computed with the one-sided Jacobi method, which
+// orthogonalizes the columns of A by plane rotations and retains high
+// relative accuracy in float32.
+//
+// Factorize returns whether the decomposition succeeded. If the decomposition
+// failed, routines that require a successful factorization will panic.
+func (svd *SVD) Factorize(a Matrix, kind SVDKind) (ok bool) {
+	if kind != SVDNone && kind != SVDThin {
+		panic("svd: bad input kind")
+	}
+	m, n := a.Dims()
+	if m == 0 || n == 0 {
+		panic(ErrZeroLength)
+	}
+
+	// The columns of the taller of A and A^T are orthogonalized.
+	// They are held as the rows of w so the rotations operate on
+	// contiguous data, and the accumulated rotations are held as
+	// the rows of z.
+	trans := m < n
+	p, q := m, n
+	if trans {
+		p, q = n, m
+	}
+	w := NewDense(q, p, nil)
+	if trans {
+		w.Copy(a)
+	} else {
+		w.Copy(a.T())
+	}
+	z := NewDense(q, q, nil)
+	for i := 0; i < q; i++ {
+		z.mat.Data[i*q+i] = 1
+	}
+	// Columns with norm below tiny hold only rounding error.
+	tiny := svdTol * float32(math.Sqrt(f32.DdotUnitary(w.mat.Data, w.mat.Data)))
+	if !jacobiSVD(w.mat.Data, z.mat.Data, p, q, tiny) {
+		svd.kind = 0
+		return false
+	}
+
+	s := make([]float32, q)
+	for i := range s {
+		row := w.mat.Data[i*p : (i+1)*p]
+		s[i] = float32(math.Sqrt(f32.DdotUnitary(row, row)))
+	}
+	order := make([]int, q)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return s[order[i]] > s[order[j]] })
+
+	svd.kind = kind
+	svd.s = use(svd.s, q)
+	for j, k := range order {
+		svd.s[j] = s[k]
+	}
+	if kind == SVDNone {
+		svd.u = nil
+		svd.v = nil
+		return true
+	}
+
+	// Normalize the orthogonalized columns to obtain the singular
+	// vectors of the taller matrix, replacing negligible columns
+	// with an orthonormal completion.
+	left := NewDense(p, q, nil)
+	right := NewDense(q, q, nil)
+	for j, k := range order {
+		if s[k] > tiny {
+			f32.ScalIncTo(left.mat.Data[j:], uintptr(q), 1/s[k], w.mat.Data[k*p:(k+1)*p], uintptr(p), 1)
+		}
+		for i := 0; i < q; i++ {
+			right.mat.Data[i*q+j] = z.mat.Data[k*q+i]
+		}
+	}
+	for j, k := range order {
+		if s[k] <= tiny {
+			completeColumn(left, j)
+		}
+	}
+	if trans {
+		svd.u, svd.v = right, left
+	} else {
+		svd.u, svd.v = left, right
+	}
+	return true
+}
+
+// jacobiSVD orthogonalizes the q rows of length p held in w by applying
+// plane rotations to pairs of rows, accumulating the rotations into the
+// rows of the q×q matrix z, which must hold the identity on entry. Both
+// matrices are stored row-major. Rows with norm at most tiny are left
+// untouched. jacobiSVD returns whether all other pairs of rows were
+// orthogonal to within svdTol before the sweep limit.
+func jacobiSVD(w, z []float32, p, q int, tiny float32) bool {
+	tiny2 := float64(tiny) * float64(tiny)
+	for sweep := 0; sweep < maxJacobiSweeps; sweep++ {
+		rotated := false
+		for i := 0; i < q-1; i++ {
+			wi := w[i*p : (i+1)*p]
+			zi := z[i*q : (i+1)*q]
+			for j := i + 1; j < q; j++ {
+				wj := w[j*p : (j+1)*p]
+				zj := z[j*q : (j+1)*q]
+				alpha := f32.DdotUnitary(wi, wi)
+				beta := f32.DdotUnitary(wj, wj)
+				gamma := f32.DdotUnitary(wi, wj)
+				if alpha <= tiny2 || beta <= tiny2 || math.Abs(gamma) <= svdTol*math.Sqrt(alpha*beta) {
+					continue
+				}
+				rotated = true
+				c, s := jacobiRotation(float32(alpha), float32(beta), float32(gamma))
+				for k, x := range wi {
+					y := wj[k]
+					wi[k] = c*x - s*y
+					wj[k] = s*x + c*y
+				}
+				for k, x := range zi {
+					y := zj[k]
+					zi[k] = c*x - s*y
+					zj[k] = s*x + c*y
+				}
+			}
+		}
+		if !rotated {
+			return true
+		}
+	}
+	return false
+}
+
+// completeColumn sets column j of m to a unit vector orthogonal to all
+// other columns of m that are either zero or of unit norm.
+func completeColumn(m *Dense, j int) {
+	r, c := m.Dims()
+	col := make([]float32, r)
+	for e := 0; e < r; e++ {
+		zero(col)
+		col[e] = 1
+		for k := 0; k < c; k++ {
+			if k == j {
+				continue
+			}
+			d := f32.DotInc(m.mat.Data[k:], col, uintptr(r), uintptr(m.mat.Stride), 1, 0, 0)
+			f32.AxpyInc(-d, m.mat.Data[k:], col, uintptr(r), uintptr(m.mat.Stride), 1, 0, 0)
+		}
+		norm := math32.Sqrt(f32.DotUnitary(col, col))
+		if norm > 0.5 {
+			f32.ScalIncTo(m.mat.Data[j:], uintptr(m.mat.Stride), 1/norm, col, uintptr(r), 1)
+			return
+		}
+	}
+}
+
+// Kind returns the SVDKind of the decomposition. If no decomposition has been
+// computed, Kind returns 0.
+func (svd *SVD) Kind() SVDKind {
+	return svd.kind
+}
+
+// Values returns the singular values of the factorized matrix in descending order.
+//
+// If the input slice is non-nil, the values will be stored in-place into
+// the slice. In this case, the slice must have length min(m,n), and Values will
+// panic with ErrSliceLengthMismatch otherwise. If the input slice is nil, a new
+// slice of the appropriate length will be allocated and returned.
+//
+// Values will panic if the receiver does not contain a successful factorization.
+func (svd *SVD) Values(s []float32) []float32 {
+	if svd.kind == 0 {
+		panic("svd: no decomposition computed")
+	}
+	if s == nil {
+		s = make([]float32, len(svd.s))
+	}
+	if len(s) != len(svd.s) {
+		panic(ErrSliceLengthMismatch)
+	}
+	copy(s, svd.s)
+	return s
+}
+
+// UTo extracts the matrix U from the singular value decomposition. The
+// columns are the left singular vectors and correspond to the singular
+// values as returned from SVD.Values.
+//
+// If dst is not nil, U is stored in-place into dst, and dst must have size
+// m×min(m,n) or be empty. If dst is nil, a new matrix of the appropriate size
+// is allocated and returned. UTo panics if svd.Kind() is not SVDThin.
+func (svd *SVD) UTo(dst *Dense) *Dense {
+	if svd.kind != SVDThin {
+		panic("mat: improper SVD kind")
+	}
+	r, c := svd.u.Dims()
+	if dst == nil {
+		dst = NewDense(r, c, nil)
+	} else {
+		dst.reuseAs(r, c)
+	}
+	dst.Copy(svd.u)
+	return dst
+}
+
+// VTo extracts the matrix V from the singular value decomposition. The
+// columns are the right singular vectors and correspond to the singular
+// values as returned from SVD.Values.
+//
+// If dst is not nil, V is stored in-place into dst, and dst must have size
+// n×min(m,n) or be empty. If dst is nil, a new matrix of the appropriate size
+// is allocated and returned. VTo panics if svd.Kind() is not SVDThin.
+func (svd *SVD) VTo(dst *Dense) *Dense {
+	if svd.kind != SVDThin {
+		panic("mat: improper SVD kind")
+	}
+	r, c := svd.v.Dims()
+	if dst == nil {
+		dst = NewDense(r, c, nil)
+	} else {
+		dst.reuseAs(r, c)
+	}
+	dst.Copy(svd.v)
+	return dst
+}
+
+// LowRankApprox places the best rank-k approximation of a, in both the
+// Frobenius and spectral norms, into dst. The approximation is formed by
+// truncating the thin singular value decomposition of a to its k largest
+// singular values,
+//  dst = U_k * Σ_k * V_k^T
+//
+// LowRankApprox panics with ErrIndexOutOfRange if k is negative or greater
+// than min(m,n) where a is m×n, and with ErrFailedEigen if the SVD does not
+// converge. If dst is empty it is resized to m×n, otherwise it must be m×n.
+func LowRankApprox(dst *Dense, a Matrix, k int) {
+	m, n := a.Dims()
+	if k < 0 || k > min(m, n) {
+		panic(ErrIndexOutOfRange)
+	}
+	if k == 0 {
+		dst.reuseAsZeroed(m, n)
+		return
+	}
+
+	var svd SVD
+	if !svd.Factorize(a, SVDThin) {
+		panic(ErrFailedEigen)
+	}
+	s := svd.Values(nil)
+	u := svd.UTo(nil)
+	v := svd.VTo(nil)
+
+	// Scale the leading k columns of U by their singular values
+	// and form the product with the leading k columns of V.
+	uk := u.Slice(0, m, 0, k).(*Dense)
+	for j := 0; j < k; j++ {
+		f32.ScalInc(s[j], uk.mat.Data[j:], uintptr(m), uintptr(uk.mat.Stride))
+	}
+	dst.Mul(uk, v.Slice(0, n, 0, k).T())
+}
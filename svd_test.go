@@ -0,0 +1,199 @@
+// Copyright ©2013 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestSVD(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i, test := range []struct {
+		a      *Dense
+		values []float32
+	}{
+		{
+			a:      NewDense(2, 2, []float32{3, 0, 0, -4}),
+			values: []float32{4, 3},
+		},
+		{
+			a: NewDense(3, 2, []float32{
+				1, 2,
+				3, 4,
+				5, 6,
+			}),
+			values: []float32{9.525518091565107, 0.514300580658644},
+		},
+		{
+			a: NewDense(2, 3, []float32{
+				1, 3, 5,
+				2, 4, 6,
+			}),
+			values: []float32{9.525518091565107, 0.514300580658644},
+		},
+		{
+			// Rank deficient.
+			a: NewDense(3, 3, []float32{
+				1, 2, 3,
+				2, 4, 6,
+				1, 1, 1,
+			}),
+		},
+		{a: randNormDense(8, 5, rnd)},
+		{a: randNormDense(5, 8, rnd)},
+		{a: randNormDense(10, 10, rnd)},
+	} {
+		var svd SVD
+		if !svd.Factorize(test.a, SVDThin) {
+			t.Errorf("unexpected factorization failure for test %d", i)
+			continue
+		}
+		m, n := test.a.Dims()
+		k := min(m, n)
+		s := svd.Values(nil)
+		if len(s) != k {
+			t.Errorf("unexpected number of singular values for test %d: got: %d want: %d", i, len(s), k)
+			continue
+		}
+		for j, v := range s {
+			if v < 0 {
+				t.Errorf("negative singular value for test %d: %v", i, s)
+				break
+			}
+			if j > 0 && v > s[j-1] {
+				t.Errorf("singular values not sorted descending for test %d: %v", i, s)
+				break
+			}
+		}
+		if test.values != nil && !EqualApprox(NewVecDense(k, s), NewVecDense(k, test.values), 1e-4) {
+			t.Errorf("unexpected singular values for test %d: got: %v want: %v", i, s, test.values)
+		}
+
+		u := svd.UTo(nil)
+		v := svd.VTo(nil)
+		if r, c := u.Dims(); r != m || c != k {
+			t.Errorf("unexpected U shape for test %d: got: %d×%d want: %d×%d", i, r, c, m, k)
+		}
+		if r, c := v.Dims(); r != n || c != k {
+			t.Errorf("unexpected V shape for test %d: got: %d×%d want: %d×%d", i, r, c, n, k)
+		}
+
+		var utu, vtv Dense
+		utu.Mul(u.T(), u)
+		vtv.Mul(v.T(), v)
+		if !EqualApprox(&utu, eye(k), 1e-4) {
+			t.Errorf("U not orthonormal for test %d:\n%v", i, Formatted(&utu))
+		}
+		if !EqualApprox(&vtv, eye(k), 1e-4) {
+			t.Errorf("V not orthonormal for test %d:\n%v", i, Formatted(&vtv))
+		}
+
+		var us, got Dense
+		us.Mul(u, NewDiagonalRect(k, k, s))
+		got.Mul(&us, v.T())
+		if !EqualApprox(&got, test.a, 1e-4) {
+			t.Errorf("unexpected reconstruction for test %d:\ngot:\n%v\nwant:\n%v", i, Formatted(&got), Formatted(test.a))
+		}
+
+		var none SVD
+		if !none.Factorize(test.a, SVDNone) {
+			t.Errorf("unexpected SVDNone factorization failure for test %d", i)
+			continue
+		}
+		if !EqualApprox(NewVecDense(k, none.Values(nil)), NewVecDense(k, s), 1e-5) {
+			t.Errorf("singular values mismatch between kinds for test %d", i)
+		}
+	}
+}
+
+func TestLowRankApprox(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i, test := range []struct {
+		a    *Dense
+		k    int
+		want *Dense
+	}{
+		{
+			// Outer product of [1 2 3] and [4 5].
+			a: NewDense(3, 2, []float32{
+				4, 5,
+				8, 10,
+				12, 15,
+			}),
+			k: 1,
+			want: NewDense(3, 2, []float32{
+				4, 5,
+				8, 10,
+				12, 15,
+			}),
+		},
+		{
+			// Outer product of [-1 0.5] and [2 0 -3 1].
+			a: NewDense(2, 4, []float32{
+				-2, 0, 3, -1,
+				1, 0, -1.5, 0.5,
+			}),
+			k: 1,
+			want: NewDense(2, 4, []float32{
+				-2, 0, 3, -1,
+				1, 0, -1.5, 0.5,
+			}),
+		},
+		{
+			a: NewDense(3, 3, []float32{
+				3, 0, 0,
+				0, 2, 0,
+				0, 0, 1,
+			}),
+			k: 2,
+			want: NewDense(3, 3, []float32{
+				3, 0, 0,
+				0, 2, 0,
+				0, 0, 0,
+			}),
+		},
+		{
+			a:    NewDense(2, 2, []float32{1, 2, 3, 4}),
+			k:    0,
+			want: NewDense(2, 2, nil),
+		},
+		{
+			a: randNormDense(6, 4, rnd),
+			k: 4,
+		},
+	} {
+		want := test.want
+		if want == nil {
+			want = test.a
+		}
+		var got Dense
+		LowRankApprox(&got, test.a, test.k)
+		if !EqualApprox(&got, want, 1e-4) {
+			t.Errorf("unexpected approximation for test %d:\ngot:\n%v\nwant:\n%v", i, Formatted(&got), Formatted(want))
+		}
+	}
+
+	for _, k := range []int{-1, 3} {
+		panicked, message := panics(func() {
+			var dst Dense
+			LowRankApprox(&dst, NewDense(2, 3, nil), k)
+		})
+		if !panicked || message != ErrIndexOutOfRange.Error() {
+			t.Errorf("expected ErrIndexOutOfRange for k=%d: got: %q", k, message)
+		}
+	}
+}
+
+// randNormDense returns an r×c matrix with elements drawn from the
+// standard normal distribution.
+func randNormDense(r, c int, rnd *rand.Rand) *Dense {
+	m := NewDense(r, c, nil)
+	for i := range m.mat.Data {
+		m.mat.Data[i] = float32(rnd.NormFloat64())
+	}
+	return m
+}
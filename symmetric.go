@@ -0,0 +1,277 @@
+// Copyright ©2015 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas32"
+)
+
+var (
+	symDense *SymDense
+
+	_ Matrix           = symDense
+	_ Symmetric        = symDense
+	_ RawSymmetricer   = symDense
+	_ MutableSymmetric = symDense
+)
+
+const (
+	badSymTriangle = "mat: blas32.Symmetric not upper"
+	badSymCap      = "mat: bad capacity for SymDense"
+)
+
+// SymDense is a symmetric matrix that uses dense storage. SymDense
+// matrices are stored in the upper triangle.
+type SymDense struct {
+	mat blas32.Symmetric
+	cap int
+}
+
+// Symmetric represents a symmetric matrix (where the element at {i, j} equals
+// the element at {j, i}). Symmetric matrices are always square.
+type Symmetric interface {
+	Matrix
+	// Symmetric returns the number of rows/columns in the matrix.
+	Symmetric() int
+}
+
+// A RawSymmetricer can return a view of itself as a BLAS Symmetric matrix.
+type RawSymmetricer interface {
+	RawSymmetric() blas32.Symmetric
+}
+
+// A MutableSymmetric can set elements of a symmetric matrix.
+type MutableSymmetric interface {
+	Symmetric
+	SetSym(i, j int, v float32)
+}
+
+// NewSymDense creates a new Symmetric matrix with n rows and columns. If data == nil,
+// a new slice is allocated for the backing slice. If len(data) == n*n, data is
+// used as the backing slice, and changes to the elements of the returned SymDense
+// will be reflected in data. If neither of these is true, NewSymDense will panic.
+// NewSymDense will panic if n is zero.
+//
+// The data must be arranged in row-major order, i.e. the (i*c + j)-th
+// element in the data slice is the {i, j}-th element in the matrix.
+// Only the values in the upper triangular portion of the matrix are used.
+func NewSymDense(n int, data []float32) *SymDense {
+	if n <= 0 {
+		if n == 0 {
+			panic(ErrZeroLength)
+		}
+		panic("mat: negative dimension")
+	}
+	if data != nil && n*n != len(data) {
+		panic(ShapeError{Got: [2]int{len(data), 1}, Want: [2]int{n * n, 1}})
+	}
+	if data == nil {
+		data = make([]float32, n*n)
+	}
+	return &SymDense{
+		mat: blas32.Symmetric{
+			N:      n,
+			Stride: n,
+			Data:   data,
+			Uplo:   blas.Upper,
+		},
+		cap: n,
+	}
+}
+
+// Dims returns the number of rows and columns in the matrix.
+func (s *SymDense) Dims() (r, c int) {
+	return s.mat.N, s.mat.N
+}
+
+// Caps returns the number of rows and columns in the backing matrix.
+func (s *SymDense) Caps() (r, c int) {
+	return s.cap, s.cap
+}
+
+// T implements the Matrix interface. Symmetric matrices, by definition, are
+// equal to their transpose, and this is a no-op.
+func (s *SymDense) T() Matrix {
+	return s
+}
+
+// Symmetric returns the number of rows/columns in the matrix.
+func (s *SymDense) Symmetric() int {
+	return s.mat.N
+}
+
+// RawSymmetric returns the matrix as a blas32.Symmetric. The returned
+// value must be stored in upper triangular format.
+func (s *SymDense) RawSymmetric() blas32.Symmetric {
+	return s.mat
+}
+
+// SetRawSymmetric sets the underlying blas32.Symmetric used by the receiver.
+// Changes to elements in the receiver following the call will be reflected
+// in b. SetRawSymmetric will panic if b is not an upper-encoded symmetric
+// matrix.
+func (s *SymDense) SetRawSymmetric(b blas32.Symmetric) {
+	if b.Uplo != blas.Upper {
+		panic(badSymTriangle)
+	}
+	s.mat = b
+	s.cap = b.N
+}
+
+// Reset zeros the dimensions of the matrix so that it can be reused as the
+// receiver of a dimensionally restricted operation.
+//
+// See the Reseter interface for more information.
+func (s *SymDense) Reset() {
+	// N and Stride must be zeroed in unison.
+	s.mat.N, s.mat.Stride = 0, 0
+	s.mat.Data = s.mat.Data[:0]
+}
+
+// IsZero returns whether the receiver is zero-sized. Zero-sized matrices can be the
+// receiver for size-restricted operations. SymDense matrices can be zeroed using Reset.
+func (s *SymDense) IsZero() bool {
+	// It must be the case that m.Dims() returns
+	// zeros in this case. See comment in Reset().
+	return s.mat.N == 0
+}
+
+// reuseAs resizes an empty matrix to a n×n matrix,
+// or checks that a non-empty matrix is n×n.
+func (s *SymDense) reuseAs(n int) {
+	if n == 0 {
+		panic(ErrZeroLength)
+	}
+	if s.mat.N > s.cap {
+		panic(badSymCap)
+	}
+	if s.IsZero() {
+		s.mat = blas32.Symmetric{
+			N:      n,
+			Stride: n,
+			Data:   use(s.mat.Data, n*n),
+			Uplo:   blas.Upper,
+		}
+		s.cap = n
+		return
+	}
+	if s.mat.Uplo != blas.Upper {
+		panic(badSymTriangle)
+	}
+	if s.mat.N != n {
+		panic(ShapeError{Got: [2]int{s.mat.N, s.mat.N}, Want: [2]int{n, n}})
+	}
+}
+
+// CopySym makes a copy of elements of a into the receiver. It is similar to the
+// built-in copy; it copies as much as the overlap between the two matrices and
+// returns the number of rows and columns it copied.
+func (s *SymDense) CopySym(a Symmetric) int {
+	n := a.Symmetric()
+	n = min(n, s.mat.N)
+	if n == 0 {
+		return 0
+	}
+	switch a := a.(type) {
+	case RawSymmetricer:
+		amat := a.RawSymmetric()
+		if amat.Uplo != blas.Upper {
+			panic(badSymTriangle)
+		}
+		for i := 0; i < n; i++ {
+			copy(s.mat.Data[i*s.mat.Stride+i:i*s.mat.Stride+n], amat.Data[i*amat.Stride+i:i*amat.Stride+n])
+		}
+	default:
+		for i := 0; i < n; i++ {
+			stmp := s.mat.Data[i*s.mat.Stride : i*s.mat.Stride+n]
+			for j := i; j < n; j++ {
+				stmp[j] = a.At(i, j)
+			}
+		}
+	}
+	return n
+}
+
+// SymRankK performs a symmetric rank-k update of the receiver,
+//  s = beta * s + alpha * A * A^T
+// where A is an n×k matrix and the receiver is n×n. Only the stored upper
+// triangle of the receiver is referenced and updated. Repeated calls with
+// beta == 1 accumulate the Gram matrix of a stream of column blocks.
+//
+// If the receiver is empty it is resized to n×n and its initial value is
+// taken to be zero. Otherwise SymRankK panics with ErrShape if the receiver
+// is not n×n. SymRankK panics if a shares storage with the receiver.
+func (s *SymDense) SymRankK(alpha float32, a Matrix, beta float32) {
+	n, k := a.Dims()
+	empty := s.IsZero()
+	s.reuseAs(n)
+	if empty {
+		for i := 0; i < n; i++ {
+			zero(s.mat.Data[i*s.mat.Stride+i : i*s.mat.Stride+n])
+		}
+	}
+
+	// Get a raw view of a, copying if necessary.
+	t := blas.NoTrans
+	var amat blas32.General
+	aU, aTrans := untranspose(a)
+	if rm, ok := aU.(RawMatrixer); ok {
+		amat = rm.RawMatrix()
+		s.checkOverlap(amat)
+		if aTrans {
+			t = blas.Trans
+		}
+	} else {
+		w := getWorkspace(n, k, false)
+		defer putWorkspace(w)
+		w.Copy(a)
+		amat = w.mat
+	}
+	blas32.Syrk(t, alpha, amat, beta, s.mat)
+}
+
+// SymRankTwo performs a symmetric rank-two update of the receiver,
+//  s = s + alpha * (x * y^T + y * x^T)
+// where x and y are vectors of length n and the receiver is n×n. Only the
+// stored upper triangle of the receiver is referenced and updated.
+//
+// If the receiver is empty it is resized to n×n and its initial value is
+// taken to be zero. Otherwise SymRankTwo panics with ErrShape if the
+// receiver is not n×n. SymRankTwo also panics with ErrShape if x and y have
+// different lengths.
+func (s *SymDense) SymRankTwo(alpha float32, x, y Vector) {
+	n := x.Len()
+	if y.Len() != n {
+		panic(ShapeError{Got: [2]int{y.Len(), 1}, Want: [2]int{n, 1}})
+	}
+	empty := s.IsZero()
+	s.reuseAs(n)
+	if empty {
+		for i := 0; i < n; i++ {
+			zero(s.mat.Data[i*s.mat.Stride+i : i*s.mat.Stride+n])
+		}
+	}
+
+	blas32.Syr2(alpha, s.rawVectorOf(x), s.rawVectorOf(y), s.mat)
+}
+
+// rawVectorOf returns a blas32.Vector holding the elements of x for use as
+// a read-only operand in an update of the receiver. If x is not a
+// RawVectorer, its elements are copied into a newly allocated vector.
+func (s *SymDense) rawVectorOf(x Vector) blas32.Vector {
+	if xv, ok := x.(*VecDense); ok {
+		s.checkOverlap(xv.asGeneral())
+	}
+	if rv, ok := x.(RawVectorer); ok {
+		return rv.RawVector()
+	}
+	n := x.Len()
+	v := blas32.Vector{Inc: 1, Data: make([]float32, n)}
+	for i := range v.Data {
+		v.Data[i] = x.AtVec(i)
+	}
+	return v
+}
@@ -0,0 +1,282 @@
+// Copyright ©2015 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas32"
+)
+
+func TestNewSymmetric(t *testing.T) {
+	for i, test := range []struct {
+		data []float32
+		n    int
+		mat  *SymDense
+	}{
+		{
+			data: []float32{
+				1, 2, 3,
+				4, 5, 6,
+				7, 8, 9,
+			},
+			n: 3,
+			mat: &SymDense{
+				mat: blas32.Symmetric{
+					N:      3,
+					Stride: 3,
+					Uplo:   blas.Upper,
+					Data:   []float32{1, 2, 3, 4, 5, 6, 7, 8, 9},
+				},
+				cap: 3,
+			},
+		},
+	} {
+		sym := NewSymDense(test.n, test.data)
+		rows, cols := sym.Dims()
+
+		if rows != test.n {
+			t.Errorf("unexpected number of rows for test %d: got: %d want: %d", i, rows, test.n)
+		}
+		if cols != test.n {
+			t.Errorf("unexpected number of cols for test %d: got: %d want: %d", i, cols, test.n)
+		}
+		if !reflect.DeepEqual(sym, test.mat) {
+			t.Errorf("unexpected data slice for test %d: got: %v want: %v", i, sym, test.mat)
+		}
+
+		m := NewDense(test.n, test.n, test.data)
+		if !reflect.DeepEqual(sym.mat.Data, m.mat.Data) {
+			t.Errorf("unexpected data slice mismatch for test %d: got: %v want: %v", i, sym.mat.Data, m.mat.Data)
+		}
+	}
+
+	matched, _ := panicsWith(func() { NewSymDense(3, []float32{1, 2}) }, ErrShape)
+	if !matched {
+		t.Error("expected panic for invalid data slice length")
+	}
+}
+
+func TestSymAtSet(t *testing.T) {
+	sym := &SymDense{
+		mat: blas32.Symmetric{
+			N:      3,
+			Stride: 3,
+			Uplo:   blas.Upper,
+			Data:   []float32{1, 2, 3, 4, 5, 6, 7, 8, 9},
+		},
+		cap: 3,
+	}
+	rows, cols := sym.Dims()
+
+	// Check At out of bounds
+	for _, row := range []int{-1, rows, rows + 1} {
+		panicked, message := panics(func() { sym.At(row, 0) })
+		if !panicked || message != ErrRowAccess.Error() {
+			t.Errorf("expected panic for invalid row access N=%d r=%d", rows, row)
+		}
+	}
+	for _, col := range []int{-1, cols, cols + 1} {
+		panicked, message := panics(func() { sym.At(0, col) })
+		if !panicked || message != ErrColAccess.Error() {
+			t.Errorf("expected panic for invalid column access N=%d c=%d", cols, col)
+		}
+	}
+
+	for _, st := range []struct {
+		row, col  int
+		orig, new float32
+	}{
+		{row: 1, col: 2, orig: 6, new: 15},
+		{row: 2, col: 1, orig: 15, new: 12},
+	} {
+		if e := sym.At(st.row, st.col); e != st.orig {
+			t.Errorf("unexpected value for At(%d, %d): got: %v want: %v", st.row, st.col, e, st.orig)
+		}
+		if e := sym.At(st.col, st.row); e != st.orig {
+			t.Errorf("unexpected value for At(%d, %d): got: %v want: %v", st.col, st.row, e, st.orig)
+		}
+		sym.SetSym(st.row, st.col, st.new)
+		if e := sym.At(st.row, st.col); e != st.new {
+			t.Errorf("unexpected value for At(%d, %d) after SetSym(%[1]d, %[2]d, %[4]v): got: %[3]v want: %[4]v", st.row, st.col, e, st.new)
+		}
+		if e := sym.At(st.col, st.row); e != st.new {
+			t.Errorf("unexpected value for At(%d, %d) after SetSym(%[2]d, %[1]d, %[4]v): got: %[3]v want: %[4]v", st.col, st.row, e, st.new)
+		}
+	}
+}
+
+func TestSymRankK(t *testing.T) {
+	for _, test := range []struct {
+		n, k        int
+		alpha, beta float32
+	}{
+		{1, 1, 1, 0},
+		{3, 2, 1, 1},
+		{4, 6, -0.5, 2},
+		{5, 1, 2, 0.5},
+	} {
+		a := NewDense(test.n, test.k, nil)
+		randomSlice(a.mat.Data)
+		init := NewDense(test.n, test.n, nil)
+		randomSlice(init.mat.Data)
+		var initSym Dense
+		initSym.Add(init, init.T())
+
+		// want = beta*(init + init^T) + alpha * A * A^T.
+		var want, aat Dense
+		aat.Mul(a, a.T())
+		aat.Scale(test.alpha, &aat)
+		want.Scale(test.beta, &initSym)
+		want.Add(&want, &aat)
+
+		for _, am := range []Matrix{a, DenseCopyOf(a.T()).T(), asBasicMatrix(a)} {
+			s := NewSymDense(test.n, DenseCopyOf(&initSym).mat.Data)
+			s.SymRankK(test.alpha, am, test.beta)
+			if !EqualApprox(s, &want, 1e-5) {
+				t.Errorf("unexpected result for n=%d k=%d with %T\ngot:\n%v\nwant:\n%v",
+					test.n, test.k, am, Formatted(s), Formatted(&want))
+			}
+		}
+
+		// An empty receiver starts from zero.
+		var s SymDense
+		s.SymRankK(test.alpha, a, test.beta)
+		if !EqualApprox(&s, &aat, 1e-5) {
+			t.Errorf("unexpected result for empty receiver n=%d k=%d", test.n, test.k)
+		}
+	}
+
+	// Streaming accumulation of the Gram matrix of column blocks.
+	a := NewDense(4, 9, nil)
+	randomSlice(a.mat.Data)
+	var acc SymDense
+	for j := 0; j < 9; j += 3 {
+		acc.SymRankK(1, a.Slice(0, 4, j, j+3), 1)
+	}
+	var gram Dense
+	gram.Mul(a, a.T())
+	if !EqualApprox(&acc, &gram, 1e-5) {
+		t.Errorf("unexpected accumulated Gram matrix")
+	}
+
+	matched, message := panicsWith(func() {
+		s := NewSymDense(3, nil)
+		s.SymRankK(1, NewDense(2, 2, nil), 1)
+	}, ErrShape)
+	if !matched {
+		t.Errorf("expected shape panic: got: %q", message)
+	}
+}
+
+func TestSymRankTwo(t *testing.T) {
+	for _, n := range []int{1, 2, 5, 10} {
+		for _, alpha := range []float32{0, 1, -2.5} {
+			xd := make([]float32, n)
+			randomSlice(xd)
+			yd := make([]float32, n)
+			randomSlice(yd)
+			init := NewDense(n, n, nil)
+			randomSlice(init.mat.Data)
+			var initSym Dense
+			initSym.Add(init, init.T())
+
+			want := DenseCopyOf(&initSym)
+			for i := 0; i < n; i++ {
+				for j := 0; j < n; j++ {
+					want.Set(i, j, want.At(i, j)+alpha*(xd[i]*yd[j]+yd[i]*xd[j]))
+				}
+			}
+
+			for _, vecs := range [][2]Vector{
+				{NewVecDense(n, xd), NewVecDense(n, yd)},
+				{&basicVector{xd}, &basicVector{yd}},
+			} {
+				s := NewSymDense(n, DenseCopyOf(&initSym).mat.Data)
+				s.SymRankTwo(alpha, vecs[0], vecs[1])
+				if !EqualApprox(s, want, 1e-5) {
+					t.Errorf("unexpected result for n=%d alpha=%v with %T\ngot:\n%v\nwant:\n%v",
+						n, alpha, vecs[0], Formatted(s), Formatted(want))
+				}
+			}
+
+			// Strided vectors.
+			xs := NewDense(n, 2, nil)
+			ys := NewDense(n, 3, nil)
+			for i := 0; i < n; i++ {
+				xs.Set(i, 1, xd[i])
+				ys.Set(i, 2, yd[i])
+			}
+			s := NewSymDense(n, DenseCopyOf(&initSym).mat.Data)
+			s.SymRankTwo(alpha, xs.ColView(1), ys.ColView(2))
+			if !EqualApprox(s, want, 1e-5) {
+				t.Errorf("unexpected result for strided vectors n=%d alpha=%v", n, alpha)
+			}
+		}
+	}
+
+	for _, fn := range []func(){
+		func() { NewSymDense(3, nil).SymRankTwo(1, NewVecDense(2, nil), NewVecDense(2, nil)) },
+		func() { NewSymDense(3, nil).SymRankTwo(1, NewVecDense(3, nil), NewVecDense(2, nil)) },
+	} {
+		matched, message := panicsWith(fn, ErrShape)
+		if !matched {
+			t.Errorf("expected shape panic: got: %q", message)
+		}
+	}
+}
+
+func TestDenseAsSymmetric(t *testing.T) {
+	m := NewDense(3, 3, []float32{
+		1, 2, 3,
+		2, 4, 5,
+		3, 5, 6,
+	})
+	s, err := m.AsSymmetric()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := s.Symmetric(); n != 3 {
+		t.Errorf("unexpected size: got: %d want: 3", n)
+	}
+	if !Equal(s, m) {
+		t.Errorf("unexpected view:\n%v", Formatted(s))
+	}
+
+	// The view shares storage with the receiver.
+	s.SetSym(0, 2, 10)
+	if v := m.At(0, 2); v != 10 {
+		t.Errorf("change to view not reflected in receiver: got: %v want: 10", v)
+	}
+	m.Set(1, 2, -1)
+	if v := s.At(2, 1); v != -1 {
+		t.Errorf("change to receiver not reflected in view: got: %v want: -1", v)
+	}
+
+	// Views of submatrices use the stride of the receiver.
+	big := NewDense(4, 5, nil)
+	for i := range big.mat.Data {
+		big.mat.Data[i] = float32(i)
+	}
+	sub := big.Slice(1, 3, 2, 4).(*Dense)
+	s, err = sub.AsSymmetric()
+	if err != nil {
+		t.Fatalf("unexpected error for submatrix: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		for j := i; j < 2; j++ {
+			if s.At(i, j) != sub.At(i, j) || s.At(j, i) != sub.At(i, j) {
+				t.Errorf("unexpected element of submatrix view at (%d, %d): got: %v want: %v", i, j, s.At(i, j), sub.At(i, j))
+			}
+		}
+	}
+
+	_, err = NewDense(2, 3, nil).AsSymmetric()
+	if err != ErrSquare {
+		t.Errorf("unexpected error for non-square matrix: got: %v want: %v", err, ErrSquare)
+	}
+}
@@ -117,7 +117,7 @@ func NewTriDense(n int, kind TriKind, data []float32) *TriDense {
 		panic("mat: negative dimension")
 	}
 	if data != nil && len(data) != n*n {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{len(data), 1}, Want: [2]int{n * n, 1}})
 	}
 	if data == nil {
 		data = make([]float32, n*n)
@@ -254,7 +254,7 @@ func (t *TriDense) reuseAs(n int, kind TriKind) {
 		return
 	}
 	if t.mat.N != n {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{t.mat.N, t.mat.N}, Want: [2]int{n, n}})
 	}
 	if t.mat.Uplo != ul {
 		panic(ErrTriangle)
@@ -345,7 +345,7 @@ func (t *TriDense) MulTri(a, b Triangular) {
 	n, kind := a.Triangle()
 	nb, kindb := b.Triangle()
 	if n != nb {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{nb, nb}, Want: [2]int{n, n}})
 	}
 	if kind != kindb {
 		panic(ErrTriangle)
@@ -58,8 +58,8 @@ func TestNewTriangular(t *testing.T) {
 	}
 
 	for _, kind := range []TriKind{Lower, Upper} {
-		panicked, message := panics(func() { NewTriDense(3, kind, []float32{1, 2}) })
-		if !panicked || message != ErrShape.Error() {
+		matched, _ := panicsWith(func() { NewTriDense(3, kind, []float32{1, 2}) }, ErrShape)
+		if !matched {
 			t.Errorf("expected panic for invalid data slice length for upper=%t", kind)
 		}
 	}
@@ -313,3 +313,56 @@ func TestScaleTri(t *testing.T) {
 		testOneInput(t, "ScaleTriLower", NewTriDense(3, Lower, nil), method, denseComparison, legalTypeTriLower, isSquare, 1e-14)
 	}
 }
+
+func TestDenseTriView(t *testing.T) {
+	for _, kind := range []TriKind{Upper, Lower} {
+		m := NewDense(3, 3, []float32{
+			1, 2, 3,
+			4, 5, 6,
+			7, 8, 9,
+		})
+		tri, err := m.TriView(kind)
+		if err != nil {
+			t.Fatalf("unexpected error for kind %v: %v", kind, err)
+		}
+		n, gotKind := tri.Triangle()
+		if n != 3 || gotKind != kind {
+			t.Errorf("unexpected triangle: got: (%d, %v) want: (3, %v)", n, gotKind, kind)
+		}
+		for i := 0; i < 3; i++ {
+			for j := 0; j < 3; j++ {
+				want := m.At(i, j)
+				if (kind == Upper && j < i) || (kind == Lower && j > i) {
+					want = 0
+				}
+				if got := tri.At(i, j); got != want {
+					t.Errorf("unexpected element for kind %v at (%d, %d): got: %v want: %v", kind, i, j, got, want)
+				}
+			}
+		}
+
+		// The view shares storage with the receiver.
+		tri.SetTri(1, 1, -5)
+		if v := m.At(1, 1); v != -5 {
+			t.Errorf("change to view not reflected in receiver for kind %v: got: %v want: -5", kind, v)
+		}
+		m.Set(2, 2, 20)
+		if v := tri.At(2, 2); v != 20 {
+			t.Errorf("change to receiver not reflected in view for kind %v: got: %v want: 20", kind, v)
+		}
+
+		// A triangular solve against the view.
+		x := NewVecDense(3, []float32{1, 2, 3})
+		var got VecDense
+		got.MulVec(tri, x)
+		blas32.Trsv(blas.NoTrans, tri.RawTriangular(), got.RawVector())
+		if !EqualApprox(&got, x, 1e-5) {
+			t.Errorf("unexpected solution for kind %v: got: %v want: %v", kind, got.RawVector().Data, x.RawVector().Data)
+		}
+	}
+
+	_, err := NewDense(2, 3, nil).TriView(Upper)
+	if err != ErrSquare {
+		t.Errorf("unexpected error for non-square matrix: got: %v want: %v", err, ErrSquare)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+// SolveTridiag solves the tridiagonal system of equations
+//  A * x = b
+// placing x into dst, where the n×n matrix A has sub-diagonal lower,
+// diagonal diag and super-diagonal upper, so that
+//  A[i, i-1] = lower[i-1], A[i, i] = diag[i], A[i, i+1] = upper[i]
+// diag and b must have length n and lower and upper must have length n-1,
+// otherwise SolveTridiag will panic with ErrShape. If dst is empty it is
+// resized to length n, otherwise it must have length n. dst may share
+// storage with b. None of the input slices are modified.
+//
+// The system is solved with the Thomas algorithm, Gaussian elimination
+// without pivoting specialized to tridiagonal matrices, which requires
+// O(n) time and storage. Since no pivoting is performed the algorithm is
+// only guaranteed to be stable for diagonally dominant or symmetric
+// positive definite A. SolveTridiag returns ErrSingular if a zero pivot
+// is encountered, in which case the contents of dst are unspecified.
+func SolveTridiag(dst *VecDense, lower, diag, upper, b []float32) error {
+	n := len(diag)
+	if n == 0 {
+		panic(ErrZeroLength)
+	}
+	if len(b) != n {
+		panic(ShapeError{Got: [2]int{len(b), 1}, Want: [2]int{n, 1}})
+	}
+	if len(lower) != n-1 {
+		panic(ShapeError{Got: [2]int{len(lower), 1}, Want: [2]int{n - 1, 1}})
+	}
+	if len(upper) != n-1 {
+		panic(ShapeError{Got: [2]int{len(upper), 1}, Want: [2]int{n - 1, 1}})
+	}
+	dst.reuseAs(n)
+
+	// Forward elimination, holding the modified super-diagonal
+	// in work and the modified right-hand side in dst.
+	work := getFloats(n, false)
+	defer putFloats(work)
+	x := dst.mat.Data
+	inc := dst.mat.Inc
+	den := diag[0]
+	if den == 0 {
+		return ErrSingular
+	}
+	x[0] = b[0] / den
+	for i := 1; i < n; i++ {
+		work[i-1] = upper[i-1] / den
+		den = diag[i] - lower[i-1]*work[i-1]
+		if den == 0 {
+			return ErrSingular
+		}
+		x[i*inc] = (b[i] - lower[i-1]*x[(i-1)*inc]) / den
+	}
+
+	// Back substitution.
+	for i := n - 2; i >= 0; i-- {
+		x[i*inc] -= work[i] * x[(i+1)*inc]
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestSolveTridiag(t *testing.T) {
+	for i, test := range []struct {
+		lower, diag, upper []float32
+		b                  []float32
+		want               []float32
+	}{
+		{
+			diag: []float32{4},
+			b:    []float32{2},
+			want: []float32{0.5},
+		},
+		{
+			// The 1-D Poisson matrix tridiag(-1, 2, -1).
+			lower: []float32{-1, -1, -1},
+			diag:  []float32{2, 2, 2, 2},
+			upper: []float32{-1, -1, -1},
+			b:     []float32{1, 0, 0, 1},
+			want:  []float32{1, 1, 1, 1},
+		},
+		{
+			lower: []float32{1, 2},
+			diag:  []float32{3, 4, 5},
+			upper: []float32{1, 1},
+			b:     []float32{5, 12, 19},
+			want:  []float32{1, 2, 3},
+		},
+	} {
+		var got VecDense
+		err := SolveTridiag(&got, test.lower, test.diag, test.upper, test.b)
+		if err != nil {
+			t.Errorf("unexpected error for test %d: %v", i, err)
+			continue
+		}
+		want := NewVecDense(len(test.want), test.want)
+		if !EqualApprox(&got, want, 1e-6) {
+			t.Errorf("unexpected result for test %d: got: %v want: %v", i, got.RawVector().Data, test.want)
+		}
+	}
+
+	// Compare against the dense product for a diagonally dominant system.
+	rnd := rand.New(rand.NewSource(1))
+	const n = 50
+	lower := make([]float32, n-1)
+	diag := make([]float32, n)
+	upper := make([]float32, n-1)
+	a := NewDense(n, n, nil)
+	for i := range diag {
+		diag[i] = 4 + rnd.Float32()
+		a.Set(i, i, diag[i])
+		if i < n-1 {
+			lower[i] = rnd.Float32() - 0.5
+			upper[i] = rnd.Float32() - 0.5
+			a.Set(i+1, i, lower[i])
+			a.Set(i, i+1, upper[i])
+		}
+	}
+	b := NewVecDense(n, randSlice(n, rnd))
+	var x, ax VecDense
+	err := SolveTridiag(&x, lower, diag, upper, b.RawVector().Data)
+	if err != nil {
+		t.Fatalf("unexpected error for random system: %v", err)
+	}
+	ax.MulVec(a, &x)
+	if !EqualApprox(&ax, b, 1e-5) {
+		t.Errorf("unexpected residual for random system")
+	}
+
+	// A zero pivot arises at the second step.
+	x.Reset()
+	err = SolveTridiag(&x, []float32{1}, []float32{1, 1}, []float32{1}, []float32{1, 2})
+	if err != ErrSingular {
+		t.Errorf("unexpected error for zero pivot: got: %v want: %v", err, ErrSingular)
+	}
+	x.Reset()
+	err = SolveTridiag(&x, []float32{1}, []float32{0, 1}, []float32{1}, []float32{1, 2})
+	if err != ErrSingular {
+		t.Errorf("unexpected error for zero leading pivot: got: %v want: %v", err, ErrSingular)
+	}
+
+	matched, message := panicsWith(func() {
+		var x VecDense
+		SolveTridiag(&x, []float32{1}, []float32{1, 2, 3}, []float32{1, 1}, []float32{1, 2, 3})
+	}, ErrShape)
+	if !matched {
+		t.Errorf("expected shape panic: got: %q want: %q", message, ErrShape)
+	}
+}
@@ -5,7 +5,11 @@
 package mat32
 
 import (
+	"encoding/binary"
+	"math"
+
 	"github.com/arjunsk/mat32/internal/asm/f32"
+	"github.com/chewxy/math32"
 	"gonum.org/v1/gonum/blas"
 	"gonum.org/v1/gonum/blas/blas32"
 )
@@ -64,6 +68,16 @@ func (t TransposeVec) TVec() Vector {
 	return t.Vector
 }
 
+// ToDense returns a newly allocated VecDense holding the elements of the
+// Vector field with unit increment. Since a VecDense is always a column
+// vector, the transposed row vector is represented by the same sequence of
+// elements; DenseCopyOf may be used to obtain it as a 1×n Dense.
+func (t TransposeVec) ToDense() *VecDense {
+	v := NewVecDense(t.Len(), nil)
+	v.CopyVec(t.Vector)
+	return v
+}
+
 // Untranspose returns the Vector field.
 func (t TransposeVec) Untranspose() Matrix {
 	return t.Vector
@@ -91,7 +105,7 @@ func NewVecDense(n int, data []float32) *VecDense {
 		panic("mat: negative dimension")
 	}
 	if len(data) != n && data != nil {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{len(data), 1}, Want: [2]int{n, 1}})
 	}
 	if data == nil {
 		data = make([]float32, n)
@@ -195,6 +209,45 @@ func (v *VecDense) CloneVec(a Vector) {
 	}
 }
 
+// UnsafeVecDense returns a VecDense of length len(data) that adopts data as
+// its backing slice without copying, so changes to the elements of the
+// returned VecDense are reflected in data and vice versa. No conversion of
+// the elements is performed. Because only a []float32 is accepted, the
+// compiler rejects attempts to pass a slice of any other element type, such
+// as a []float64, which must not be reinterpreted as float32 data with the
+// unsafe package since the bit patterns of the two types differ. Callers
+// holding float64 data must copy and convert it.
+//
+// UnsafeVecDense is equivalent to NewVecDense(len(data), data); the name is
+// a reminder that the returned vector aliases data.
+func UnsafeVecDense(data []float32) *VecDense {
+	return NewVecDense(len(data), data)
+}
+
+// OneHot returns a new vector of length n with a one at index and zeros
+// elsewhere. OneHot panics with ErrVectorAccess if index is not in [0, n).
+func OneHot(n, index int) *VecDense {
+	v := NewVecDense(n, nil)
+	v.SetVec(index, 1)
+	return v
+}
+
+// OneHotMulti returns a new vector of length n with ones at each of the
+// given indices and zeros elsewhere. Repeated indices are set once.
+// OneHotMulti panics with ErrVectorAccess if any index is not in [0, n).
+func OneHotMulti(n int, indices []int) *VecDense {
+	v := NewVecDense(n, nil)
+	for _, idx := range indices {
+		if uint(idx) >= uint(n) {
+			panic(ErrVectorAccess)
+		}
+	}
+	for _, idx := range indices {
+		v.mat.Data[idx] = 1
+	}
+	return v
+}
+
 // VecDenseCopyOf returns a newly allocated copy of the elements of a.
 func VecDenseCopyOf(a Vector) *VecDense {
 	v := &VecDense{}
@@ -256,6 +309,120 @@ func (v *VecDense) ScaleVec(alpha float32, a Vector) {
 	}
 }
 
+// AbsVec computes the absolute value of each element of a, placing the
+// result in the receiver. The sign bit is cleared, so -0 becomes +0 and NaN
+// elements remain NaN.
+func (v *VecDense) AbsVec(a Vector) {
+	v.mapVec(math32.Abs, a)
+}
+
+// SignVec computes the sign of each element of a, placing the result in the
+// receiver. Positive elements map to 1, negative elements to -1 and both
+// +0 and -0 to 0. NaN elements remain NaN.
+func (v *VecDense) SignVec(a Vector) {
+	v.mapVec(sign, a)
+}
+
+// RoundVec rounds each element of a to the nearest integer, placing the
+// result in the receiver. Halfway cases are rounded to even, following the
+// IEEE 754 default, so 0.5 rounds to 0 and 1.5 and 2.5 round to 2.
+func (v *VecDense) RoundVec(a Vector) {
+	v.mapVec(roundToEven, a)
+}
+
+// roundToEven returns x rounded to the nearest integer, rounding half to
+// even. float32 values are exactly representable as float64, so rounding
+// in float64 gives the correctly rounded float32 result.
+func roundToEven(x float32) float32 {
+	return float32(math.RoundToEven(float64(x)))
+}
+
+// FloorVec computes the greatest integer value less than or equal to each
+// element of a, placing the result in the receiver.
+func (v *VecDense) FloorVec(a Vector) {
+	v.mapVec(math32.Floor, a)
+}
+
+// CeilVec computes the least integer value greater than or equal to each
+// element of a, placing the result in the receiver.
+func (v *VecDense) CeilVec(a Vector) {
+	v.mapVec(math32.Ceil, a)
+}
+
+// mapVec applies fn to each element of a, placing the result in the
+// receiver. The receiver may be a.
+func (v *VecDense) mapVec(fn func(float32) float32, a Vector) {
+	n := a.Len()
+
+	if v == a {
+		for i := 0; i < n; i++ {
+			v.mat.Data[i*v.mat.Inc] = fn(v.mat.Data[i*v.mat.Inc])
+		}
+		return
+	}
+
+	v.reuseAs(n)
+
+	if rv, ok := a.(RawVectorer); ok {
+		mat := rv.RawVector()
+		v.checkOverlap(mat)
+		for i := 0; i < n; i++ {
+			v.mat.Data[i*v.mat.Inc] = fn(mat.Data[i*mat.Inc])
+		}
+		return
+	}
+
+	for i := 0; i < n; i++ {
+		v.setVec(i, fn(a.AtVec(i)))
+	}
+}
+
+// RollVec circularly shifts the elements of a by shift positions, placing
+// the result in the receiver,
+//  v[(i+shift) mod n] = a[i]
+// A positive shift moves elements toward higher indices and a negative
+// shift toward lower indices, with elements shifted past either end
+// wrapping around. Shifts of any magnitude are reduced modulo the length.
+// The receiver may be a. RollVec panics with ErrZeroLength if a is empty.
+func (v *VecDense) RollVec(a Vector, shift int) {
+	n := a.Len()
+	if n == 0 {
+		panic(ErrZeroLength)
+	}
+	shift %= n
+	if shift < 0 {
+		shift += n
+	}
+
+	if v == a {
+		// Rotate in place by three reversals.
+		v.reverse(0, n)
+		v.reverse(0, shift)
+		v.reverse(shift, n)
+		return
+	}
+
+	v.reuseAs(n)
+	if rv, ok := a.(RawVectorer); ok {
+		v.checkOverlap(rv.RawVector())
+	}
+	for i := 0; i < n; i++ {
+		j := i + shift
+		if j >= n {
+			j -= n
+		}
+		v.setVec(j, a.AtVec(i))
+	}
+}
+
+// reverse reverses the order of the elements of v in [i, j).
+func (v *VecDense) reverse(i, j int) {
+	inc := v.mat.Inc
+	for j--; i < j; i, j = i+1, j-1 {
+		v.mat.Data[i*inc], v.mat.Data[j*inc] = v.mat.Data[j*inc], v.mat.Data[i*inc]
+	}
+}
+
 // AddScaledVec adds the vectors a and alpha*b, placing the result in the receiver.
 func (v *VecDense) AddScaledVec(a Vector, alpha float32, b Vector) {
 	if alpha == 1 {
@@ -271,7 +438,7 @@ func (v *VecDense) AddScaledVec(a Vector, alpha float32, b Vector) {
 	br := b.Len()
 
 	if ar != br {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{br, 1}, Want: [2]int{ar, 1}})
 	}
 
 	var amat, bmat blas32.Vector
@@ -335,7 +502,7 @@ func (v *VecDense) AddVec(a, b Vector) {
 	br := b.Len()
 
 	if ar != br {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{br, 1}, Want: [2]int{ar, 1}})
 	}
 
 	v.reuseAs(ar)
@@ -372,13 +539,218 @@ func (v *VecDense) AddVec(a, b Vector) {
 	}
 }
 
+// AddVecMasked adds the vectors a and b element-wise where mask is non-zero,
+// placing the result in the receiver. Where mask is zero the corresponding
+// element of a is copied into the receiver,
+//  v[i] = a[i] + b[i]  if mask[i] != 0
+//  v[i] = a[i]         otherwise
+// AddVecMasked panics with ErrShape if a, b and mask do not have the same
+// length.
+func (v *VecDense) AddVecMasked(a, b Vector, mask Vector) {
+	n := a.Len()
+	if b.Len() != n {
+		panic(ShapeError{Got: [2]int{b.Len(), 1}, Want: [2]int{n, 1}})
+	}
+	if mask.Len() != n {
+		panic(ShapeError{Got: [2]int{mask.Len(), 1}, Want: [2]int{n, 1}})
+	}
+
+	v.reuseAs(n)
+	for _, x := range [3]Vector{a, b, mask} {
+		if v == x {
+			continue
+		}
+		if rv, ok := x.(RawVectorer); ok {
+			v.checkOverlap(rv.RawVector())
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		f := a.AtVec(i)
+		if mask.AtVec(i) != 0 {
+			f += b.AtVec(i)
+		}
+		v.setVec(i, f)
+	}
+}
+
+// Map2 applies the binary function fn element-wise to x and y, placing
+// the result in the receiver,
+//  v[i] = fn(x[i], y[i])
+// The receiver may be x or y. Map2 panics with ErrShape if x and y do not
+// have the same length.
+func (v *VecDense) Map2(fn func(a, b float32) float32, x, y Vector) {
+	n := x.Len()
+	if y.Len() != n {
+		panic(ShapeError{Got: [2]int{y.Len(), 1}, Want: [2]int{n, 1}})
+	}
+
+	v.reuseAs(n)
+	for _, a := range [2]Vector{x, y} {
+		if v == a {
+			continue
+		}
+		if rv, ok := a.(RawVectorer); ok {
+			v.checkOverlap(rv.RawVector())
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		v.setVec(i, fn(x.AtVec(i), y.AtVec(i)))
+	}
+}
+
+// Reduce folds the elements of the receiver from left to right with fn,
+// starting from init,
+//  acc = fn(...fn(fn(init, v[0]), v[1])..., v[n-1])
+// and returns the final accumulator. The order of application is fixed, so
+// fn need not be associative. An empty receiver returns init.
+func (v *VecDense) Reduce(init float32, fn func(acc, x float32) float32) float32 {
+	acc := init
+	for i := 0; i < v.n; i++ {
+		acc = fn(acc, v.mat.Data[i*v.mat.Inc])
+	}
+	return acc
+}
+
+// Dot returns the sum of the element-wise product of the receiver and b.
+// It is equivalent to Dot(v, b), and panics with ErrShape if the lengths
+// of the receiver and b differ.
+func (v *VecDense) Dot(b Vector) float32 {
+	if b.Len() != v.n {
+		panic(ShapeError{Got: [2]int{b.Len(), 1}, Want: [2]int{v.n, 1}})
+	}
+	if rv, ok := b.(RawVectorer); ok {
+		return blas32.Dot(v.n, v.mat, rv.RawVector())
+	}
+	var sum float32
+	for i := 0; i < v.n; i++ {
+		sum += v.mat.Data[i*v.mat.Inc] * b.AtVec(i)
+	}
+	return sum
+}
+
+// Distance returns the distance between the receiver and b under the given
+// metric. It is equivalent to the package-level Distance function, but
+// panics with ErrShape if the lengths of the receiver and b differ and with
+// ErrMetric if metric is not a known Metric.
+func (v *VecDense) Distance(b Vector, metric Metric) float32 {
+	d, err := Distance(metric, v, b)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// DotMatrix places the dot product of the receiver with each row of m
+// into dst,
+//  dst[i] = v · m[i,:]
+// This is the matrix-vector product m * v computed with a single call to
+// Gemv, and is considerably faster than separate calls to Dot for each
+// row. DotMatrix panics with ErrShape if the length of the receiver is not
+// the number of columns of m, and with ErrSliceLengthMismatch if the length
+// of dst is not the number of rows of m.
+func (v *VecDense) DotMatrix(dst []float32, m *Dense) {
+	r, c := m.Dims()
+	if v.n != c {
+		panic(ShapeError{Got: [2]int{v.n, 1}, Want: [2]int{c, 1}})
+	}
+	if len(dst) != r {
+		panic(ErrSliceLengthMismatch)
+	}
+	if c == 0 {
+		zero(dst)
+		return
+	}
+	blas32.Gemv(blas.NoTrans, 1, m.mat, v.mat, 0, blas32.Vector{Inc: 1, Data: dst})
+}
+
+// Gather places the elements of the receiver at the given indices into dst,
+//  dst[k] = v[indices[k]]
+// Indices may be repeated and need not be ordered. If dst is empty it is
+// resized to len(indices), otherwise it must have length len(indices).
+// Gather panics with ErrVectorAccess if any index is out of range.
+func (v *VecDense) Gather(dst *VecDense, indices []int) {
+	for _, idx := range indices {
+		if idx < 0 || idx >= v.n {
+			panic(ErrVectorAccess)
+		}
+	}
+	dst.reuseAs(len(indices))
+	if dst == v {
+		var restore func()
+		dst, restore = dst.isolatedWorkspace(v)
+		defer restore()
+	} else {
+		dst.checkOverlap(v.mat)
+	}
+
+	if v.mat.Inc == 1 && dst.mat.Inc == 1 {
+		for k, idx := range indices {
+			dst.mat.Data[k] = v.mat.Data[idx]
+		}
+		return
+	}
+	for k, idx := range indices {
+		dst.mat.Data[k*dst.mat.Inc] = v.mat.Data[idx*v.mat.Inc]
+	}
+}
+
+// Scatter writes the elements of src into the receiver at the given indices,
+//  v[indices[k]] = src[k]
+// If an index is repeated, the last corresponding element of src is kept.
+// See ScatterAdd for accumulating into repeated indices.
+//
+// Scatter panics with ErrShape if len(indices) does not equal the length of
+// src and with ErrVectorAccess if any index is out of range.
+func (v *VecDense) Scatter(indices []int, src Vector) {
+	v.scatter(indices, src, false)
+}
+
+// ScatterAdd adds the elements of src into the receiver at the given indices,
+//  v[indices[k]] += src[k]
+// Contributions to repeated indices are accumulated.
+//
+// ScatterAdd panics with ErrShape if len(indices) does not equal the length
+// of src and with ErrVectorAccess if any index is out of range.
+func (v *VecDense) ScatterAdd(indices []int, src Vector) {
+	v.scatter(indices, src, true)
+}
+
+func (v *VecDense) scatter(indices []int, src Vector, add bool) {
+	if len(indices) != src.Len() {
+		panic(ShapeError{Got: [2]int{src.Len(), 1}, Want: [2]int{len(indices), 1}})
+	}
+	for _, idx := range indices {
+		if idx < 0 || idx >= v.n {
+			panic(ErrVectorAccess)
+		}
+	}
+	if src == Vector(v) {
+		w := getWorkspaceVec(v.n, false)
+		defer putWorkspaceVec(w)
+		w.CopyVec(v)
+		src = w
+	} else if rv, ok := src.(RawVectorer); ok {
+		v.checkOverlap(rv.RawVector())
+	}
+
+	for k, idx := range indices {
+		f := src.AtVec(k)
+		if add {
+			f += v.mat.Data[idx*v.mat.Inc]
+		}
+		v.mat.Data[idx*v.mat.Inc] = f
+	}
+}
+
 // SubVec subtracts the vector b from a, placing the result in the receiver.
 func (v *VecDense) SubVec(a, b Vector) {
 	ar := a.Len()
 	br := b.Len()
 
 	if ar != br {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{br, 1}, Want: [2]int{ar, 1}})
 	}
 
 	v.reuseAs(ar)
@@ -422,7 +794,7 @@ func (v *VecDense) MulElemVec(a, b Vector) {
 	br := b.Len()
 
 	if ar != br {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{br, 1}, Want: [2]int{ar, 1}})
 	}
 
 	v.reuseAs(ar)
@@ -471,7 +843,7 @@ func (v *VecDense) DivElemVec(a, b Vector) {
 	br := b.Len()
 
 	if ar != br {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{br, 1}, Want: [2]int{ar, 1}})
 	}
 
 	v.reuseAs(ar)
@@ -512,6 +884,59 @@ func (v *VecDense) DivElemVec(a, b Vector) {
 	}
 }
 
+// DivElemVecSafe performs element-wise division of a by b, placing the result
+// in the receiver. Elements where b is zero are set to onZero rather than
+// the infinity or NaN that would result from the division.
+func (v *VecDense) DivElemVecSafe(a, b Vector, onZero float32) {
+	ar := a.Len()
+	br := b.Len()
+
+	if ar != br {
+		panic(ShapeError{Got: [2]int{br, 1}, Want: [2]int{ar, 1}})
+	}
+
+	v.reuseAs(ar)
+
+	aU, _ := untranspose(a)
+	bU, _ := untranspose(b)
+
+	if arv, ok := aU.(RawVectorer); ok {
+		if brv, ok := bU.(RawVectorer); ok {
+			amat := arv.RawVector()
+			bmat := brv.RawVector()
+
+			if v != a {
+				v.checkOverlap(amat)
+			}
+			if v != b {
+				v.checkOverlap(bmat)
+			}
+
+			var ia, ib int
+			for i := 0; i < ar; i++ {
+				d := bmat.Data[ib]
+				if d == 0 {
+					v.setVec(i, onZero)
+				} else {
+					v.setVec(i, amat.Data[ia]/d)
+				}
+				ia += amat.Inc
+				ib += bmat.Inc
+			}
+			return
+		}
+	}
+
+	for i := 0; i < ar; i++ {
+		d := b.AtVec(i)
+		if d == 0 {
+			v.setVec(i, onZero)
+		} else {
+			v.setVec(i, a.AtVec(i)/d)
+		}
+	}
+}
+
 // MulVec computes a * b. The result is stored into the receiver.
 // MulVec panics if the number of columns in a does not equal the number of rows in b
 // or if the number of columns in b does not equal 1.
@@ -519,7 +944,7 @@ func (v *VecDense) MulVec(a Matrix, b Vector) {
 	r, c := a.Dims()
 	br, bc := b.Dims()
 	if c != br || bc != 1 {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{br, bc}, Want: [2]int{c, 1}})
 	}
 
 	aU, trans := untranspose(a)
@@ -589,6 +1014,15 @@ func (v *VecDense) MulVec(a Matrix, b Vector) {
 			ta = blas.Trans
 		}
 		blas32.Trmv(ta, amat, v.mat)
+	case RawSymmetricer:
+		if fast {
+			amat := aU.RawSymmetric()
+			// We don't know that a is a *SymDense, so make
+			// a temporary SymDense to check overlap.
+			(&SymDense{mat: amat}).checkOverlap(v.asGeneral())
+			blas32.Symv(1, amat, bmat, 0, v.mat)
+			return
+		}
 	case RawMatrixer:
 		if fast {
 			amat := aU.RawMatrix()
@@ -624,6 +1058,101 @@ func (v *VecDense) MulVec(a Matrix, b Vector) {
 	}
 }
 
+// Gemv computes
+//  y = alpha * op(a) * x + beta * y
+// where op(a) is aᵀ if trans is true and a otherwise. This is the BLAS
+// level 2 general matrix-vector operation, dispatched to blas32.Gemv when
+// a and x provide raw storage. As in BLAS, when beta is zero y need not be
+// initialized, so NaN elements of y do not propagate to the result. The
+// vector y may be x.
+//
+// If y is empty it is resized to the number of rows of op(a) and treated as
+// zero. Gemv panics with ErrShape if the length of x is not the number of
+// columns of op(a), or if y is non-empty and its length is not the number
+// of rows of op(a).
+func Gemv(trans bool, alpha float32, a Matrix, x Vector, beta float32, y *VecDense) {
+	r, c := a.Dims()
+	if trans {
+		r, c = c, r
+	}
+	if n := x.Len(); n != c {
+		panic(ShapeError{Got: [2]int{n, 1}, Want: [2]int{c, 1}})
+	}
+	if y.IsZero() {
+		y.reuseAs(r)
+		beta = 0
+	} else if y.n != r {
+		panic(ShapeError{Got: [2]int{y.n, 1}, Want: [2]int{r, 1}})
+	}
+
+	aU, aTrans := untranspose(a)
+	if rm, ok := aU.(RawMatrixer); ok {
+		if rv, ok := x.(RawVectorer); ok {
+			amat := rm.RawMatrix()
+			xmat := rv.RawVector()
+			// We don't know that a is a *Dense, so make
+			// a temporary Dense to check overlap.
+			(&Dense{mat: amat}).checkOverlap(y.asGeneral())
+			if y == x || (xmat.Inc == y.mat.Inc && offset(y.mat.Data[:1], xmat.Data[:1]) == 0) {
+				// blas32.Gemv reads x while writing y,
+				// so take a copy of x when y is x.
+				w := getWorkspaceVec(c, false)
+				defer putWorkspaceVec(w)
+				w.CopyVec(x)
+				xmat = w.mat
+			} else {
+				y.checkOverlap(xmat)
+			}
+			t := blas.NoTrans
+			if trans != aTrans {
+				t = blas.Trans
+			}
+			blas32.Gemv(t, alpha, amat, xmat, beta, y.mat)
+			return
+		}
+	}
+
+	// Form op(a) * x before updating y, since y may be x.
+	ax := getFloats(r, false)
+	defer putFloats(ax)
+	for i := range ax {
+		var f float32
+		for j := 0; j < c; j++ {
+			if trans {
+				f += a.At(j, i) * x.AtVec(j)
+			} else {
+				f += a.At(i, j) * x.AtVec(j)
+			}
+		}
+		ax[i] = f
+	}
+	for i, f := range ax {
+		if beta == 0 {
+			y.setVec(i, alpha*f)
+		} else {
+			y.setVec(i, alpha*f+beta*y.AtVec(i))
+		}
+	}
+}
+
+// Axpy computes
+//  y += alpha * x
+// in place over the raw slices x and y. It is the zero-wrapper fast path to
+// the unit-stride kernel underlying AddScaledVec, with no Vector dispatch,
+// overlap checks or allocation, for callers that already hold their data in
+// contiguous slices.
+//
+// Axpy panics with ErrShape if x and y have different lengths.
+func Axpy(alpha float32, x, y []float32) {
+	if len(x) != len(y) {
+		panic(ShapeError{Got: [2]int{len(y), 1}, Want: [2]int{len(x), 1}})
+	}
+	if alpha == 0 {
+		return
+	}
+	f32.AxpyUnitary(alpha, x, y)
+}
+
 // reuseAs resizes an empty vector to a r×1 vector,
 // or checks that a non-empty matrix is r×1.
 func (v *VecDense) reuseAs(r int) {
@@ -639,7 +1168,7 @@ func (v *VecDense) reuseAs(r int) {
 		return
 	}
 	if r != v.n {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{v.n, 1}, Want: [2]int{r, 1}})
 	}
 }
 
@@ -651,6 +1180,49 @@ func (v *VecDense) IsZero() bool {
 	return v.mat.Inc == 0
 }
 
+// IsFinite returns whether all elements of the receiver are finite, that
+// is neither NaN nor ±Inf.
+func (v *VecDense) IsFinite() bool {
+	for i := 0; i < v.n; i++ {
+		if !isFinite(v.mat.Data[i*v.mat.Inc]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Key returns a compact binary encoding of the length and element values
+// of the receiver, suitable for use as a map key when deduplicating
+// vectors. The key is not human-readable. Elements are read through the
+// receiver's increment, so views with different strides but equal elements
+// have equal keys.
+//
+// Keys compare the bit patterns of the elements, so they differ from Equal
+// in two respects: +0 and -0 give different keys, and NaN elements with the
+// same bit pattern give equal keys.
+func (v *VecDense) Key() string {
+	b := make([]byte, 4*v.n)
+	for i := 0; i < v.n; i++ {
+		binary.LittleEndian.PutUint32(b[4*i:], math.Float32bits(v.mat.Data[i*v.mat.Inc]))
+	}
+	return string(b)
+}
+
+// NormSafe returns the Euclidean norm of the receiver,
+//  sqrt(sum_i v_i^2)
+// computed as in the reference BLAS snrm2 by accumulating the sum of
+// squares scaled by the running maximum magnitude. Unlike a direct sum of
+// squares, the result does not overflow when the squares exceed the
+// float32 range and does not underflow to zero for vectors of subnormal
+// values. NormSafe returns NaN if any element is NaN and zero for an empty
+// receiver.
+func (v *VecDense) NormSafe() float32 {
+	if v.IsZero() {
+		return 0
+	}
+	return blas32.Nrm2(v.n, v.mat)
+}
+
 func (v *VecDense) isolatedWorkspace(a Vector) (n *VecDense, restore func()) {
 	l := a.Len()
 	if l == 0 {
@@ -694,7 +1266,7 @@ func (v *VecDense) ColViewOf(m RawMatrixer, j int) {
 		panic(ErrColAccess)
 	}
 	if !v.IsZero() && v.n != rm.Rows {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{v.n, 1}, Want: [2]int{rm.Rows, 1}})
 	}
 
 	v.mat.Inc = rm.Stride
@@ -712,7 +1284,7 @@ func (v *VecDense) RowViewOf(m RawMatrixer, i int) {
 		panic(ErrRowAccess)
 	}
 	if !v.IsZero() && v.n != rm.Cols {
-		panic(ErrShape)
+		panic(ShapeError{Got: [2]int{v.n, 1}, Want: [2]int{rm.Cols, 1}})
 	}
 
 	v.mat.Inc = 1
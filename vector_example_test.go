@@ -0,0 +1,35 @@
+// Copyright ©2017 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat32_test
+
+import (
+	"fmt"
+
+	mat "github.com/arjunsk/mat32"
+)
+
+func ExampleVecDense_Map2() {
+	// Initialize two vectors, x and y.
+	x := mat.NewVecDense(4, []float32{1, 5, 2, 8})
+	y := mat.NewVecDense(4, []float32{3, 4, 6, 7})
+
+	// Take the element-wise maximum of x and y, placing
+	// the result into v.
+	var v mat.VecDense
+	v.Map2(func(a, b float32) float32 {
+		if a > b {
+			return a
+		}
+		return b
+	}, x, y)
+
+	// Print the result using the formatter.
+	fv := mat.Formatted(v.T(), mat.Prefix("    "), mat.Squeeze())
+	fmt.Printf("v = %v", fv)
+
+	// Output:
+	//
+	// v = [3  5  6  8]
+}
@@ -6,8 +6,11 @@ package mat32
 
 import (
 	"reflect"
+	"sync"
 	"testing"
 
+	"github.com/chewxy/math32"
+
 	"golang.org/x/exp/rand"
 
 	"gonum.org/v1/gonum/blas/blas32"
@@ -56,6 +59,81 @@ func TestNewVecDense(t *testing.T) {
 	}
 }
 
+func TestUnsafeVecDense(t *testing.T) {
+	data := []float32{1, 2, 3, 4}
+	v := UnsafeVecDense(data)
+	if v.Len() != len(data) {
+		t.Errorf("unexpected length: got: %d want: %d", v.Len(), len(data))
+	}
+	for i, want := range data {
+		if got := v.AtVec(i); got != want {
+			t.Errorf("unexpected element %d: got: %v want: %v", i, got, want)
+		}
+	}
+
+	// The vector and the slice share storage in both directions.
+	v.SetVec(1, 10)
+	if data[1] != 10 {
+		t.Errorf("change to vector not reflected in slice: got: %v want: 10", data[1])
+	}
+	data[3] = -1
+	if got := v.AtVec(3); got != -1 {
+		t.Errorf("change to slice not reflected in vector: got: %v want: -1", got)
+	}
+	if &v.RawVector().Data[0] != &data[0] {
+		t.Errorf("vector does not alias the input slice")
+	}
+
+	if v := UnsafeVecDense(nil); v.Len() != 0 {
+		t.Errorf("unexpected length for nil slice: got: %d", v.Len())
+	}
+}
+
+func TestOneHot(t *testing.T) {
+	for i, test := range []struct {
+		n, index int
+		want     []float32
+	}{
+		{n: 1, index: 0, want: []float32{1}},
+		{n: 4, index: 0, want: []float32{1, 0, 0, 0}},
+		{n: 4, index: 2, want: []float32{0, 0, 1, 0}},
+		{n: 4, index: 3, want: []float32{0, 0, 0, 1}},
+	} {
+		got := OneHot(test.n, test.index)
+		if !Equal(got, NewVecDense(test.n, test.want)) {
+			t.Errorf("unexpected result for test %d: got: %v want: %v", i, got.RawVector().Data, test.want)
+		}
+	}
+
+	for i, test := range []struct {
+		n       int
+		indices []int
+		want    []float32
+	}{
+		{n: 3, indices: nil, want: []float32{0, 0, 0}},
+		{n: 5, indices: []int{1, 3}, want: []float32{0, 1, 0, 1, 0}},
+		{n: 5, indices: []int{4, 0, 4}, want: []float32{1, 0, 0, 0, 1}},
+		{n: 2, indices: []int{0, 1}, want: []float32{1, 1}},
+	} {
+		got := OneHotMulti(test.n, test.indices)
+		if !Equal(got, NewVecDense(test.n, test.want)) {
+			t.Errorf("unexpected multi-hot result for test %d: got: %v want: %v", i, got.RawVector().Data, test.want)
+		}
+	}
+
+	for i, fn := range []func(){
+		func() { OneHot(3, 3) },
+		func() { OneHot(3, -1) },
+		func() { OneHotMulti(3, []int{0, 3}) },
+		func() { OneHotMulti(3, []int{-1}) },
+	} {
+		panicked, message := panics(fn)
+		if !panicked || message != ErrVectorAccess.Error() {
+			t.Errorf("expected ErrVectorAccess for out of range test %d: got: %q", i, message)
+		}
+	}
+}
+
 func TestCap(t *testing.T) {
 	for i, test := range []struct {
 		vector *VecDense
@@ -412,6 +490,1030 @@ func TestVecDenseDivElem(t *testing.T) {
 	}
 }
 
+func TestVecDenseAddVecMasked(t *testing.T) {
+	for i, test := range []struct {
+		a, b, mask []float32
+		want       []float32
+	}{
+		{
+			a:    []float32{1, 2, 3, 4},
+			b:    []float32{10, 20, 30, 40},
+			mask: []float32{1, 1, 1, 1},
+			want: []float32{11, 22, 33, 44},
+		},
+		{
+			a:    []float32{1, 2, 3, 4},
+			b:    []float32{10, 20, 30, 40},
+			mask: []float32{0, 0, 0, 0},
+			want: []float32{1, 2, 3, 4},
+		},
+		{
+			a:    []float32{1, 2, 3, 4, 5},
+			b:    []float32{10, 20, 30, 40, 50},
+			mask: []float32{1, 0, 1, 0, 0},
+			want: []float32{11, 2, 33, 4, 5},
+		},
+		{
+			// Any non-zero mask value enables the addition.
+			a:    []float32{-1, -1, -1},
+			b:    []float32{1, 2, 3},
+			mask: []float32{-0.5, 0, 2},
+			want: []float32{0, -1, 2},
+		},
+	} {
+		n := len(test.a)
+		a := NewVecDense(n, test.a)
+		b := NewVecDense(n, test.b)
+		mask := NewVecDense(n, test.mask)
+		want := NewVecDense(n, test.want)
+
+		var v VecDense
+		v.AddVecMasked(a, b, mask)
+		if !Equal(&v, want) {
+			t.Errorf("unexpected result for test %d: got: %v want: %v", i, v.RawVector().Data, test.want)
+		}
+
+		var vb VecDense
+		vb.AddVecMasked(&basicVector{test.a}, &basicVector{test.b}, &basicVector{test.mask})
+		if !Equal(&vb, want) {
+			t.Errorf("unexpected non-raw result for test %d: got: %v want: %v", i, vb.RawVector().Data, test.want)
+		}
+
+		// The receiver may alias a.
+		inPlace := NewVecDense(n, append([]float32(nil), test.a...))
+		inPlace.AddVecMasked(inPlace, b, mask)
+		if !Equal(inPlace, want) {
+			t.Errorf("unexpected in-place result for test %d: got: %v want: %v", i, inPlace.RawVector().Data, test.want)
+		}
+	}
+
+	matched, message := panicsWith(func() {
+		var v VecDense
+		v.AddVecMasked(NewVecDense(3, nil), NewVecDense(3, nil), NewVecDense(2, nil))
+	}, ErrShape)
+	if !matched {
+		t.Errorf("expected ErrShape for mismatched mask: got: %q", message)
+	}
+}
+
+func TestVecDenseMap2(t *testing.T) {
+	sub := func(a, b float32) float32 { return a - b }
+	for i, test := range []struct {
+		x, y []float32
+		want []float32
+	}{
+		{
+			x:    []float32{1},
+			y:    []float32{3},
+			want: []float32{-2},
+		},
+		{
+			x:    []float32{1, 2, 3, 4},
+			y:    []float32{4, 3, 2, 1},
+			want: []float32{-3, -1, 1, 3},
+		},
+	} {
+		n := len(test.x)
+		x := NewVecDense(n, test.x)
+		y := NewVecDense(n, test.y)
+		want := NewVecDense(n, test.want)
+
+		var v VecDense
+		v.Map2(sub, x, y)
+		if !Equal(&v, want) {
+			t.Errorf("unexpected result for test %d: got: %v want: %v", i, v.RawVector().Data, test.want)
+		}
+
+		var vb VecDense
+		vb.Map2(sub, &basicVector{test.x}, &basicVector{test.y})
+		if !Equal(&vb, want) {
+			t.Errorf("unexpected non-raw result for test %d: got: %v want: %v", i, vb.RawVector().Data, test.want)
+		}
+
+		// The receiver may alias either operand.
+		inX := NewVecDense(n, append([]float32(nil), test.x...))
+		inX.Map2(sub, inX, y)
+		if !Equal(inX, want) {
+			t.Errorf("unexpected result aliasing x for test %d: got: %v want: %v", i, inX.RawVector().Data, test.want)
+		}
+		inY := NewVecDense(n, append([]float32(nil), test.y...))
+		inY.Map2(sub, x, inY)
+		if !Equal(inY, want) {
+			t.Errorf("unexpected result aliasing y for test %d: got: %v want: %v", i, inY.RawVector().Data, test.want)
+		}
+		both := NewVecDense(n, append([]float32(nil), test.x...))
+		both.Map2(sub, both, both)
+		if !Equal(both, NewVecDense(n, nil)) {
+			t.Errorf("unexpected result aliasing x and y for test %d: got: %v", i, both.RawVector().Data)
+		}
+	}
+
+	matched, message := panicsWith(func() {
+		var v VecDense
+		v.Map2(sub, NewVecDense(3, nil), NewVecDense(2, nil))
+	}, ErrShape)
+	if !matched {
+		t.Errorf("expected ErrShape for mismatched lengths: got: %q", message)
+	}
+
+	panicked, message := panics(func() {
+		data := make([]float32, 4)
+		v := NewVecDense(3, data[1:])
+		v.Map2(sub, NewVecDense(3, data[:3]), NewVecDense(3, nil))
+	})
+	if !panicked || message != regionOverlap {
+		t.Errorf("expected overlap panic for partially aliased operand: got: %q", message)
+	}
+}
+
+func TestVecDenseReduce(t *testing.T) {
+	mul := func(acc, x float32) float32 { return acc * x }
+	v := NewVecDense(4, []float32{1, 2, 3, 4})
+	if got := v.Reduce(1, mul); got != 24 {
+		t.Errorf("unexpected product: got: %v want: %v", got, 24)
+	}
+
+	// The fold is applied left to right, which matters
+	// for a non-associative fn.
+	sub := func(acc, x float32) float32 { return acc - x }
+	if got := v.Reduce(0, sub); got != -10 {
+		t.Errorf("unexpected left fold: got: %v want: %v", got, -10)
+	}
+	digits := func(acc, x float32) float32 { return 10*acc + x }
+	if got := v.Reduce(0, digits); got != 1234 {
+		t.Errorf("unexpected fold order: got: %v want: %v", got, 1234)
+	}
+
+	// Strided receivers visit only their own elements.
+	col := NewDense(4, 2, []float32{1, -1, 2, -1, 3, -1, 4, -1}).ColView(0).(*VecDense)
+	if got := col.Reduce(0, digits); got != 1234 {
+		t.Errorf("unexpected strided fold: got: %v want: %v", got, 1234)
+	}
+
+	var empty VecDense
+	if got := empty.Reduce(7, mul); got != 7 {
+		t.Errorf("unexpected result for empty receiver: got: %v want: %v", got, 7)
+	}
+}
+
+func TestVecDenseAbsVec(t *testing.T) {
+	nan := math32.NaN()
+	negZero := math32.Copysign(0, -1)
+	data := []float32{-1, 2, negZero, nan, -3.5, math32.Inf(-1)}
+	want := []float32{1, 2, 0, nan, 3.5, math32.Inf(1)}
+	check := func(name string, got Vector) {
+		for i, w := range want {
+			v := got.AtVec(i)
+			if math32.IsNaN(w) {
+				if !math32.IsNaN(v) {
+					t.Errorf("unexpected %s result at %d: got: %v want: NaN", name, i, v)
+				}
+				continue
+			}
+			if v != w || math32.Signbit(v) {
+				t.Errorf("unexpected %s result at %d: got: %v want: %v", name, i, v, w)
+			}
+		}
+	}
+
+	var v VecDense
+	v.AbsVec(NewVecDense(len(data), data))
+	check("AbsVec", &v)
+
+	var vb VecDense
+	vb.AbsVec(&basicVector{data})
+	check("non-raw AbsVec", &vb)
+
+	strided := make([]float32, 2*len(data))
+	for i, d := range data {
+		strided[2*i] = d
+		strided[2*i+1] = -7
+	}
+	col := NewDense(len(data), 2, strided).ColView(0).(*VecDense)
+	col.AbsVec(col)
+	check("in-place strided AbsVec", col)
+	for i := 1; i < len(strided); i += 2 {
+		if strided[i] != -7 {
+			t.Errorf("AbsVec modified element outside the vector at %d: got: %v", i, strided[i])
+		}
+	}
+}
+
+func TestVecDenseSignVec(t *testing.T) {
+	nan := math32.NaN()
+	data := []float32{3, -0.5, 0, math32.Copysign(0, -1), nan, math32.Inf(-1)}
+	want := []float32{1, -1, 0, 0, nan, -1}
+	for _, test := range []struct {
+		name string
+		fn   func() Vector
+	}{
+		{name: "raw", fn: func() Vector {
+			var v VecDense
+			v.SignVec(NewVecDense(len(data), data))
+			return &v
+		}},
+		{name: "non-raw", fn: func() Vector {
+			var v VecDense
+			v.SignVec(&basicVector{data})
+			return &v
+		}},
+		{name: "in-place", fn: func() Vector {
+			v := NewVecDense(len(data), append([]float32(nil), data...))
+			v.SignVec(v)
+			return v
+		}},
+	} {
+		got := test.fn()
+		for i, w := range want {
+			v := got.AtVec(i)
+			if math32.IsNaN(w) {
+				if !math32.IsNaN(v) {
+					t.Errorf("unexpected %s result at %d: got: %v want: NaN", test.name, i, v)
+				}
+				continue
+			}
+			if v != w || math32.Signbit(v) != math32.Signbit(w) {
+				t.Errorf("unexpected %s result at %d: got: %v want: %v", test.name, i, v, w)
+			}
+		}
+	}
+}
+
+func TestVecDenseRoundFloorCeil(t *testing.T) {
+	nan := math32.NaN()
+	data := []float32{-2.5, -1.5, -0.5, 0.5, 1.5, 2.5, 3.5, 0.49999997, 1.25, -1.75, 16777215, nan}
+	for _, test := range []struct {
+		name string
+		fn   func(v *VecDense, a Vector)
+		want []float32
+	}{
+		{
+			name: "RoundVec",
+			fn:   (*VecDense).RoundVec,
+			want: []float32{-2, -2, 0, 0, 2, 2, 4, 0, 1, -2, 16777215, nan},
+		},
+		{
+			name: "FloorVec",
+			fn:   (*VecDense).FloorVec,
+			want: []float32{-3, -2, -1, 0, 1, 2, 3, 0, 1, -2, 16777215, nan},
+		},
+		{
+			name: "CeilVec",
+			fn:   (*VecDense).CeilVec,
+			want: []float32{-2, -1, 0, 1, 2, 3, 4, 1, 2, -1, 16777215, nan},
+		},
+	} {
+		var v VecDense
+		test.fn(&v, NewVecDense(len(data), data))
+
+		var vb VecDense
+		test.fn(&vb, &basicVector{data})
+
+		inPlace := NewVecDense(len(data), append([]float32(nil), data...))
+		test.fn(inPlace, inPlace)
+
+		for _, got := range []*VecDense{&v, &vb, inPlace} {
+			for i, w := range test.want {
+				g := got.AtVec(i)
+				if math32.IsNaN(w) {
+					if !math32.IsNaN(g) {
+						t.Errorf("unexpected %s result for %v: got: %v want: NaN", test.name, data[i], g)
+					}
+					continue
+				}
+				if g != w {
+					t.Errorf("unexpected %s result for %v: got: %v want: %v", test.name, data[i], g, w)
+				}
+			}
+		}
+	}
+}
+
+func TestVecDenseRollVec(t *testing.T) {
+	for i, test := range []struct {
+		a     []float32
+		shift int
+		want  []float32
+	}{
+		{a: []float32{1, 2, 3, 4, 5}, shift: 0, want: []float32{1, 2, 3, 4, 5}},
+		{a: []float32{1, 2, 3, 4, 5}, shift: 1, want: []float32{5, 1, 2, 3, 4}},
+		{a: []float32{1, 2, 3, 4, 5}, shift: 2, want: []float32{4, 5, 1, 2, 3}},
+		{a: []float32{1, 2, 3, 4, 5}, shift: -1, want: []float32{2, 3, 4, 5, 1}},
+		{a: []float32{1, 2, 3, 4, 5}, shift: -3, want: []float32{4, 5, 1, 2, 3}},
+		{a: []float32{1, 2, 3, 4, 5}, shift: 5, want: []float32{1, 2, 3, 4, 5}},
+		{a: []float32{1, 2, 3, 4, 5}, shift: 12, want: []float32{4, 5, 1, 2, 3}},
+		{a: []float32{1, 2, 3, 4, 5}, shift: -11, want: []float32{2, 3, 4, 5, 1}},
+		{a: []float32{1, 2, 3, 4, 5, 6}, shift: 3, want: []float32{4, 5, 6, 1, 2, 3}},
+		{a: []float32{7}, shift: -4, want: []float32{7}},
+	} {
+		n := len(test.a)
+		want := NewVecDense(n, test.want)
+
+		var v VecDense
+		v.RollVec(NewVecDense(n, test.a), test.shift)
+		if !Equal(&v, want) {
+			t.Errorf("unexpected result for test %d: got: %v want: %v", i, v.RawVector().Data, test.want)
+		}
+
+		var vb VecDense
+		vb.RollVec(&basicVector{test.a}, test.shift)
+		if !Equal(&vb, want) {
+			t.Errorf("unexpected non-raw result for test %d: got: %v want: %v", i, vb.RawVector().Data, test.want)
+		}
+
+		// In-place on a strided view must leave the
+		// interleaved elements untouched.
+		data := make([]float32, 2*n)
+		for j, x := range test.a {
+			data[2*j] = x
+			data[2*j+1] = -1
+		}
+		col := NewDense(n, 2, data).ColView(0).(*VecDense)
+		col.RollVec(col, test.shift)
+		if !Equal(col, want) {
+			t.Errorf("unexpected in-place result for test %d: got: %v want: %v", i, data, test.want)
+		}
+		for j := 1; j < len(data); j += 2 {
+			if data[j] != -1 {
+				t.Errorf("in-place roll modified element outside the vector for test %d", i)
+				break
+			}
+		}
+	}
+
+	panicked, message := panics(func() {
+		data := []float32{1, 2, 3, 4}
+		v := NewVecDense(3, data[1:])
+		v.RollVec(NewVecDense(3, data[:3]), 1)
+	})
+	if !panicked || message != regionOverlap {
+		t.Errorf("expected overlap panic for partially aliased operand: got: %q", message)
+	}
+}
+
+func TestVecDenseNormSafe(t *testing.T) {
+	naive := func(v []float32) float32 {
+		var sum float32
+		for _, x := range v {
+			sum += x * x
+		}
+		return math32.Sqrt(sum)
+	}
+	// EqualWithinRel considers infinity close to any large value.
+	close := func(a, b float32) bool {
+		return !math32.IsInf(a, 0) && EqualWithinRel(a, b, 1e-6)
+	}
+	denorm := float32(math32.SmallestNonzeroFloat32 * 4)
+	for i, test := range []struct {
+		data []float32
+		want float32
+		// naiveOK is whether the naive norm is expected
+		// to be correct for the data.
+		naiveOK bool
+	}{
+		{data: []float32{3, 4}, want: 5, naiveOK: true},
+		{data: []float32{-1, 2, -2}, want: 3, naiveOK: true},
+		{data: []float32{0, 0, 0}, want: 0, naiveOK: true},
+		{
+			// The square of the first element overflows.
+			data: []float32{math32.MaxFloat32 / 2, 1},
+			want: math32.MaxFloat32 / 2,
+		},
+		{
+			data: []float32{3e30, 4e30},
+			want: 5e30,
+		},
+		{
+			// The squares of subnormal values underflow to zero.
+			data: []float32{denorm, denorm, denorm, denorm},
+			want: 2 * denorm,
+		},
+		{
+			data: []float32{3e-30, -4e-30},
+			want: 5e-30,
+		},
+	} {
+		v := NewVecDense(len(test.data), test.data)
+		got := v.NormSafe()
+		if !close(got, test.want) {
+			t.Errorf("unexpected norm for test %d: got: %v want: %v", i, got, test.want)
+		}
+		if n := naive(test.data); close(n, test.want) != test.naiveOK {
+			t.Errorf("unexpected naive norm for test %d: got: %v want: %v", i, n, test.want)
+		}
+
+		// Strided receivers give the same result.
+		strided := make([]float32, 3*len(test.data))
+		for j, x := range test.data {
+			strided[3*j] = x
+			strided[3*j+1] = math32.MaxFloat32
+		}
+		col := NewDense(len(test.data), 3, strided).ColView(0).(*VecDense)
+		if got := col.NormSafe(); !close(got, test.want) {
+			t.Errorf("unexpected strided norm for test %d: got: %v want: %v", i, got, test.want)
+		}
+	}
+
+	v := NewVecDense(3, []float32{1, math32.NaN(), 2})
+	if got := v.NormSafe(); !math32.IsNaN(got) {
+		t.Errorf("unexpected norm with NaN element: got: %v want: NaN", got)
+	}
+	var empty VecDense
+	if got := empty.NormSafe(); got != 0 {
+		t.Errorf("unexpected norm for empty vector: got: %v want: 0", got)
+	}
+}
+
+func TestVecDenseKey(t *testing.T) {
+	a := NewVecDense(3, []float32{1, 2, 3})
+	b := NewVecDense(3, []float32{1, 2, 3})
+	strided := NewDense(3, 2, []float32{1, -1, 2, -1, 3, -1}).ColView(0).(*VecDense)
+	if a.Key() != b.Key() {
+		t.Errorf("equal vectors have different keys")
+	}
+	if a.Key() != strided.Key() {
+		t.Errorf("equal strided vector has a different key")
+	}
+
+	for i, other := range []*VecDense{
+		NewVecDense(3, []float32{1, 2, 4}),
+		NewVecDense(3, []float32{3, 2, 1}),
+		NewVecDense(2, []float32{1, 2}),
+		NewVecDense(4, []float32{1, 2, 3, 0}),
+		NewVecDense(3, []float32{1, 2, math32.Nextafter(3, 4)}),
+	} {
+		if a.Key() == other.Key() {
+			t.Errorf("different vector %d has the same key", i)
+		}
+	}
+
+	if NewVecDense(1, []float32{0}).Key() == NewVecDense(1, []float32{math32.Copysign(0, -1)}).Key() {
+		t.Errorf("expected +0 and -0 to have different keys")
+	}
+
+	// Keys may be used to deduplicate vectors.
+	seen := make(map[string]bool)
+	for _, v := range []*VecDense{a, b, strided, NewVecDense(3, []float32{1, 2, 4})} {
+		seen[v.Key()] = true
+	}
+	if len(seen) != 2 {
+		t.Errorf("unexpected number of distinct keys: got: %d want: 2", len(seen))
+	}
+}
+
+func TestGemv(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		r, c        int
+		alpha, beta float32
+	}{
+		{r: 1, c: 1, alpha: 1, beta: 0},
+		{r: 3, c: 4, alpha: 1, beta: 0},
+		{r: 4, c: 3, alpha: 2, beta: 0},
+		{r: 5, c: 5, alpha: -0.5, beta: 1},
+		{r: 3, c: 7, alpha: 1.5, beta: -2},
+	} {
+		a := randNormDense(test.r, test.c, rnd)
+		for _, trans := range []bool{false, true} {
+			r, c := test.r, test.c
+			if trans {
+				r, c = c, r
+			}
+			x := NewVecDense(c, randSlice(c, rnd))
+			y0 := randSlice(r, rnd)
+
+			// Reference result by element.
+			want := make([]float32, r)
+			for i := range want {
+				var f float32
+				for j := 0; j < c; j++ {
+					if trans {
+						f += a.At(j, i) * x.AtVec(j)
+					} else {
+						f += a.At(i, j) * x.AtVec(j)
+					}
+				}
+				want[i] = test.alpha*f + test.beta*y0[i]
+			}
+
+			for _, args := range []struct {
+				name  string
+				a     Matrix
+				trans bool
+				x     Vector
+			}{
+				{name: "raw", a: a, trans: trans, x: x},
+				{name: "transposed operand", a: a.T(), trans: !trans, x: x},
+				{name: "non-raw matrix", a: asBasicMatrix(a), trans: trans, x: x},
+				{name: "non-raw vector", a: a, trans: trans, x: &basicVector{x.RawVector().Data}},
+			} {
+				y := NewVecDense(r, append([]float32(nil), y0...))
+				Gemv(args.trans, test.alpha, args.a, args.x, test.beta, y)
+				if !EqualApprox(y, NewVecDense(r, want), 1e-5) {
+					t.Errorf("unexpected %s result for %d×%d trans=%t alpha=%v beta=%v: got: %v want: %v",
+						args.name, test.r, test.c, trans, test.alpha, test.beta, y.RawVector().Data, want)
+				}
+			}
+
+			// An empty y is treated as zero.
+			var y VecDense
+			Gemv(trans, test.alpha, a, x, test.beta, &y)
+			for i := range want {
+				want[i] -= test.beta * y0[i]
+			}
+			if !EqualApprox(&y, NewVecDense(r, want), 1e-5) {
+				t.Errorf("unexpected result with empty y for %d×%d trans=%t: got: %v want: %v",
+					test.r, test.c, trans, y.RawVector().Data, want)
+			}
+		}
+	}
+
+	// The vector y may be x, for both raw and non-raw a, and may share
+	// its storage with x.
+	sq := NewDense(3, 3, []float32{
+		1, 2, 0,
+		0, 1, -1,
+		3, 0, 2,
+	})
+	x0 := []float32{1, 2, 3}
+	for _, trans := range []bool{false, true} {
+		want := NewVecDense(3, append([]float32(nil), x0...))
+		Gemv(trans, 2, sq, NewVecDense(3, x0), 0.5, want)
+		for _, test := range []struct {
+			name string
+			a    Matrix
+			view bool
+		}{
+			{name: "raw", a: sq},
+			{name: "non-raw", a: asBasicMatrix(sq)},
+			{name: "raw shared storage", a: sq, view: true},
+		} {
+			y := NewVecDense(3, append([]float32(nil), x0...))
+			var x Vector = y
+			if test.view {
+				x = y.SliceVec(0, 3)
+			}
+			Gemv(trans, 2, test.a, x, 0.5, y)
+			if !EqualApprox(y, want, 1e-6) {
+				t.Errorf("unexpected %s result for y aliasing x with trans=%t: got: %v want: %v",
+					test.name, trans, y.RawVector().Data, want.RawVector().Data)
+			}
+		}
+	}
+
+	// With beta zero, NaN elements of y are not propagated.
+	y := NewVecDense(2, []float32{math32.NaN(), math32.NaN()})
+	Gemv(false, 1, NewDense(2, 2, []float32{1, 2, 3, 4}), NewVecDense(2, []float32{1, 1}), 0, y)
+	if !Equal(y, NewVecDense(2, []float32{3, 7})) {
+		t.Errorf("unexpected result with beta zero and NaN y: got: %v", y.RawVector().Data)
+	}
+
+	for i, fn := range []func(){
+		func() { Gemv(false, 1, NewDense(2, 3, nil), NewVecDense(2, nil), 0, &VecDense{}) },
+		func() { Gemv(true, 1, NewDense(2, 3, nil), NewVecDense(3, nil), 0, &VecDense{}) },
+		func() { Gemv(false, 1, NewDense(2, 3, nil), NewVecDense(3, nil), 0, NewVecDense(3, nil)) },
+	} {
+		matched, message := panicsWith(fn, ErrShape)
+		if !matched {
+			t.Errorf("expected shape panic for test %d: got: %q", i, message)
+		}
+	}
+}
+
+func TestAxpy(t *testing.T) {
+	for i, test := range []struct {
+		alpha float32
+		x, y  []float32
+		want  []float32
+	}{
+		{alpha: 1, x: nil, y: nil, want: nil},
+		{alpha: 2, x: []float32{1}, y: []float32{3}, want: []float32{5}},
+		{alpha: 0, x: []float32{1, 2, 3}, y: []float32{4, 5, 6}, want: []float32{4, 5, 6}},
+		{alpha: -1, x: []float32{1, 2, 3}, y: []float32{4, 5, 6}, want: []float32{3, 3, 3}},
+		{alpha: 0.5, x: []float32{2, 4, 6, 8, 10}, y: []float32{1, 1, 1, 1, 1}, want: []float32{2, 3, 4, 5, 6}},
+	} {
+		y := append([]float32(nil), test.y...)
+		Axpy(test.alpha, test.x, y)
+		if !reflect.DeepEqual(y, test.want) {
+			t.Errorf("unexpected result for test %d: got: %v want: %v", i, y, test.want)
+		}
+	}
+
+	// Axpy agrees with AddScaledVec.
+	rnd := rand.New(rand.NewSource(1))
+	x := randSlice(100, rnd)
+	y := randSlice(100, rnd)
+	var want VecDense
+	want.AddScaledVec(NewVecDense(100, y), 1.5, NewVecDense(100, x))
+	Axpy(1.5, x, y)
+	if !Equal(NewVecDense(100, y), &want) {
+		t.Errorf("unexpected result compared to AddScaledVec: got: %v want: %v", y, want.RawVector().Data)
+	}
+
+	matched, message := panicsWith(func() { Axpy(1, make([]float32, 2), make([]float32, 3)) }, ErrShape)
+	if !matched {
+		t.Errorf("expected shape panic for mismatched lengths: got: %q", message)
+	}
+}
+
+func TestVecDenseDot(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 3, 17, 100} {
+		ad := make([]float32, n)
+		bd := make([]float32, n)
+		for i := range ad {
+			ad[i] = float32(rnd.NormFloat64())
+			bd[i] = float32(rnd.NormFloat64())
+		}
+		a := NewVecDense(n, ad)
+		strided := NewDense(n, 2, nil)
+		for i, v := range bd {
+			strided.Set(i, 1, v)
+		}
+		for _, b := range []Vector{NewVecDense(n, bd), strided.ColView(1), &basicVector{bd}} {
+			got := a.Dot(b)
+			want := Dot(a, b)
+			if !EqualWithinAbsOrRel(got, want, 1e-6, 1e-6) {
+				t.Errorf("unexpected dot for n=%d with %T: got: %v want: %v", n, b, got, want)
+			}
+		}
+	}
+
+	if got := NewVecDense(3, []float32{1, 2, 3}).Dot(NewVecDense(3, []float32{4, -5, 6})); got != 12 {
+		t.Errorf("unexpected dot: got: %v want: 12", got)
+	}
+
+	matched, message := panicsWith(func() { NewVecDense(3, nil).Dot(NewVecDense(2, nil)) }, ErrShape)
+	if !matched {
+		t.Errorf("expected shape panic: got: %q", message)
+	}
+}
+
+func TestVecDenseDistance(t *testing.T) {
+	a := NewVecDense(3, []float32{1, 2, 3})
+	b := NewVecDense(3, []float32{4, 0, 3})
+	for _, test := range []struct {
+		metric Metric
+		want   float32
+	}{
+		{metric: L2, want: math32.Sqrt(13)},
+		{metric: SquaredL2, want: 13},
+		{metric: L1, want: 5},
+		{metric: Cosine, want: 1 - 13/(math32.Sqrt(14)*5)},
+		{metric: InnerProduct, want: -13},
+		{metric: Chebyshev, want: 3},
+	} {
+		got := a.Distance(b, test.metric)
+		if !EqualWithinAbsOrRel(got, test.want, 1e-6, 1e-6) {
+			t.Errorf("unexpected distance for metric %d: got: %v want: %v", test.metric, got, test.want)
+		}
+		want, _ := Distance(test.metric, a, &basicVector{[]float32{4, 0, 3}})
+		if !EqualWithinAbsOrRel(got, want, 1e-6, 1e-6) {
+			t.Errorf("mismatch with Distance for metric %d: got: %v want: %v", test.metric, got, want)
+		}
+	}
+
+	matched, message := panicsWith(func() { a.Distance(NewVecDense(2, nil), L2) }, ErrShape)
+	if !matched {
+		t.Errorf("expected shape panic: got: %q", message)
+	}
+	panicked, message := panics(func() { a.Distance(b, 0) })
+	if !panicked || message != ErrMetric.Error() {
+		t.Errorf("expected metric panic: got: %q", message)
+	}
+}
+
+func TestVecDenseDotMatrix(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		r, c int
+	}{
+		{1, 1},
+		{3, 5},
+		{17, 8},
+		{100, 33},
+	} {
+		m := NewDense(test.r, test.c, nil)
+		for i := range m.mat.Data {
+			m.mat.Data[i] = float32(rnd.NormFloat64())
+		}
+		v := NewVecDense(test.c, nil)
+		for i := 0; i < test.c; i++ {
+			v.SetVec(i, float32(rnd.NormFloat64()))
+		}
+		for _, mat := range []*Dense{m, NewDense(test.r+2, test.c+3, nil).Slice(1, test.r+1, 2, test.c+2).(*Dense)} {
+			if mat != m {
+				mat.Copy(m)
+			}
+			got := make([]float32, test.r)
+			for i := range got {
+				got[i] = math32.NaN()
+			}
+			v.DotMatrix(got, mat)
+			for i, d := range got {
+				want := Dot(v, mat.RowView(i))
+				if !EqualWithinAbsOrRel(d, want, 1e-5, 1e-5) {
+					t.Errorf("unexpected dot for %d×%d row %d: got: %v want: %v", test.r, test.c, i, d, want)
+				}
+			}
+		}
+	}
+
+	for _, fn := range []func(){
+		func() { NewVecDense(3, nil).DotMatrix(make([]float32, 2), NewDense(2, 4, nil)) },
+		func() { NewVecDense(4, nil).DotMatrix(make([]float32, 3), NewDense(2, 4, nil)) },
+	} {
+		if panicked, _ := panics(fn); !panicked {
+			t.Errorf("expected panic for mismatched dimensions")
+		}
+	}
+}
+
+func TestVecDenseGather(t *testing.T) {
+	src := []float32{10, 11, 12, 13, 14}
+	for i, test := range []struct {
+		v       *VecDense
+		indices []int
+		want    []float32
+	}{
+		{v: NewVecDense(5, src), indices: []int{0, 1, 2, 3, 4}, want: src},
+		{v: NewVecDense(5, src), indices: []int{4, 0, 3}, want: []float32{14, 10, 13}},
+		{v: NewVecDense(5, src), indices: []int{2, 2, 1, 2}, want: []float32{12, 12, 11, 12}},
+		{
+			// Strided source.
+			v:       NewDense(5, 2, []float32{10, 0, 11, 0, 12, 0, 13, 0, 14, 0}).ColView(0).(*VecDense),
+			indices: []int{3, 1, 3},
+			want:    []float32{13, 11, 13},
+		},
+	} {
+		var dst VecDense
+		test.v.Gather(&dst, test.indices)
+		if !Equal(&dst, NewVecDense(len(test.want), test.want)) {
+			t.Errorf("unexpected gather for test %d: got: %v want: %v", i, dst.RawVector().Data, test.want)
+		}
+
+		// Gathering into a strided destination.
+		d := NewDense(len(test.indices), 2, nil)
+		test.v.Gather(d.ColView(1).(*VecDense), test.indices)
+		for k, w := range test.want {
+			if d.At(k, 1) != w || d.At(k, 0) != 0 {
+				t.Errorf("unexpected strided gather for test %d: got:\n%v", i, Formatted(d))
+				break
+			}
+		}
+	}
+
+	// In-place permutation.
+	v := NewVecDense(3, []float32{1, 2, 3})
+	v.Gather(v, []int{2, 0, 1})
+	if !Equal(v, NewVecDense(3, []float32{3, 1, 2})) {
+		t.Errorf("unexpected in-place gather: got: %v", v.RawVector().Data)
+	}
+
+	for _, idx := range []int{-1, 5} {
+		panicked, message := panics(func() {
+			var dst VecDense
+			NewVecDense(5, src).Gather(&dst, []int{0, idx})
+		})
+		if !panicked || message != ErrVectorAccess.Error() {
+			t.Errorf("expected ErrVectorAccess for index %d: got: %q", idx, message)
+		}
+	}
+}
+
+func TestVecDenseScatter(t *testing.T) {
+	for i, test := range []struct {
+		v       []float32
+		indices []int
+		src     []float32
+		set     []float32
+		add     []float32
+	}{
+		{
+			v:       []float32{0, 0, 0, 0},
+			indices: []int{3, 1},
+			src:     []float32{5, 7},
+			set:     []float32{0, 7, 0, 5},
+			add:     []float32{0, 7, 0, 5},
+		},
+		{
+			// Repeated index: overwrite keeps the last value,
+			// accumulate sums all contributions.
+			v:       []float32{1, 1, 1},
+			indices: []int{2, 0, 2, 2},
+			src:     []float32{1, 2, 3, 4},
+			set:     []float32{2, 1, 4},
+			add:     []float32{3, 1, 9},
+		},
+	} {
+		n := len(test.v)
+		src := NewVecDense(len(test.src), test.src)
+
+		v := NewVecDense(n, append([]float32(nil), test.v...))
+		v.Scatter(test.indices, src)
+		if !Equal(v, NewVecDense(n, test.set)) {
+			t.Errorf("unexpected Scatter result for test %d: got: %v want: %v", i, v.RawVector().Data, test.set)
+		}
+
+		v = NewVecDense(n, append([]float32(nil), test.v...))
+		v.ScatterAdd(test.indices, src)
+		if !Equal(v, NewVecDense(n, test.add)) {
+			t.Errorf("unexpected ScatterAdd result for test %d: got: %v want: %v", i, v.RawVector().Data, test.add)
+		}
+
+		// Strided receiver.
+		d := NewDense(n, 2, nil)
+		d.SetCol(0, test.v)
+		col := d.ColView(0).(*VecDense)
+		col.ScatterAdd(test.indices, &basicVector{test.src})
+		for k, w := range test.add {
+			if d.At(k, 0) != w || d.At(k, 1) != 0 {
+				t.Errorf("unexpected strided ScatterAdd for test %d: got:\n%v", i, Formatted(d))
+				break
+			}
+		}
+	}
+
+	// In-place reversal.
+	v := NewVecDense(3, []float32{1, 2, 3})
+	v.Scatter([]int{2, 1, 0}, v)
+	if !Equal(v, NewVecDense(3, []float32{3, 2, 1})) {
+		t.Errorf("unexpected in-place scatter: got: %v", v.RawVector().Data)
+	}
+
+	for _, test := range []struct {
+		name  string
+		fn    func()
+		panic error
+	}{
+		{name: "length", fn: func() { NewVecDense(3, nil).Scatter([]int{0}, NewVecDense(2, nil)) }, panic: ErrShape},
+		{name: "index", fn: func() { NewVecDense(3, nil).ScatterAdd([]int{0, 3}, NewVecDense(2, nil)) }, panic: ErrVectorAccess},
+	} {
+		matched, message := panicsWith(test.fn, test.panic)
+		if !matched {
+			t.Errorf("expected %v panic for %s: got: %q", test.panic, test.name, message)
+		}
+	}
+}
+
+func TestVecDenseDivElemSafe(t *testing.T) {
+	for i, test := range []struct {
+		a, b   Vector
+		onZero float32
+		want   *VecDense
+	}{
+		{
+			a:      NewVecDense(3, []float32{0.5, 1, 2}),
+			b:      NewVecDense(3, []float32{0.5, 0.5, 1}),
+			onZero: -1,
+			want:   NewVecDense(3, []float32{1, 2, 2}),
+		},
+		{
+			a:      NewVecDense(4, []float32{0.5, 1, 2, 0}),
+			b:      NewVecDense(4, []float32{0, 0.5, 0, 0}),
+			onZero: -1,
+			want:   NewVecDense(4, []float32{-1, 2, -1, -1}),
+		},
+		{
+			a:      NewDense(3, 2, []float32{3, 0, 1, 0, 2, 0}).ColView(0),
+			b:      NewDense(3, 1, []float32{0.5, 0, 1}).ColView(0),
+			onZero: 0,
+			want:   NewVecDense(3, []float32{6, 0, 2}),
+		},
+		{
+			a:      &basicVector{m: []float32{3, 1, 2}},
+			b:      NewVecDense(3, []float32{0.5, 0, 1}),
+			onZero: 7,
+			want:   NewVecDense(3, []float32{6, 7, 2}),
+		},
+	} {
+		var v VecDense
+		v.DivElemVecSafe(test.a, test.b, test.onZero)
+		if !Equal(&v, test.want) {
+			t.Errorf("unexpected result for test %d: got: %v want: %v", i, v.RawVector(), test.want.RawVector())
+		}
+	}
+
+	// Nonzero denominators must agree with DivElemVec.
+	a := NewVecDense(4, []float32{1, -2, 3.5, 7})
+	b := NewVecDense(4, []float32{3, 0.25, -7, 9})
+	var got, want VecDense
+	got.DivElemVecSafe(a, b, 0)
+	want.DivElemVec(a, b)
+	if !Equal(&got, &want) {
+		t.Errorf("unexpected mismatch with DivElemVec: got: %v want: %v", got.RawVector(), want.RawVector())
+	}
+}
+
+func TestVecDenseConcurrentRead(t *testing.T) {
+	const (
+		rows    = 200
+		cols    = 16
+		workers = 8
+	)
+	rnd := rand.New(rand.NewSource(1))
+	db := NewDense(rows, cols, nil)
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			db.Set(i, j, rnd.Float32())
+		}
+	}
+	proj := NewDense(cols, cols, nil)
+	for i := 0; i < cols; i++ {
+		for j := 0; j < cols; j++ {
+			proj.Set(i, j, rnd.Float32())
+		}
+	}
+	queries := make([]*VecDense, workers)
+	for i := range queries {
+		queries[i] = NewVecDense(cols, nil)
+		for j := 0; j < cols; j++ {
+			queries[i].SetVec(j, rnd.Float32())
+		}
+	}
+
+	distances := func(q *VecDense) []float32 {
+		// Project the query in place to exercise the shared workspace pools.
+		p := VecDenseCopyOf(q)
+		p.MulVec(proj, p)
+		dists := make([]float32, rows)
+		var diff VecDense
+		for i := range dists {
+			diff.SubVec(p, db.RowView(i))
+			dists[i] = math32.Sqrt(Dot(&diff, &diff))
+		}
+		return dists
+	}
+
+	want := make([][]float32, workers)
+	for i, q := range queries {
+		want[i] = distances(q)
+	}
+
+	got := make([][]float32, workers)
+	var wg sync.WaitGroup
+	for i := range queries {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			got[i] = distances(queries[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("unexpected concurrent distances for query %d: got: %v want: %v", i, got[i], want[i])
+		}
+	}
+}
+
+func BenchmarkDotMatrix10000x256(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	m := NewDense(10000, 256, nil)
+	for i := range m.mat.Data {
+		m.mat.Data[i] = float32(rnd.NormFloat64())
+	}
+	v := NewVecDense(256, nil)
+	for i := 0; i < 256; i++ {
+		v.SetVec(i, float32(rnd.NormFloat64()))
+	}
+	dst := make([]float32, 10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.DotMatrix(dst, m)
+	}
+}
+
+func BenchmarkDotMatrixPerRow10000x256(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	m := NewDense(10000, 256, nil)
+	for i := range m.mat.Data {
+		m.mat.Data[i] = float32(rnd.NormFloat64())
+	}
+	v := NewVecDense(256, nil)
+	for i := 0; i < 256; i++ {
+		v.SetVec(i, float32(rnd.NormFloat64()))
+	}
+	dst := make([]float32, 10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range dst {
+			dst[j] = Dot(v, m.RowView(j))
+		}
+	}
+}
+
+func BenchmarkAxpy1000(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	x := randSlice(1000, rnd)
+	y := randSlice(1000, rnd)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Axpy(1e-6, x, y)
+	}
+}
+
 func BenchmarkAddScaledVec10Inc1(b *testing.B)      { addScaledVecBench(b, 10, 1) }
 func BenchmarkAddScaledVec100Inc1(b *testing.B)     { addScaledVecBench(b, 100, 1) }
 func BenchmarkAddScaledVec1000Inc1(b *testing.B)    { addScaledVecBench(b, 1000, 1) }